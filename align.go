@@ -0,0 +1,94 @@
+package gopdf
+
+import "strings"
+
+// Alignment controls how text is positioned horizontally
+type Alignment int
+
+// Text alignments
+const (
+	AlignLeft Alignment = iota
+	AlignCenter
+	AlignRight
+	AlignJustify
+)
+
+// PrintAligned outputs text aligned between the margins, or in the current column, on the current line and moves the
+// cursor to the start of the next line. Each line of text containing newlines is aligned separately. Justified text
+// is stretched to fill the line by adjusting the space between words.
+func (p *PdfPage) PrintAligned(text string, align Alignment) {
+	for _, line := range splitLines(text) {
+		p = p.flow()
+		p.outputAligned(line, p.lineLeft(), p.lineRight()-p.lineLeft(), align)
+		p.newLine()
+	}
+}
+
+// PrintAlignedAt outputs text on the current line aligned within the box starting at x that is width wide.
+// This is useful for table cells. The cursor is not moved.
+func (p *PdfPage) PrintAlignedAt(text string, x, width float64, align Alignment) {
+	p.outputAligned(text, p.pt(x), p.pt(width), align)
+}
+
+// WriteWrappedAligned outputs text broken into lines as for WriteWrapped, with each line aligned between the
+// margins. When justifying, the last line of the text is left aligned.
+func (p *PdfPage) WriteWrappedAligned(text string, align Alignment) {
+	p = p.flow()
+	width := p.lineRight() - p.lineLeft()
+	lines := p.wrapText(text, width, width)
+	for i, line := range lines {
+		if align == AlignJustify && i == len(lines)-1 {
+			align = AlignLeft
+		}
+		p.PrintAligned(line, align)
+	}
+}
+
+// outputAligned outputs text at the current y position aligned within the box starting at x
+func (p *PdfPage) outputAligned(text string, x, width float64, align Alignment) {
+	textWidth := p.textWidth(text)
+	switch align {
+	case AlignCenter:
+		x += (width - textWidth) / 2
+	case AlignRight:
+		x += width - textWidth
+	case AlignJustify:
+		spaces := strings.Count(text, " ")
+		if spaces == 0 || textWidth >= width {
+			break
+		}
+		// the extra space is stretched by the horizontal scaling along with the text
+		extra := (width - textWidth) / float64(spaces) * 100 / p.horizontalScaling
+		if p.font != nil && p.font.unicode != nil {
+			p.outputJustifiedGlyphs(text, x, extra)
+			p.decorateText(x, width)
+			return
+		}
+		// widen the spaces while the text is output so that it is measured at its justified width
+		wordSpacing := p.wordSpacing
+		p.wordSpacing += extra
+		p.content.addTextf("%v Tw\r\n", formatNumber(p.wordSpacing))
+		p.outputTextAt(text, x)
+		p.wordSpacing = wordSpacing
+		p.content.addTextf("%v Tw\r\n", formatNumber(p.wordSpacing))
+		return
+	}
+	p.outputTextAt(text, x)
+}
+
+// outputJustifiedGlyphs outputs text in a Type0 font with extra points added after each space. Word spacing only
+// applies to single byte codes so the spaces are adjusted individually with TJ instead.
+func (p *PdfPage) outputJustifiedGlyphs(text string, x, extra float64) {
+	adjust := formatNumber(-extra * 1000 / p.fontSize)
+	words := strings.Split(text, " ")
+	var sb strings.Builder
+	for i, word := range words {
+		if i < len(words)-1 {
+			sb.WriteString(p.textString(word+" ") + " " + adjust + " ")
+		} else {
+			sb.WriteString(p.textString(word))
+		}
+	}
+	p.content.addTextf("1 0 0 1 %v %v Tm\r\n[ %s ] TJ\r\n", formatNumber(x), formatNumber(p.y), sb.String())
+	p.measureBaseline()
+}
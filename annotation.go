@@ -0,0 +1,220 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// annotation is an object listed in the /Annots of a page
+type annotation interface {
+	PdfObjectWriter
+	objectRef() string
+}
+
+// PdfLink is a link annotation, a rectangle on a page that opens a URI or jumps to a position in the document when
+// clicked
+type PdfLink struct {
+	PdfObject
+	rect       [4]float64
+	uri        string
+	target     *PdfPage
+	targetY    float64 // in points
+	targetName string  // a named destination, instead of target
+}
+
+// AddLink makes the rectangle with its corner at x, y a link to uri
+func (p *PdfPage) AddLink(x, y, w, h float64, uri string) {
+	x, y, w, h = p.rect(x, y, w, h)
+	p.addLink(x, y, w, h, uri)
+}
+
+// addLink adds a link covering the rectangle with its bottom left corner at x, y, measured in points
+func (p *PdfPage) addLink(x, y, w, h float64, uri string) *PdfLink {
+	l := &PdfLink{rect: [4]float64{x, y, x + w, y + h}, uri: uri}
+	if p.measure != nil {
+		return l
+	}
+	p.addObject(l)
+	p.annots = append(p.annots, l)
+	return l
+}
+
+// AddInternalLink makes the rectangle with its corner at x, y a link to height targetY on the target page. The
+// target can be nil when the page has not been added yet and set later with SetDestination.
+func (p *PdfPage) AddInternalLink(x, y, w, h float64, target *PdfPage, targetY float64) *PdfLink {
+	x, y, w, h = p.rect(x, y, w, h)
+	l := p.addLink(x, y, w, h, "")
+	l.SetDestination(target, targetY)
+	return l
+}
+
+// SetDestination sets the page and height that an internal link jumps to
+func (l *PdfLink) SetDestination(target *PdfPage, targetY float64) {
+	l.target = target
+	l.targetY = l.document.pageY(target, targetY)
+	l.targetName = ""
+}
+
+// pageY converts a height on page in the document's unit and origin to points above the bottom of the page, for a
+// destination. The page can be nil when it is set later.
+func (d *PdfDocument) pageY(page *PdfPage, y float64) float64 {
+	if page == nil {
+		return y * float64(d.unit)
+	}
+	return page.ptY(y, 0)
+}
+
+// destination returns an explicit destination showing page scrolled to height y at the current zoom
+func destination(page *PdfPage, y float64) string {
+	return fmt.Sprintf("[ %v /XYZ null %v null ]", page.objectRef(), formatNumber(y))
+}
+
+// PrintLink prints text at the cursor like Print and makes it a link to uri
+func (p *PdfPage) PrintLink(text, uri string) {
+	width := p.textWidth(text)
+	size := p.fontSize
+	// cover the descenders below the baseline as well as the capitals above it
+	p.addLink(p.x, p.y-size/4, width, size, uri)
+	p.Print(text)
+}
+
+// escapeURI percent encodes the bytes of uri that are not printable ASCII, since URIs in a PDF are 7 bit strings
+func escapeURI(uri string) string {
+	var sb strings.Builder
+	for i := 0; i < len(uri); i++ {
+		if b := uri[i]; b <= ' ' || b > '~' {
+			fmt.Fprintf(&sb, "%%%02X", b)
+		} else {
+			sb.WriteByte(b)
+		}
+	}
+	return sb.String()
+}
+
+func (l *PdfLink) bytes() []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%v 0 obj\r\n", l.id)
+	fmt.Fprintf(&buf, "<<\r\n")
+	fmt.Fprintf(&buf, "/Type /Annot\r\n")
+	fmt.Fprintf(&buf, "/Subtype /Link\r\n")
+	fmt.Fprintf(&buf, "/Rect %v\r\n", formatRect(l.rect))
+	fmt.Fprintf(&buf, "/Border [ 0 0 0 ]\r\n")
+	fmt.Fprintf(&buf, "/F 4\r\n") // print
+	if l.uri != "" {
+		fmt.Fprintf(&buf, "/A << /S /URI /URI %v >>\r\n", formatString(escapeURI(l.uri)))
+	} else if l.target != nil {
+		fmt.Fprintf(&buf, "/Dest %v\r\n", destination(l.target, l.targetY))
+	} else if l.targetName != "" {
+		fmt.Fprintf(&buf, "/Dest %v\r\n", formatString(l.targetName))
+	}
+	fmt.Fprintf(&buf, ">>\r\n")
+	fmt.Fprintf(&buf, "endobj\r\n")
+	return buf.Bytes()
+}
+
+// PdfNote is a text annotation, a comment shown as an icon that opens a pop-up note when clicked
+type PdfNote struct {
+	PdfObject
+	rect     [4]float64
+	title    string
+	contents string
+}
+
+// AddNote adds a note with its icon's corner at x, y. The title is usually the name of the author.
+func (p *PdfPage) AddNote(x, y float64, title, contents string) {
+	if p.measure != nil {
+		return
+	}
+	x, y = p.pt(x), p.ptY(y, 20)
+	n := &PdfNote{rect: [4]float64{x, y, x + 20, y + 20}, title: title, contents: contents}
+	p.addObject(n)
+	p.annots = append(p.annots, n)
+}
+
+func (n PdfNote) bytes() []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%v 0 obj\r\n", n.id)
+	fmt.Fprintf(&buf, "<<\r\n")
+	fmt.Fprintf(&buf, "/Type /Annot\r\n")
+	fmt.Fprintf(&buf, "/Subtype /Text\r\n")
+	fmt.Fprintf(&buf, "/Rect %v\r\n", formatRect(n.rect))
+	fmt.Fprintf(&buf, "/Name /Comment\r\n")
+	fmt.Fprintf(&buf, "/T %v\r\n", formatTextString(n.title))
+	fmt.Fprintf(&buf, "/Contents %v\r\n", formatTextString(n.contents))
+	fmt.Fprintf(&buf, "/F 4\r\n") // print
+	fmt.Fprintf(&buf, ">>\r\n")
+	fmt.Fprintf(&buf, "endobj\r\n")
+	return buf.Bytes()
+}
+
+// PdfFreeText is a free text annotation, a comment shown as text in a box on the page
+type PdfFreeText struct {
+	PdfObject
+	rect       [4]float64
+	text       string
+	font       *PdfFont
+	fontSize   float64
+	appearance *appearanceStream
+}
+
+// AddFreeText adds a comment shown as text in a box w by h with its corner at x, y. The text is in the
+// current font, or Helvetica when that is a Unicode font, and each line of it starts on a new line. The font size is
+// in points.
+func (p *PdfPage) AddFreeText(x, y, w, h float64, text string, fontSize float64) {
+	if p.measure != nil {
+		return
+	}
+	x, y, w, h = p.rect(x, y, w, h)
+	t := &PdfFreeText{rect: [4]float64{x, y, x + w, y + h}, text: text, font: p.fieldFont(), fontSize: fontSize}
+	// viewers that don't draw the text from /DA show the appearance
+	var ops strings.Builder
+	fmt.Fprintf(&ops, "0.5 w\r\n0.25 0.25 %v %v re\r\nS\r\n", formatNumber(w-0.5), formatNumber(h-0.5))
+	fmt.Fprintf(&ops, "BT\r\n%v %v Tf\r\n%v TL\r\n2 %v Td\r\n", formatName(t.font.name), formatNumber(fontSize),
+		formatNumber(fontSize), formatNumber(h-fontSize))
+	for _, line := range strings.Split(text, "\n") {
+		encoded, _, _ := encodeWinAnsi(line, p.document.replacement)
+		fmt.Fprintf(&ops, "%v Tj\r\nT*\r\n", formatString(encoded))
+	}
+	fmt.Fprintf(&ops, "ET\r\n")
+	resources := fmt.Sprintf("/Resources << /Font << %v %v >> >>\r\n", formatName(t.font.name), t.font.objectRef())
+	t.appearance = p.addAppearance(w, h, resources, ops.String())
+	p.addObject(t)
+	p.annots = append(p.annots, t)
+}
+
+func (t PdfFreeText) bytes() []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%v 0 obj\r\n", t.id)
+	fmt.Fprintf(&buf, "<<\r\n")
+	fmt.Fprintf(&buf, "/Type /Annot\r\n")
+	fmt.Fprintf(&buf, "/Subtype /FreeText\r\n")
+	fmt.Fprintf(&buf, "/Rect %v\r\n", formatRect(t.rect))
+	fmt.Fprintf(&buf, "/Contents %v\r\n", formatTextString(t.text))
+	fmt.Fprintf(&buf, "/DA (%v %v Tf 0 g)\r\n", formatName(t.font.name), formatNumber(t.fontSize))
+	fmt.Fprintf(&buf, "/AP << /N %v >>\r\n", t.appearance.objectRef())
+	fmt.Fprintf(&buf, "/F 4\r\n") // print
+	fmt.Fprintf(&buf, ">>\r\n")
+	fmt.Fprintf(&buf, "endobj\r\n")
+	return buf.Bytes()
+}
+
+// appearanceStream is a form XObject that draws an annotation
+type appearanceStream struct {
+	PdfObject
+	width, height float64
+	resources     string
+	ops           string
+}
+
+// addAppearance adds an appearance stream for an annotation on the page drawn with ops in a box w by h points
+func (p *PdfPage) addAppearance(w, h float64, resources, ops string) *appearanceStream {
+	a := &appearanceStream{width: w, height: h, resources: resources, ops: ops}
+	p.addObject(a)
+	return a
+}
+
+func (a appearanceStream) bytes() []byte {
+	entries := fmt.Sprintf("/Type /XObject\r\n/Subtype /Form\r\n/BBox [ 0 0 %v %v ]\r\n%v", formatNumber(a.width), formatNumber(a.height), a.resources)
+	return streamObject(a.id, entries, []byte(a.ops))
+}
@@ -0,0 +1,76 @@
+package gopdf
+
+import (
+	"bytes"
+	"crypto/md5"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// PdfFileSpec is a file specification for a file embedded in the document
+type PdfFileSpec struct {
+	PdfObject
+	name        string
+	description string
+	file        *PdfEmbeddedFile
+}
+
+// PdfEmbeddedFile is the stream holding the data of an attached file
+type PdfEmbeddedFile struct {
+	PdfObject
+	data     []byte
+	modified time.Time
+}
+
+// AttachFile embeds a copy of data in the document as a file called name, which viewers list as an attachment
+func (d *PdfDocument) AttachFile(name string, data []byte, description string) {
+	f := &PdfEmbeddedFile{data: append([]byte(nil), data...), modified: d.now()}
+	d.addObject(f)
+	spec := &PdfFileSpec{name: name, description: description, file: f}
+	d.addObject(spec)
+	d.catalog.attachments = append(d.catalog.attachments, spec)
+}
+
+// embeddedFilesTree returns the name tree of the attachments, whose keys must be sorted by their bytes
+func embeddedFilesTree(attachments []*PdfFileSpec) string {
+	sorted := append([]*PdfFileSpec(nil), attachments...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return encodeTextString(sorted[i].name) < encodeTextString(sorted[j].name)
+	})
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "<< /Names [ ")
+	for _, spec := range sorted {
+		fmt.Fprintf(&buf, "%v %v ", formatTextString(spec.name), spec.objectRef())
+	}
+	fmt.Fprintf(&buf, "] >>")
+	return buf.String()
+}
+
+func (s PdfFileSpec) bytes() []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%v 0 obj\r\n", s.id)
+	fmt.Fprintf(&buf, "<<\r\n")
+	fmt.Fprintf(&buf, "/Type /Filespec\r\n")
+	fmt.Fprintf(&buf, "/F %v\r\n", formatTextString(s.name))
+	fmt.Fprintf(&buf, "/UF %v\r\n", formatTextString(s.name))
+	fmt.Fprintf(&buf, "/EF << /F %v >>\r\n", s.file.objectRef())
+	if s.description != "" {
+		fmt.Fprintf(&buf, "/Desc %v\r\n", formatTextString(s.description))
+	}
+	fmt.Fprintf(&buf, ">>\r\n")
+	fmt.Fprintf(&buf, "endobj\r\n")
+	return buf.Bytes()
+}
+
+func (f PdfEmbeddedFile) bytes() []byte {
+	sum := md5.Sum(f.data)
+	entries := "/Type /EmbeddedFile\r\n"
+	entries += fmt.Sprintf("/Params << /Size %v /CheckSum <%X> /ModDate %v >>\r\n", len(f.data), sum, formatString(formatDate(f.modified)))
+	data := f.data
+	if !f.document.noCompression {
+		entries += "/Filter /FlateDecode\r\n"
+		data = deflate(data)
+	}
+	return streamObject(f.id, entries, data)
+}
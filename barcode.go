@@ -0,0 +1,188 @@
+package gopdf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// code128Patterns are the widths of the bars and spaces of each Code 128 symbol in modules, starting with a bar.
+// Symbols 103 to 105 are the start symbols for code sets A, B and C and 106 is the stop symbol.
+var code128Patterns = [107]string{
+	"212222", "222122", "222221", "121223", "121322", "131222", "122213", "122312", "132212", "221213",
+	"221312", "231212", "112232", "122132", "122231", "113222", "123122", "123221", "223211", "221132",
+	"221231", "213212", "223112", "312131", "311222", "321122", "321221", "312212", "322112", "322211",
+	"212123", "212321", "232121", "111323", "131123", "131321", "112313", "132113", "132311", "211313",
+	"231113", "231311", "112133", "112331", "132131", "113123", "113321", "133121", "313121", "211331",
+	"231131", "213113", "213311", "213131", "311123", "311321", "331121", "312113", "312311", "332111",
+	"314111", "221411", "431111", "111224", "111422", "121124", "121421", "141122", "141221", "112214",
+	"112412", "122114", "122411", "142112", "142211", "241211", "221114", "413111", "241112", "134111",
+	"111242", "121142", "121241", "114212", "124112", "124211", "411212", "421112", "421211", "212141",
+	"214121", "412121", "111143", "111341", "131141", "114113", "114311", "411113", "411311", "113141",
+	"114131", "311141", "411131", "211412", "211214", "211232", "2331112",
+}
+
+// Code 128 code sets and the symbols that switch to them
+const (
+	code128A = iota
+	code128B
+	code128C
+)
+
+var code128Switch = [3]int{101, 100, 99}
+
+// code39Patterns are the Code 39 characters with their bars and spaces, where 1 is a wide element
+var code39Patterns = map[rune]string{
+	'0': "000110100", '1': "100100001", '2': "001100001", '3': "101100000", '4': "000110001",
+	'5': "100110000", '6': "001110000", '7': "000100101", '8': "100100100", '9': "001100100",
+	'A': "100001001", 'B': "001001001", 'C': "101001000", 'D': "000011001", 'E': "100011000",
+	'F': "001011000", 'G': "000001101", 'H': "100001100", 'I': "001001100", 'J': "000011100",
+	'K': "100000011", 'L': "001000011", 'M': "101000010", 'N': "000010011", 'O': "100010010",
+	'P': "001010010", 'Q': "000000111", 'R': "100000110", 'S': "001000110", 'T': "000010110",
+	'U': "110000001", 'V': "011000001", 'W': "111000000", 'X': "010010001", 'Y': "110010000",
+	'Z': "011010000", '-': "010000101", '.': "110000100", ' ': "011000100", '$': "010101000",
+	'/': "010100010", '+': "010001010", '%': "000101010", '*': "010010100",
+}
+
+// SetBarcodeText turns printing the data of barcodes in the current font beneath the bars on or off
+func (p *PdfPage) SetBarcodeText(show bool) {
+	p.barcodeText = show
+}
+
+// DrawBarcode128 draws data as a Code 128 barcode with its corner at x, y. The narrowest bar is
+// moduleWidth wide, and scanners need a clear space of at least 10 modules either side. Data can contain any
+// ASCII characters, and the code sets are chosen to keep the barcode short.
+func (p *PdfPage) DrawBarcode128(data string, x, y, height, moduleWidth float64) error {
+	symbols, err := code128Symbols(data)
+	if err != nil {
+		return err
+	}
+	var widths []int
+	for _, symbol := range symbols {
+		for _, w := range code128Patterns[symbol] {
+			widths = append(widths, int(w-'0'))
+		}
+	}
+	x, y, _, height = p.rect(x, y, 0, height)
+	p.drawBars(data, widths, x, y, height, p.pt(moduleWidth))
+	return nil
+}
+
+// code128Symbols encodes data as Code 128 symbols including the start, checksum and stop symbols. Runs of four or
+// more digits use code set C, which holds two digits a symbol, and the rest use code set B unless it has control
+// characters, which are only in code set A.
+func code128Symbols(data string) ([]int, error) {
+	for i := 0; i < len(data); i++ {
+		if data[i] > 127 {
+			return nil, fmt.Errorf("%w: %q can't be encoded in Code 128", ErrInvalidBarcode, data[i])
+		}
+	}
+	digits := func(i int) int {
+		n := 0
+		for i+n < len(data) && data[i+n] >= '0' && data[i+n] <= '9' {
+			n++
+		}
+		return n
+	}
+	textSet := func(i int) int {
+		// the set that holds the next character that's only in one of A and B
+		for ; i < len(data); i++ {
+			if data[i] < ' ' {
+				return code128A
+			}
+			if data[i] >= '`' {
+				return code128B
+			}
+		}
+		return code128B
+	}
+	var symbols []int
+	set := -1
+	use := func(s int) {
+		if set == -1 {
+			symbols = append(symbols, 103+s)
+		} else if set != s {
+			symbols = append(symbols, code128Switch[s])
+		}
+		set = s
+	}
+	for i := 0; i < len(data); {
+		if n := digits(i); n >= 4 || n == len(data) && n%2 == 0 || set == code128C && n >= 2 {
+			if n%2 == 1 && set != code128C {
+				// encode the odd digit first so that the rest pair up
+				if set == -1 {
+					use(textSet(i))
+				}
+				symbols = append(symbols, int(data[i])-' ')
+				i++
+			}
+			use(code128C)
+			for ; i+1 < len(data) && data[i] >= '0' && data[i] <= '9' && data[i+1] >= '0' && data[i+1] <= '9'; i += 2 {
+				symbols = append(symbols, int(data[i]-'0')*10+int(data[i+1]-'0'))
+			}
+			continue
+		}
+		c := int(data[i])
+		switch {
+		case set == code128A && c >= '`', set == code128B && c < ' ', set == code128C, set == -1:
+			use(textSet(i))
+		}
+		if set == code128A && c < ' ' {
+			symbols = append(symbols, c+64)
+		} else {
+			symbols = append(symbols, c-' ')
+		}
+		i++
+	}
+	if set == -1 {
+		use(code128B)
+	}
+	checksum := symbols[0]
+	for i, symbol := range symbols[1:] {
+		checksum += (i + 1) * symbol
+	}
+	return append(symbols, checksum%103, 106), nil
+}
+
+// DrawBarcode39 draws data as a Code 39 barcode with its corner at x, y, with the start and stop
+// characters added. Narrow bars are moduleWidth wide and wide ones three times that. Data can contain the
+// digits, capital letters, space and - . $ / + %.
+func (p *PdfPage) DrawBarcode39(data string, x, y, height, moduleWidth float64) error {
+	var widths []int
+	for i, r := range "*" + data + "*" {
+		pattern, ok := code39Patterns[r]
+		if !ok || r == '*' && i > 0 && i < len(data)+1 {
+			return fmt.Errorf("%w: %q can't be encoded in Code 39", ErrInvalidBarcode, r)
+		}
+		if i > 0 {
+			// the narrow space between characters
+			widths = append(widths, 1)
+		}
+		for _, e := range pattern {
+			widths = append(widths, 1+2*int(e-'0'))
+		}
+	}
+	x, y, _, height = p.rect(x, y, 0, height)
+	p.drawBars(data, widths, x, y, height, p.pt(moduleWidth))
+	return nil
+}
+
+// drawBars draws alternating bars and spaces of the given widths in modules, starting with a bar, and the text
+// beneath them if SetBarcodeText is on
+func (p *PdfPage) drawBars(text string, widths []int, x, y, height, moduleWidth float64) {
+	var sb strings.Builder
+	at := x
+	for i, w := range widths {
+		if i%2 == 0 {
+			fmt.Fprintf(&sb, "%v %v %v %v re\r\n", formatNumber(at), formatNumber(y), formatNumber(float64(w)*moduleWidth), formatNumber(height))
+		}
+		at += float64(w) * moduleWidth
+	}
+	p.content.addGraphics(sb.String() + "f\r\n")
+	if p.barcodeText {
+		left := x + (at-x-p.textWidth(text))/2
+		baseline := p.y
+		p.y = y - p.fontSize
+		p.outputTextAt(text, left)
+		p.y = baseline
+	}
+}
@@ -0,0 +1,112 @@
+package gopdf
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// barWidths returns the widths of the alternating bars and spaces of the one barcode drawn on the page, from the
+// rectangles of its bars
+func barWidths(t *testing.T, p *PdfPage) []int {
+	t.Helper()
+	content := p.content.stream.String()
+	var widths []int
+	end := -1.0
+	for _, line := range strings.Split(content, "\r\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 5 || fields[4] != "re" {
+			continue
+		}
+		x, err1 := strconv.ParseFloat(fields[0], 64)
+		w, err2 := strconv.ParseFloat(fields[2], 64)
+		if err1 != nil || err2 != nil {
+			t.Fatalf("bad rectangle %q", line)
+		}
+		if end >= 0 {
+			widths = append(widths, int(x-end))
+		}
+		widths = append(widths, int(w))
+		end = x + w
+	}
+	return widths
+}
+
+func TestCode128Symbols(t *testing.T) {
+	for _, c := range []struct {
+		data string
+		want []int
+	}{
+		{"ABC", []int{104, 33, 34, 35, 1, 106}},
+		{"123456", []int{105, 12, 34, 56, 44, 106}},
+		{"AB1234", []int{104, 33, 34, 99, 12, 34, 102, 106}},
+		{"12345", []int{104, 17, 99, 23, 45, 53, 106}},
+		{"X1234Y", []int{104, 56, 99, 12, 34, 100, 57, 33, 106}},
+		{"X12Y", []int{104, 56, 17, 18, 57, 64, 106}},
+		{"a\tb", []int{104, 65, 101, 73, 100, 66, 84, 106}},
+		{"", []int{104, 1, 106}},
+	} {
+		got, err := code128Symbols(c.data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(c.want) {
+			t.Errorf("code128Symbols(%q) = %v, want %v", c.data, got, c.want)
+		}
+	}
+}
+
+func TestDrawBarcode128(t *testing.T) {
+	p := NewPdfDocument().CurrentPage()
+	if err := p.DrawBarcode128("AB1234", 100, 100, 20, 1); err != nil {
+		t.Fatal(err)
+	}
+	// start B, A, B, switch to C, 12, 34, the check symbol 102 and stop
+	want := "211214" + "111323" + "131123" + "113141" + "112232" + "131123" + "411131" + "2331112"
+	var got strings.Builder
+	for _, w := range barWidths(t, p) {
+		got.WriteString(strconv.Itoa(w))
+	}
+	if got.String() != want {
+		t.Errorf("bars and spaces %v, want %v", got.String(), want)
+	}
+}
+
+func TestDrawBarcode39(t *testing.T) {
+	p := NewPdfDocument().CurrentPage()
+	if err := p.DrawBarcode39("A-1", 100, 100, 20, 1); err != nil {
+		t.Fatal(err)
+	}
+	var want []int
+	for i, pattern := range []string{"010010100", "100001001", "010000101", "100100001", "010010100"} {
+		if i > 0 {
+			want = append(want, 1)
+		}
+		for _, e := range pattern {
+			want = append(want, map[rune]int{'0': 1, '1': 3}[e])
+		}
+	}
+	if got := barWidths(t, p); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("bars and spaces %v, want %v", got, want)
+	}
+}
+
+func TestBarcodeErrors(t *testing.T) {
+	p := NewPdfDocument().CurrentPage()
+	before := p.content.stream.Len()
+	for _, data := range []string{"café", "\x80", "tab\t\xFF"} {
+		if err := p.DrawBarcode128(data, 100, 100, 20, 1); !errors.Is(err, ErrInvalidBarcode) {
+			t.Errorf("DrawBarcode128(%q) returned %v", data, err)
+		}
+	}
+	for _, data := range []string{"abc", "A*B", "*", "CAFÉ", "A_B"} {
+		if err := p.DrawBarcode39(data, 100, 100, 20, 1); !errors.Is(err, ErrInvalidBarcode) {
+			t.Errorf("DrawBarcode39(%q) returned %v", data, err)
+		}
+	}
+	if p.content.stream.Len() != before {
+		t.Errorf("invalid barcodes drew %q", p.content.stream.String()[before:])
+	}
+}
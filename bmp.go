@@ -0,0 +1,140 @@
+package gopdf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"math/bits"
+)
+
+// isBMP reports whether file starts with a BMP header
+func isBMP(file []byte) bool {
+	return bytes.HasPrefix(file, []byte("BM"))
+}
+
+// decodeBMP decodes an uncompressed or bit field BMP file with 1, 4, 8, 16, 24 or 32 bits per pixel
+func decodeBMP(file []byte) (image.Image, error) {
+	le := binary.LittleEndian
+	if len(file) < 26 {
+		return nil, fmt.Errorf("%w: BMP header is truncated", ErrUnsupportedImage)
+	}
+	pixelsAt := int(le.Uint32(file[10:]))
+	headerSize := int(le.Uint32(file[14:]))
+	var width, height, depth, compression, colours int
+	switch {
+	case headerSize == 12 && len(file) >= 26:
+		width, height = int(int16(le.Uint16(file[18:]))), int(int16(le.Uint16(file[20:])))
+		depth = int(le.Uint16(file[24:]))
+	case headerSize >= 40 && len(file) >= 14+headerSize:
+		width, height = int(int32(le.Uint32(file[18:]))), int(int32(le.Uint32(file[22:])))
+		depth, compression = int(le.Uint16(file[28:])), int(le.Uint32(file[30:]))
+		colours = int(le.Uint32(file[46:]))
+	default:
+		return nil, fmt.Errorf("%w: BMP header of %v bytes", ErrUnsupportedImage, headerSize)
+	}
+	// a negative height is stored from the top down
+	topDown := height < 0
+	if topDown {
+		height = -height
+	}
+
+	// the bit fields of 16 and 32 bit pixels, which follow the header or are part of it
+	masks := [3]uint32{0x7C00, 0x3E0, 0x1F}
+	if depth == 32 {
+		masks = [3]uint32{0xFF0000, 0xFF00, 0xFF}
+	}
+	switch compression {
+	case 0:
+	case 3:
+		// after a 40 byte header, or in the same place in the longer ones
+		at := 54
+		if depth != 16 && depth != 32 || len(file) < at+12 {
+			return nil, fmt.Errorf("%w: BMP bit fields with %v bits", ErrUnsupportedImage, depth)
+		}
+		for i := range masks {
+			masks[i] = le.Uint32(file[at+4*i:])
+		}
+	default:
+		name := map[int]string{1: "RLE8", 2: "RLE4", 4: "JPEG", 5: "PNG"}[compression]
+		if name == "" {
+			name = fmt.Sprint(compression)
+		}
+		return nil, fmt.Errorf("%w: BMP compression %v", ErrUnsupportedImage, name)
+	}
+	if width <= 0 || height <= 0 || depth != 1 && depth != 4 && depth != 8 && depth != 16 && depth != 24 && depth != 32 {
+		return nil, fmt.Errorf("%w: BMP %v by %v with %v bits per pixel", ErrUnsupportedImage, width, height, depth)
+	}
+	rowBytes := (width*depth + 31) / 32 * 4
+	if pixelsAt < 0 || pixelsAt+rowBytes*height > len(file) {
+		return nil, fmt.Errorf("%w: BMP pixels are truncated", ErrUnsupportedImage)
+	}
+	row := func(y int) []byte {
+		if !topDown {
+			y = height - 1 - y
+		}
+		return file[pixelsAt+y*rowBytes : pixelsAt+(y+1)*rowBytes]
+	}
+	rect := image.Rect(0, 0, width, height)
+
+	if depth <= 8 {
+		if colours == 0 || colours > 1<<depth {
+			colours = 1 << depth
+		}
+		entry, at := 4, 14+headerSize
+		if headerSize == 12 {
+			entry = 3
+		}
+		if at+entry*colours > len(file) {
+			return nil, fmt.Errorf("%w: BMP palette is truncated", ErrUnsupportedImage)
+		}
+		palette := make(color.Palette, colours)
+		for i := range palette {
+			bgr := file[at+entry*i:]
+			palette[i] = color.RGBA{bgr[2], bgr[1], bgr[0], 255}
+		}
+		img := image.NewPaletted(rect, palette)
+		for y := 0; y < height; y++ {
+			r := row(y)
+			for x := 0; x < width; x++ {
+				bit := x * depth
+				index := r[bit/8] >> (8 - depth - bit%8) & (1<<depth - 1)
+				if int(index) >= colours {
+					index = 0
+				}
+				img.Pix[y*width+x] = index
+			}
+		}
+		return img, nil
+	}
+
+	// field returns the value of a bit field scaled to 8 bits
+	field := func(v, mask uint32) uint8 {
+		if mask == 0 {
+			return 0
+		}
+		v = (v & mask) >> bits.TrailingZeros32(mask)
+		max := mask >> bits.TrailingZeros32(mask)
+		return uint8(uint64(v) * 255 / uint64(max))
+	}
+	img := image.NewNRGBA(rect)
+	for y := 0; y < height; y++ {
+		r := row(y)
+		for x := 0; x < width; x++ {
+			at := 4 * (y*width + x)
+			switch depth {
+			case 24:
+				img.Pix[at], img.Pix[at+1], img.Pix[at+2] = r[3*x+2], r[3*x+1], r[3*x]
+			case 16:
+				v := uint32(le.Uint16(r[2*x:]))
+				img.Pix[at], img.Pix[at+1], img.Pix[at+2] = field(v, masks[0]), field(v, masks[1]), field(v, masks[2])
+			case 32:
+				v := le.Uint32(r[4*x:])
+				img.Pix[at], img.Pix[at+1], img.Pix[at+2] = field(v, masks[0]), field(v, masks[1]), field(v, masks[2])
+			}
+			img.Pix[at+3] = 255
+		}
+	}
+	return img, nil
+}
@@ -0,0 +1,94 @@
+package gopdf
+
+import "fmt"
+
+// cellPadding is the space in points between the sides of a cell and its text
+const cellPadding = 2
+
+// SetCellFillColor sets the colour used to fill the background of cells, with components from 0 to 255. The text
+// in the cells is drawn in the fill colour set with SetFillColor.
+func (p *PdfPage) SetCellFillColor(red, green, blue int) {
+	p.cellFill = RGBColor{red, green, blue}
+}
+
+// SetCellFill sets the colour used to fill the background of cells, as for SetCellFillColor
+func (p *PdfPage) SetCellFill(c Color) {
+	p.cellFill = c
+}
+
+// Cell draws a cell w wide and h high whose top left corner is at the cursor, where the top of the
+// current line is the font size above the baseline. The text is centred vertically and aligned within the cell,
+// which can have a border and be filled with the cell fill colour. The cursor moves to the right of the cell so that
+// a row of cells followed by Ln makes a simple table.
+func (p *PdfPage) Cell(w, h float64, text string, border bool, align Alignment, fill bool) {
+	x, top := p.x, p.y+p.fontSize
+	w, h = p.pt(w), p.pt(h)
+	p.drawCellBox(x, top, w, h, border, fill)
+	if text != "" {
+		// centre the capitals, which are about 70% of the font size
+		p.printInCell(text, x, top-h/2-0.35*p.fontSize, w, align)
+	}
+	p.x = x + w
+	p.lastCellHeight = h
+}
+
+// MultiCell draws text wrapped to fit within w, with lines h apart, in a cell whose top left corner
+// is at the cursor. The cell can have a border and be filled with the cell fill colour. The cursor moves to the start
+// of the line below the cell.
+func (p *PdfPage) MultiCell(w, h float64, text string, border bool, align Alignment, fill bool) {
+	x, top := p.x, p.y+p.fontSize
+	w, h = p.pt(w), p.pt(h)
+	width := w - 2*cellPadding
+	lines := p.wrapText(text, width, width)
+	if len(lines) == 0 {
+		lines = []string{""}
+	}
+	height := h * float64(len(lines))
+	p.drawCellBox(x, top, w, height, border, fill)
+	for i, line := range lines {
+		lineAlign := align
+		if align == AlignJustify && i == len(lines)-1 {
+			lineAlign = AlignLeft
+		}
+		lineTop := top - float64(i)*h
+		p.printInCell(line, x, lineTop-h/2-0.35*p.fontSize, w, lineAlign)
+	}
+	p.lastCellHeight = height
+	p.lineFeed(height)
+}
+
+// Ln moves the cursor to the start of the line, at the left margin or the current column, and down by h, or by the
+// height of the last cell when h is 0
+func (p *PdfPage) Ln(h float64) {
+	p.lineFeed(p.pt(h))
+}
+
+// lineFeed moves the cursor to the start of the line and down by h points, or by the height of the last cell when h
+// is 0
+func (p *PdfPage) lineFeed(h float64) {
+	if h <= 0 {
+		h = p.lastCellHeight
+	}
+	p.x = p.lineLeft()
+	p.y -= h
+}
+
+// drawCellBox draws the border and background of a cell with its top left corner at x, top
+func (p *PdfPage) drawCellBox(x, top, w, h float64, border, fill bool) {
+	rect := fmt.Sprintf("%v %v %v %v re\r\n", formatNumber(x), formatNumber(top-h), formatNumber(w), formatNumber(h))
+	if fill {
+		p.use(p.cellFill)
+		p.content.addGraphicsf("q\r\n%v\r\n%vf\r\nQ\r\n", p.cellFill.operator(false), rect)
+	}
+	if border {
+		p.content.addGraphics(rect + "S\r\n")
+	}
+}
+
+// printInCell prints text on the baseline aligned within a cell starting at x that is w points wide
+func (p *PdfPage) printInCell(text string, x, baseline, w float64, align Alignment) {
+	y := p.y
+	p.y = baseline
+	p.outputAligned(text, x+cellPadding, w-2*cellPadding, align)
+	p.y = y
+}
@@ -0,0 +1,262 @@
+package gopdf
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ChartPalette is the default palette for charts
+var ChartPalette = []Color{
+	RGBColor{31, 119, 180}, RGBColor{255, 127, 14}, RGBColor{44, 160, 44}, RGBColor{214, 39, 40},
+	RGBColor{148, 103, 189}, RGBColor{140, 86, 75}, RGBColor{227, 119, 194}, RGBColor{127, 127, 127},
+}
+
+// ChartOptions controls how charts are drawn. The zero value uses ChartPalette and about 5 intervals on the value
+// axis.
+type ChartOptions struct {
+	Palette   []Color // colours used in turn for bars, lines and slices, or nil for ChartPalette
+	Ticks     int     // number of intervals on the value axis, or 0 for about 5
+	LineWidth float64 // width of the lines of line charts, or 0 for 1.5
+}
+
+// colour returns the palette colour for the i'th bar, line or slice
+func (o ChartOptions) colour(i int) Color {
+	palette := o.Palette
+	if len(palette) == 0 {
+		palette = ChartPalette
+	}
+	return palette[i%len(palette)]
+}
+
+// chartArea is the part of a chart inside its axes, with the range of values it shows from bottom to top
+type chartArea struct {
+	x, y, w, h float64
+	lo, hi     float64
+}
+
+// valueY returns the height on the page of a value
+func (a chartArea) valueY(v float64) float64 {
+	return a.y + (v-a.lo)/(a.hi-a.lo)*a.h
+}
+
+// slotX returns the middle of the i'th of n categories along the bottom
+func (a chartArea) slotX(i, n int) float64 {
+	return a.x + (float64(i)+0.5)*a.w/float64(n)
+}
+
+// DrawBarChart draws a bar chart w by h with its corner at x, y, including the labels of the axes.
+// Each value is a bar, coloured from the palette in turn, with its label beneath. Labels can be nil, and the labels
+// and value axis use the current font.
+func (p *PdfPage) DrawBarChart(x, y, w, h float64, series []float64, labels []string, opts ChartOptions) error {
+	if err := checkChartData(labels, len(series), series); err != nil {
+		return err
+	}
+	x, y, w, h = p.rect(x, y, w, h)
+	area := p.drawChartAxes(x, y, w, h, series, labels, opts)
+	var sb strings.Builder
+	zero := area.valueY(0)
+	slot := area.w / float64(max(len(series), 1))
+	for i, v := range series {
+		p.use(opts.colour(i))
+		fmt.Fprintf(&sb, "%v\r\n%v %v %v %v re\r\nf\r\n", opts.colour(i).operator(false),
+			formatNumber(area.slotX(i, len(series))-0.35*slot), formatNumber(zero), formatNumber(0.7*slot),
+			formatNumber(area.valueY(v)-zero))
+	}
+	p.content.addGraphics(sb.String() + "Q\r\n")
+	return nil
+}
+
+// DrawLineChart draws a line chart w by h with its corner at x, y, including the labels of the
+// axes. Each series is a line through its values, coloured from the palette in turn, and the values at the same
+// index in each series share a label beneath. Labels can be nil, and the labels and value axis use the current font.
+func (p *PdfPage) DrawLineChart(x, y, w, h float64, series [][]float64, labels []string, opts ChartOptions) error {
+	n := 0
+	var all []float64
+	for _, s := range series {
+		n = max(n, len(s))
+		all = append(all, s...)
+	}
+	if err := checkChartData(labels, n, all); err != nil {
+		return err
+	}
+	x, y, w, h = p.rect(x, y, w, h)
+	area := p.drawChartAxes(x, y, w, h, all, labels, opts)
+	width := opts.LineWidth
+	if width == 0 {
+		width = 1.5
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%v w\r\n1 J\r\n1 j\r\n", formatNumber(width))
+	for i, s := range series {
+		p.use(opts.colour(i))
+		points := make([]Point, len(s))
+		for j, v := range s {
+			points[j] = Point{area.slotX(j, n), area.valueY(v)}
+		}
+		if path, err := polylinePath(points); err == nil {
+			fmt.Fprintf(&sb, "%v\r\n%vS\r\n", opts.colour(i).operator(true), path)
+		}
+		// a dot on each point, which is all that shows of a series with one value
+		fmt.Fprintf(&sb, "%v\r\n", opts.colour(i).operator(false))
+		for _, pt := range points {
+			sb.WriteString(arcPath(pt.X, pt.Y, width*1.5, width*1.5, 0, 360) + "f\r\n")
+		}
+	}
+	p.content.addGraphics(sb.String() + "Q\r\n")
+	return nil
+}
+
+// DrawPieChart draws a pie chart of radius r centred on cx, cy with a slice for each value, starting at the top and
+// going clockwise, coloured from the palette in turn. Labels can be nil, and are drawn outside the circle in the
+// current font. Values can't be negative, and if they are all zero only the outline of the circle is drawn.
+func (p *PdfPage) DrawPieChart(cx, cy, r float64, values []float64, labels []string, opts ChartOptions) error {
+	if err := checkChartData(labels, len(values), values); err != nil {
+		return err
+	}
+	cx, cy, r = p.pt(cx), p.ptY(cy, 0), p.pt(r)
+	total := 0.0
+	for _, v := range values {
+		if v < 0 {
+			return fmt.Errorf("%w: negative value %v in a pie chart", ErrInvalidChart, v)
+		}
+		total += v
+	}
+	var sb strings.Builder
+	sb.WriteString("q\r\n")
+	if total == 0 {
+		sb.WriteString("0.5 G\r\n" + arcPath(cx, cy, r, r, 0, 360) + "h\r\nS\r\nQ\r\n")
+		p.content.addGraphics(sb.String())
+		return nil
+	}
+	// slices are separated by white lines
+	sb.WriteString("1 G\r\n1 w\r\n1 j\r\n")
+	start := 90.0
+	mids := make([]float64, len(values))
+	for i, v := range values {
+		sweep := v / total * 360
+		mids[i] = start - sweep/2
+		if v > 0 {
+			p.use(opts.colour(i))
+			arc := strings.Replace(arcPath(cx, cy, r, r, start, start-sweep), " m\r\n", " l\r\n", 1)
+			fmt.Fprintf(&sb, "%v\r\n%v %v m\r\n%vh\r\nB\r\n", opts.colour(i).operator(false), formatNumber(cx),
+				formatNumber(cy), arc)
+		}
+		start -= sweep
+	}
+	p.content.addGraphics(sb.String() + "0 g\r\n")
+	for i, label := range labels {
+		if values[i] == 0 {
+			continue
+		}
+		sin, cos := math.Sincos(mids[i] * math.Pi / 180)
+		align := AlignCenter
+		if cos > 0.1 {
+			align = AlignLeft
+		} else if cos < -0.1 {
+			align = AlignRight
+		}
+		p.chartText(label, cx+(r+4)*cos, cy+(r+4+0.35*p.fontSize)*sin-0.35*p.fontSize, align)
+	}
+	p.content.addGraphics("Q\r\n")
+	return nil
+}
+
+// checkChartData checks that there is a label for each of n categories, if there are labels, and that the values
+// are all numbers
+func checkChartData(labels []string, n int, values []float64) error {
+	if labels != nil && len(labels) != n {
+		return fmt.Errorf("%w: %v labels for %v values", ErrInvalidChart, len(labels), n)
+	}
+	for _, v := range values {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return fmt.Errorf("%w: value %v", ErrInvalidChart, v)
+		}
+	}
+	return nil
+}
+
+// drawChartAxes saves the graphics state and draws the value axis with its gridlines and tick labels and the
+// category labels for a chart of values w by h points at x, y. It returns the area inside the axes.
+func (p *PdfPage) drawChartAxes(x, y, w, h float64, values []float64, labels []string, opts ChartOptions) chartArea {
+	lo, hi, step := chartScale(values, opts.Ticks)
+	var ticks []float64
+	for v := lo; v <= hi+step/2; v += step {
+		// rounded to a multiple of the step so that errors don't build up
+		ticks = append(ticks, math.Round(v/step)*step)
+	}
+	labelWidth := 0.0
+	for _, v := range ticks {
+		labelWidth = math.Max(labelWidth, p.textWidth(formatNumber(v)))
+	}
+	size := p.fontSize
+	// room for the tick labels, which are centred on the ticks, and the category labels beneath
+	area := chartArea{x: x + labelWidth + 6, y: y + size/2, lo: lo, hi: hi}
+	if labels != nil {
+		area.y = y + size + 4
+	}
+	area.w = x + w - area.x
+	area.h = y + h - size/2 - area.y
+
+	var sb strings.Builder
+	sb.WriteString("q\r\n0.5 w\r\n0.85 G\r\n")
+	for _, v := range ticks[1:] {
+		fmt.Fprintf(&sb, "%v %v m\r\n%v %v l\r\n", formatNumber(area.x), formatNumber(area.valueY(v)),
+			formatNumber(area.x+area.w), formatNumber(area.valueY(v)))
+	}
+	sb.WriteString("S\r\n0 G\r\n")
+	for _, v := range ticks {
+		fmt.Fprintf(&sb, "%v %v m\r\n%v %v l\r\n", formatNumber(area.x-3), formatNumber(area.valueY(v)),
+			formatNumber(area.x), formatNumber(area.valueY(v)))
+	}
+	fmt.Fprintf(&sb, "%v %v m\r\n%v %v l\r\n", formatNumber(area.x), formatNumber(area.y), formatNumber(area.x),
+		formatNumber(area.y+area.h))
+	fmt.Fprintf(&sb, "%v %v m\r\n%v %v l\r\nS\r\n0 g\r\n", formatNumber(area.x), formatNumber(area.valueY(0)),
+		formatNumber(area.x+area.w), formatNumber(area.valueY(0)))
+	p.content.addGraphics(sb.String())
+	for _, v := range ticks {
+		p.chartText(formatNumber(v), area.x-5, area.valueY(v)-0.35*size, AlignRight)
+	}
+	for i, label := range labels {
+		p.chartText(label, area.slotX(i, len(labels)), y+2, AlignCenter)
+	}
+	return area
+}
+
+// chartScale returns the range of the value axis for values and the distance between ticks, which is 1, 2 or 5
+// times a power of 10 giving about ticks intervals. The range always includes zero.
+func chartScale(values []float64, ticks int) (lo, hi, step float64) {
+	if ticks <= 0 {
+		ticks = 5
+	}
+	for _, v := range values {
+		lo, hi = math.Min(lo, v), math.Max(hi, v)
+	}
+	if lo == hi {
+		hi = 1
+	}
+	raw := (hi - lo) / float64(ticks)
+	magnitude := math.Pow(10, math.Floor(math.Log10(raw)))
+	switch norm := raw / magnitude; {
+	case norm <= 1:
+		step = magnitude
+	case norm <= 2:
+		step = 2 * magnitude
+	case norm <= 5:
+		step = 5 * magnitude
+	default:
+		step = 10 * magnitude
+	}
+	return math.Floor(lo/step) * step, math.Ceil(hi/step) * step, step
+}
+
+// chartText draws a label with its baseline at y, aligned to x
+func (p *PdfPage) chartText(text string, x, y float64, align Alignment) {
+	switch align {
+	case AlignCenter:
+		x -= p.textWidth(text) / 2
+	case AlignRight:
+		x -= p.textWidth(text)
+	}
+	p.content.addTextf("1 0 0 1 %v %v Tm\r\n%s Tj\r\n", formatNumber(x), formatNumber(y), p.textString(text))
+}
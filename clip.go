@@ -0,0 +1,79 @@
+package gopdf
+
+// pageState is the part of the graphics state that pages keep track of, which RestoreState puts back
+type pageState struct {
+	font                        *PdfFont
+	fontSize                    float64
+	charSpacing, wordSpacing    float64
+	horizontalScaling, textRise float64
+	lineHeight, leading         float64
+	extGState                   extGStateParams
+}
+
+// state returns the page's current pageState
+func (p *PdfPage) state() pageState {
+	return pageState{p.font, p.fontSize, p.charSpacing, p.wordSpacing, p.horizontalScaling, p.textRise, p.lineHeight,
+		p.leading, p.extGState}
+}
+
+// SaveState saves the graphics state, which includes the colours, line style, font, text spacing, alpha, clipping
+// region and transform, so that RestoreState can put it back. States can be saved inside each other, and any still
+// saved when the page is finished are restored then.
+func (p *PdfPage) SaveState() {
+	p.content.addGraphics("q\r\n")
+	p.savedStates = append(p.savedStates, p.state())
+}
+
+// RestoreState puts back the graphics state saved by the matching call to SaveState, which removes any clipping
+// added since
+func (p *PdfPage) RestoreState() error {
+	if len(p.savedStates) == 0 {
+		return ErrNoSavedState
+	}
+	s := p.savedStates[len(p.savedStates)-1]
+	p.savedStates = p.savedStates[:len(p.savedStates)-1]
+	p.content.addGraphics("Q\r\n")
+	p.font, p.fontSize, p.charSpacing, p.wordSpacing = s.font, s.fontSize, s.charSpacing, s.wordSpacing
+	p.horizontalScaling, p.textRise, p.lineHeight, p.leading = s.horizontalScaling, s.textRise, s.lineHeight, s.leading
+	p.extGState = s.extGState
+	return nil
+}
+
+// closeSavedStates restores every state saved by SaveState that hasn't been restored, so that a page or template
+// finishes in the state it started in
+func (p *PdfPage) closeSavedStates() {
+	for len(p.savedStates) > 0 {
+		p.RestoreState()
+	}
+}
+
+// Transform changes the coordinates of text and graphics drawn afterwards by the matrix [a b c d e f]. The matrix
+// applies to the page's own coordinates, with y increasing up the page from the bottom left corner whatever the
+// coordinate origin: a, b, c and d scale, rotate and skew them and e, f move them by a distance in the document's
+// unit. Transforms combine with each other and last until RestoreState, so they are usually done just after
+// SaveState.
+func (p *PdfPage) Transform(a, b, c, d, e, f float64) {
+	p.content.addGraphicsf("%v cm\r\n", formatNumbers(a, b, c, d, p.pt(e), p.pt(f)))
+}
+
+// ClipRect limits text and graphics drawn afterwards to the rectangle with its corner at x, y. Clipping
+// lasts until RestoreState, so it is usually done just after SaveState. Clipping again intersects the regions.
+func (p *PdfPage) ClipRect(x, y, w, h float64) {
+	p.content.addGraphicsf("%v re\r\nW n\r\n", formatNumbers(p.rect(x, y, w, h)))
+}
+
+// ClipCircle limits text and graphics drawn afterwards to the circle of radius r centred on cx, cy, as for ClipRect
+func (p *PdfPage) ClipCircle(cx, cy, r float64) {
+	cx, cy, r = p.pt(cx), p.ptY(cy, 0), p.pt(r)
+	p.content.addGraphics(arcPath(cx, cy, r, r, 0, 360) + "h\r\nW n\r\n")
+}
+
+// ClipPath limits text and graphics drawn afterwards to the inside of a path, as for ClipRect. The path is built by
+// build with MoveTo, LineTo, CurveTo and ClosePath.
+func (p *PdfPage) ClipPath(build func(p *PdfPage)) {
+	build(p)
+	if p.inPath {
+		p.content.addGraphics("W n\r\n")
+		p.inPath = false
+	}
+}
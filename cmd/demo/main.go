@@ -0,0 +1,112 @@
+// Command demo writes a sample PDF showing the core fonts, an image and some simple graphics.
+//
+// Run it from the repository root so that gopher.jpg can be found:
+//
+//	go run ./cmd/demo > demo.pdf
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/rickymclaren/gopdf"
+)
+
+func main() {
+	document, err := demoDocument("gopher.jpg")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := document.Validate(); err != nil {
+		log.Fatal(err)
+	}
+	if _, err := document.WriteTo(os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// demoDocument builds the sample document, with the image read from the file gopher
+func demoDocument(gopher string) (*gopdf.PdfDocument, error) {
+	var charset [256]byte
+	for i := range charset {
+		charset[i] = byte(i)
+	}
+	document := gopdf.NewPdfDocument()
+	page := document.CurrentPage()
+	fonts := []struct {
+		name string
+		font int
+	}{
+		{"Courier", gopdf.Courier},
+		{"CourierBold", gopdf.CourierBold},
+		{"TimesRoman", gopdf.TimesRoman},
+		{"TimesBold", gopdf.TimesBold},
+		{"Symbol", gopdf.Symbol},
+		{"Dingbats", gopdf.ZapfDingbats},
+	}
+	for _, f := range fonts {
+		if _, err := document.AddFont(f.name, f.font); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := document.AddImage("gopher", gopher); err != nil {
+		return nil, err
+	}
+
+	page.SetFont("CourierBold")
+	page.Println("Courier")
+	page.SetFont("Courier")
+	for i := 0; i < len(charset); i += 16 {
+		s := fmt.Sprintf("%2X %s", i, string(charset[i:i+16]))
+		page.Println(s)
+	}
+	page.Println("")
+
+	page.SetFont("TimesBold")
+	page.Println("Times Roman")
+	page.SetFont("TimesRoman")
+	for i := 0; i < len(charset); i += 16 {
+		s := fmt.Sprintf("%2X %s", i, string(charset[i:i+16]))
+		page.Println(s)
+	}
+	page.Println("")
+
+	page.SetFont("TimesBold")
+	page.Println("Symbol")
+	page.SetFont("Symbol")
+	for i := 0; i < len(charset); i += 16 {
+		s := fmt.Sprintf("%2X %s", i, string(charset[i:i+16]))
+		page.Println(s)
+	}
+	page.Println("")
+
+	page.SetFont("TimesBold")
+	page.Println("Dingbats")
+	page.SetFont("Dingbats")
+	for i := 0; i < len(charset); i += 16 {
+		s := fmt.Sprintf("%2X %s", i, string(charset[i:i+16]))
+		page.Println(s)
+	}
+	page.Println("")
+
+	if err := page.DrawImage("gopher", 250, 550); err != nil {
+		return nil, err
+	}
+	page.SetStrokeColor(0, 0, 255)
+	page.DrawBox(250, 500, 300, 20)
+	page.DrawLine(250, 480, 550, 480)
+
+	page.SetFont("TimesBold")
+	page.SetFontSize(18)
+	page.SetColour(255, 0, 0)
+	page.SetXY(300, 440)
+	page.Print("Red")
+	page.SetColour(0, 255, 0)
+	page.SetXY(300, 420)
+	page.Print("Green")
+	page.SetColour(0, 0, 255)
+	page.SetXY(300, 400)
+	page.Print("Blue")
+	return document, nil
+}
@@ -0,0 +1,13 @@
+package main
+
+import "testing"
+
+func TestDemoDocument(t *testing.T) {
+	document, err := demoDocument("../../gopher.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := document.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
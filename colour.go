@@ -0,0 +1,70 @@
+package gopdf
+
+import "fmt"
+
+// Color is a colour in one of the device colour spaces, an RGBColor, CMYKColor or GrayColor, or a tint of a spot
+// colour
+type Color interface {
+	// operator returns the content stream operator that sets the colour for filling, or for stroking if stroke is
+	// true
+	operator(stroke bool) string
+}
+
+// RGBColor is a colour for screens, with components from 0 to 255
+type RGBColor struct {
+	R, G, B int
+}
+
+func (c RGBColor) operator(stroke bool) string {
+	if stroke {
+		return rgb(c.R, c.G, c.B) + " RG"
+	}
+	return rgb(c.R, c.G, c.B) + " rg"
+}
+
+// CMYKColor is a colour for printing, with the amount of each ink from 0 to 1
+type CMYKColor struct {
+	C, M, Y, K float64
+}
+
+func (c CMYKColor) operator(stroke bool) string {
+	op := "k"
+	if stroke {
+		op = "K"
+	}
+	return fmt.Sprintf("%v %v %v %v %v", formatNumber(c.C), formatNumber(c.M), formatNumber(c.Y), formatNumber(c.K), op)
+}
+
+// GrayColor is a shade of gray from 0 for black to 1 for white
+type GrayColor float64
+
+func (c GrayColor) operator(stroke bool) string {
+	if stroke {
+		return formatNumber(float64(c)) + " G"
+	}
+	return formatNumber(float64(c)) + " g"
+}
+
+// SetFill sets the colour used for text and filled shapes
+func (p *PdfPage) SetFill(c Color) {
+	p.use(c)
+	p.content.addStatef("%v\r\n", c.operator(false))
+}
+
+// SetStroke sets the colour used for lines and the outlines of shapes
+func (p *PdfPage) SetStroke(c Color) {
+	p.use(c)
+	p.content.addStatef("%v\r\n", c.operator(true))
+}
+
+// SetFillColorCMYK sets the colour used for text and filled shapes to the given amounts of cyan, magenta, yellow and
+// black ink, from 0 to 1
+func (p *PdfPage) SetFillColorCMYK(c, m, y, k float64) {
+	p.SetFill(CMYKColor{c, m, y, k})
+}
+
+// SetStrokeColorCMYK sets the colour used for lines and the outlines of shapes to the given amounts of cyan, magenta,
+// yellow and black ink, from 0 to 1
+func (p *PdfPage) SetStrokeColorCMYK(c, m, y, k float64) {
+	p.SetStroke(CMYKColor{c, m, y, k})
+}
@@ -0,0 +1,117 @@
+package gopdf
+
+// columnLayout is the state of the columns started by BeginColumns, with positions in points
+type columnLayout struct {
+	count  int
+	gap    float64
+	index  int     // the column the cursor is in
+	top    float64 // baseline of the first line of each column
+	lowest float64 // lowest cursor position reached in any column on the page
+}
+
+// BeginColumns divides the space between the margins into n columns of equal width with gap between them, starting
+// at the cursor. Text output by Print, Println, PrintAligned, WriteWrapped and WriteWrappedAligned is wrapped to the
+// width of a column and fills the first column down to the bottom margin before continuing at the top of the next.
+// When the last column is full the text continues in the first column of a new page, which becomes the document's
+// current page; text output on this page after that also goes to the new page.
+func (p *PdfPage) BeginColumns(n int, gap float64) {
+	p = p.flow()
+	p.EndColumns()
+	p = p.flow()
+	if n < 2 {
+		return
+	}
+	p.columns = &columnLayout{count: n, gap: p.pt(gap), top: p.y, lowest: p.y}
+	p.x = p.lineLeft()
+}
+
+// EndColumns ends the columns started by BeginColumns and moves the cursor to the left margin below the tallest
+// column. When a column reached the bottom margin text continues at the top of a new page.
+func (p *PdfPage) EndColumns() {
+	p = p.flow()
+	c := p.columns
+	if c == nil {
+		return
+	}
+	p.columns = nil
+	p.x, p.y = p.leftMargin, min(c.lowest, p.y)
+	if p.y < p.bottomMargin {
+		p.next = p.continuation(p.fontName(), p.fontSize)
+	}
+}
+
+// SetColumnRelative sets whether images and boxes drawn while text is in columns are positioned relative to the
+// left edge of the current column instead of the left edge of the page
+func (p *PdfPage) SetColumnRelative(on bool) {
+	p.columnRelative = on
+}
+
+// columnX converts the horizontal position in points of an image or box to a position on the page
+func (p *PdfPage) columnX(x float64) float64 {
+	if p.columnRelative && p.columns != nil {
+		return x + p.lineLeft()
+	}
+	return x
+}
+
+// lineLeft returns the position in points where lines start, which is the left margin or the left edge of the
+// current column
+func (p *PdfPage) lineLeft() float64 {
+	c := p.columns
+	if c == nil {
+		return p.leftMargin
+	}
+	return p.leftMargin + float64(c.index)*(p.columnWidth()+c.gap)
+}
+
+// lineRight returns the position in points where lines end, which is the right margin or the right edge of the
+// current column
+func (p *PdfPage) lineRight() float64 {
+	if p.columns == nil {
+		return p.width - p.rightMargin
+	}
+	return p.lineLeft() + p.columnWidth()
+}
+
+// columnWidth returns the width in points of each column
+func (p *PdfPage) columnWidth() float64 {
+	c := p.columns
+	return (p.width - p.leftMargin - p.rightMargin - float64(c.count-1)*c.gap) / float64(c.count)
+}
+
+// nextColumn moves the cursor to the top of the next column, or to the first column of a new page after the last
+// column
+func (p *PdfPage) nextColumn() {
+	c := p.columns
+	c.lowest = min(c.lowest, p.y)
+	if p.measure != nil {
+		p.measure.pageBreaks++
+	}
+	if c.index < c.count-1 {
+		c.index++
+		p.x, p.y = p.lineLeft(), c.top
+		return
+	}
+	next := p.continuation(p.fontName(), p.fontSize)
+	next.columns = &columnLayout{count: c.count, gap: c.gap, top: next.y, lowest: next.y}
+	next.columnRelative = p.columnRelative
+	next.x = next.lineLeft()
+	p.columns = nil
+	p.next = next
+}
+
+// flow returns the page that text output on p goes to, which is a later page when columns have filled p
+func (p *PdfPage) flow() *PdfPage {
+	for p.next != nil {
+		p = p.next
+	}
+	return p
+}
+
+// fontName returns the name of the current font, or an empty string before a font has been set
+func (p *PdfPage) fontName() string {
+	if p.font == nil {
+		return ""
+	}
+	return p.font.name
+}
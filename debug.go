@@ -0,0 +1,330 @@
+package gopdf
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// SetDebugContent turns debugging comments in content streams on or off. When it is on, the content streams of pages,
+// templates and patterns are written uncompressed, and each group of operators added to them is followed by a comment
+// naming the method that added it and the file and line it was called from, such as
+// "% PdfPage.DrawLine at main.go:42". The comments are left out when it is off, which is the default.
+func (d *PdfDocument) SetDebugContent(on bool) {
+	d.debugContent = on
+}
+
+// compressContent reports whether content streams are compressed
+func (d *PdfDocument) compressContent() bool {
+	return !d.noCompression && !d.debugContent
+}
+
+// packagePrefix is what the names of the functions of this package start with in a stack trace
+var packagePrefix = func() string {
+	pc, _, _, _ := runtime.Caller(0)
+	name := runtime.FuncForPC(pc).Name()
+	slash := strings.LastIndex(name, "/") + 1
+	return name[:slash+strings.Index(name[slash:], ".")+1]
+}()
+
+// callSite returns a comment naming the method of the package that was called from outside it and where it was
+// called from, for the operators just added to a content stream
+func callSite() string {
+	pcs := make([]uintptr, 64)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	method := ""
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, packagePrefix) {
+			if method == "" {
+				break
+			}
+			return fmt.Sprintf("%% %v at %v:%v\r\n", method, filepath.Base(frame.File), frame.Line)
+		}
+		// the method called from outside the package is the last one of the package on the stack
+		method = strings.NewReplacer("(*", "", ")", "").Replace(strings.TrimPrefix(frame.Function, packagePrefix))
+		if !more {
+			break
+		}
+	}
+	return ""
+}
+
+// DebugDump writes an outline of the document to w as it would be written, for finding out why a viewer shows it
+// differently from what was expected. Each object is listed with its number, type and dictionary entries, and the
+// operators of content streams are decompressed and indented by the graphics states saved with q and the text
+// objects begun with BT. A summary of the fonts, images and templates each page uses follows. An encrypted document
+// is shown as it would be without encryption.
+func (d *PdfDocument) DebugDump(w io.Writer) error {
+	var buf bytes.Buffer
+	_, err := d.write(context.Background(), &buf, func() (func(), error) {
+		e := d.encryption
+		d.encryption = nil
+		return func() { d.encryption = e }, nil
+	})
+	if err != nil {
+		return err
+	}
+	pr, err := newPDFReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		return err
+	}
+	pages, err := pr.pages()
+	if err != nil {
+		return err
+	}
+	content := map[int]bool{}
+	for _, page := range pages {
+		contents := page.dict.get("Contents")
+		if list, ok := pr.resolve(contents).([]any); ok {
+			for _, ref := range list {
+				if ref, ok := ref.(pdfRef); ok {
+					content[ref.num] = true
+				}
+			}
+		} else if ref, ok := contents.(pdfRef); ok {
+			content[ref.num] = true
+		}
+	}
+
+	out := &countingWriter{w: w}
+	fmt.Fprintf(out, "PDF-%v, %v bytes\n", d.version(), buf.Len())
+	nums := make([]int, 0, len(pr.xref))
+	for num, e := range pr.xref {
+		if !e.free {
+			nums = append(nums, num)
+		}
+	}
+	sort.Ints(nums)
+	for _, num := range nums {
+		v, err := pr.object(num)
+		if err != nil {
+			return err
+		}
+		dict, _ := v.(pdfDict)
+		s, isStream := v.(*pdfStream)
+		if isStream {
+			dict = s.dict
+		}
+		fmt.Fprintf(out, "\n%v 0 obj %v\n", num, objectType(v, dict))
+		if dict == nil {
+			fmt.Fprintf(out, "  %v\n", shorten(formatValue(v)))
+		}
+		for _, e := range dict {
+			fmt.Fprintf(out, "  %v %v\n", formatName(string(e.key)), shorten(formatValue(e.value)))
+		}
+		if !isStream {
+			continue
+		}
+		subtype, _ := dict.get("Subtype").(pdfName)
+		patternType, _ := dict.get("PatternType").(int)
+		if !content[num] && subtype != "Form" && patternType != 1 {
+			fmt.Fprintf(out, "  stream of %v bytes\n", len(s.data))
+			continue
+		}
+		data, err := pr.decode(s)
+		if err != nil {
+			fmt.Fprintf(out, "  content stream of %v bytes that can't be decoded: %v\n", len(s.data), err)
+			continue
+		}
+		fmt.Fprintf(out, "  content stream of %v bytes, %v decoded:\n", len(s.data), len(data))
+		out.Write(formatContent(data, "    "))
+	}
+
+	for i, page := range pages {
+		fmt.Fprintf(out, "\nPage %v, object %v\n", i+1, page.num)
+		data, err := pr.pageContent(page.dict)
+		if err != nil {
+			return err
+		}
+		resources, _ := pr.resolve(page.dict.get("Resources")).(pdfDict)
+		fonts, _ := pr.resolve(resources.get("Font")).(pdfDict)
+		xobjects, _ := pr.resolve(resources.get("XObject")).(pdfDict)
+		var fontNames, images, forms []string
+		for _, used := range usedResources(data) {
+			switch used.kind {
+			case "Font":
+				font, _ := pr.resolve(fonts.get(used.name)).(pdfDict)
+				base, ok := font.get("BaseFont").(pdfName)
+				if !ok {
+					base = "missing from the page's resources"
+				}
+				fontNames = append(fontNames, fmt.Sprintf("%v %v", formatName(string(used.name)), base))
+			case "XObject":
+				x, _ := pr.resolve(xobjects.get(used.name)).(*pdfStream)
+				if x == nil {
+					forms = append(forms, formatName(string(used.name))+" missing from the page's resources")
+				} else if x.dict.get("Subtype") == pdfName("Image") {
+					images = append(images, fmt.Sprintf("%v %vx%v", formatName(string(used.name)),
+						formatValue(x.dict.get("Width")), formatValue(x.dict.get("Height"))))
+				} else {
+					forms = append(forms, formatName(string(used.name)))
+				}
+			}
+		}
+		for _, list := range []struct {
+			title string
+			names []string
+		}{{"fonts", fontNames}, {"images", images}, {"templates", forms}} {
+			if len(list.names) > 0 {
+				fmt.Fprintf(out, "  %v: %v\n", list.title, strings.Join(list.names, ", "))
+			}
+		}
+	}
+	return out.err
+}
+
+// objectType describes an object by its Type and Subtype, or by what kind of value it is if it has no Type
+func objectType(v any, dict pdfDict) string {
+	if t, ok := dict.get("Type").(pdfName); ok {
+		if subtype, ok := dict.get("Subtype").(pdfName); ok {
+			return fmt.Sprintf("%v %v", formatName(string(t)), formatName(string(subtype)))
+		}
+		return formatName(string(t))
+	}
+	switch v.(type) {
+	case *pdfStream:
+		return "stream"
+	case pdfDict:
+		return "dictionary"
+	case []any:
+		return "array"
+	}
+	return "value"
+}
+
+// shorten cuts a value that is too long to read at a glance, such as a long array of widths, down to its start
+func shorten(s string) string {
+	if len(s) > 100 {
+		return s[:97] + "..."
+	}
+	return s
+}
+
+// usedResource is a resource named by an operator of a content stream
+type usedResource struct {
+	kind, name pdfName
+}
+
+// usedResources returns the resources named by the operators of content, in the order they are first used
+func usedResources(content []byte) []usedResource {
+	var used []usedResource
+	seen := map[usedResource]bool{}
+	var operands []any
+	p := &pdfParser{data: content}
+	for {
+		p.skipSpace()
+		if p.pos >= len(content) {
+			return used
+		}
+		start := p.pos
+		if !isPDFDelimiter(content[p.pos]) {
+			token := p.keyword()
+			if !isOperand(token) {
+				if token == "ID" {
+					p.pos = inlineImageEnd(content, p.pos)
+				}
+				if kind := resourceOperators[token]; kind != "" && len(operands) > 0 {
+					name, _ := operands[len(operands)-1].(pdfName)
+					if token == "Tf" {
+						name, _ = operands[0].(pdfName)
+					}
+					if r := (usedResource{kind, name}); name != "" && !seen[r] {
+						seen[r] = true
+						used = append(used, r)
+					}
+				}
+				operands = operands[:0]
+				continue
+			}
+			p.pos = start
+		}
+		v, err := p.value()
+		if err != nil {
+			return used
+		}
+		operands = append(operands, v)
+	}
+}
+
+// isOperand reports whether a token of regular characters in a content stream is an operand rather than an operator
+func isOperand(token string) bool {
+	if token == "true" || token == "false" || token == "null" {
+		return true
+	}
+	for _, c := range token {
+		if (c < '0' || c > '9') && c != '.' && c != '-' && c != '+' {
+			return false
+		}
+	}
+	return token != ""
+}
+
+// formatContent returns the operators of a content stream one to a line after indent, indented further inside the
+// graphics states saved with q and the text objects begun with BT. Comments are kept on lines of their own, and the
+// data of inline images is replaced by its length.
+func formatContent(content []byte, indent string) []byte {
+	var out bytes.Buffer
+	depth := 0
+	var operands []string
+	line := func(s string) {
+		fmt.Fprintf(&out, "%v%v%v\n", indent, strings.Repeat("  ", depth), s)
+	}
+	p := &pdfParser{data: content}
+	for {
+		for p.pos < len(content) && isPDFSpace(content[p.pos]) {
+			p.pos++
+		}
+		if p.pos >= len(content) {
+			break
+		}
+		start := p.pos
+		if content[p.pos] == '%' {
+			for p.pos < len(content) && content[p.pos] != '\r' && content[p.pos] != '\n' {
+				p.pos++
+			}
+			line(string(content[start:p.pos]))
+			continue
+		}
+		if !isPDFDelimiter(content[p.pos]) {
+			token := p.keyword()
+			if !isOperand(token) {
+				if token == "Q" || token == "ET" || token == "EMC" {
+					depth = max(depth-1, 0)
+				}
+				if token == "BI" {
+					// an inline image is shown on one line
+					operands = append(operands, token)
+					continue
+				}
+				if token == "ID" {
+					end := inlineImageEnd(content, p.pos)
+					operands = append(operands, fmt.Sprintf("ID <%v bytes>", max(end-p.pos-2, 0)))
+					p.pos, token = min(end+2, len(content)), "EI"
+				}
+				line(strings.Join(append(operands, token), " "))
+				if token == "q" || token == "BT" || token == "BMC" || token == "BDC" {
+					depth++
+				}
+				operands = operands[:0]
+				continue
+			}
+			p.pos = start
+		}
+		if _, err := p.value(); err != nil {
+			// show the rest as it is rather than stopping
+			line(strings.Join(append(operands, string(content[start:])), " "))
+			return out.Bytes()
+		}
+		operands = append(operands, string(content[start:p.pos]))
+	}
+	if len(operands) > 0 {
+		line(strings.Join(operands, " "))
+	}
+	return out.Bytes()
+}
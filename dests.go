@@ -0,0 +1,84 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+)
+
+// namedDestination is a position in the document that links and bookmarks can jump to by name
+type namedDestination struct {
+	name string
+	page *PdfPage
+	y    float64 // in points
+}
+
+// AddNamedDestination names the position at height y on page, so that links within the document or from other
+// documents can jump to it by name
+func (d *PdfDocument) AddNamedDestination(name string, page *PdfPage, y float64) error {
+	for _, dest := range d.catalog.dests {
+		if dest.name == name {
+			return fmt.Errorf("%w: destination %v", ErrDuplicateName, name)
+		}
+	}
+	d.catalog.dests = append(d.catalog.dests, &namedDestination{name: name, page: page, y: d.pageY(page, y)})
+	return nil
+}
+
+// AddNamedLink makes the rectangle with its corner at x, y a link to a named destination. The
+// destination doesn't have to exist yet, but the document can't be written until it does.
+func (p *PdfPage) AddNamedLink(x, y, w, h float64, name string) *PdfLink {
+	x, y, w, h = p.rect(x, y, w, h)
+	l := p.addLink(x, y, w, h, "")
+	l.SetNamedDestination(name)
+	return l
+}
+
+// SetNamedDestination makes an internal link jump to a named destination instead of a page
+func (l *PdfLink) SetNamedDestination(name string) {
+	l.target = nil
+	l.targetName = name
+}
+
+// AddBookmarkToName adds a bookmark that jumps to a named destination, as for AddBookmark
+func (d *PdfDocument) AddBookmarkToName(title, name string, parent *Bookmark) *Bookmark {
+	b := d.AddBookmark(title, nil, 0, parent)
+	b.targetName = name
+	return b
+}
+
+// checkDestinations returns ErrDestinationNotFound if a link or bookmark jumps to a name that hasn't been added
+func (d *PdfDocument) checkDestinations() error {
+	names := make(map[string]bool)
+	for _, dest := range d.catalog.dests {
+		names[dest.name] = true
+	}
+	for _, obj := range d.objects {
+		var name string
+		switch obj := obj.(type) {
+		case *PdfLink:
+			name = obj.targetName
+		case *Bookmark:
+			name = obj.targetName
+		}
+		if name != "" && !names[name] {
+			return fmt.Errorf("%w: %v", ErrDestinationNotFound, name)
+		}
+	}
+	return nil
+}
+
+// destsTree returns the name tree of the named destinations, whose keys must be sorted by their bytes
+func destsTree(dests []*namedDestination) string {
+	sorted := append([]*namedDestination(nil), dests...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].name < sorted[j].name
+	})
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "<< /Names [ ")
+	for _, dest := range sorted {
+		fmt.Fprintf(&buf, "%v %v ", formatString(dest.name), destination(dest.page, dest.y))
+	}
+	fmt.Fprintf(&buf, "] >>")
+	return buf.String()
+}
@@ -0,0 +1,41 @@
+package gopdf
+
+import (
+	"crypto/md5"
+	"time"
+)
+
+// SetDeterministic makes the document come out byte for byte the same each time it is built from the same input, so
+// that it can be compared with a file written before or cached by its contents. Every date in the document is
+// fixedTime instead of the time it was made, including those already set, and the file ID in the trailer is derived
+// from the time and the contents only. Set it before Encrypt, whose key depends on the ID; documents encrypted with
+// EncryptAES256 are never the same twice, since their keys must be random.
+func (d *PdfDocument) SetDeterministic(fixedTime time.Time) {
+	d.fixedTime = &fixedTime
+	if d.metadata != nil {
+		d.metadata.created, d.metadata.modified = fixedTime, fixedTime
+	}
+	for _, spec := range d.catalog.attachments {
+		spec.file.modified = fixedTime
+	}
+}
+
+// now returns the time to record when something is made, the time set by SetDeterministic if there is one
+func (d *PdfDocument) now() time.Time {
+	if d.fixedTime != nil {
+		return *d.fixedTime
+	}
+	return time.Now()
+}
+
+// fileID returns the ID written in the trailer of the document written to cw: the ID the encryption key was made
+// with for an encrypted document, and otherwise a hash of the time and everything written before the trailer
+func (d *PdfDocument) fileID(cw *countingWriter) []byte {
+	if d.encryption != nil {
+		return d.encryption.fileID
+	}
+	h := md5.New()
+	h.Write([]byte(d.now().UTC().Format(time.RFC3339Nano)))
+	h.Write(cw.hash.Sum(nil))
+	return h.Sum(nil)
+}
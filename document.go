@@ -0,0 +1,598 @@
+// Package gopdf generates PDF documents.
+//
+// PDF Structure
+// =============
+//
+//	PdfDocument
+//		PdfInfo
+//		PdfResources
+//			PdfFont
+//			PdfImage
+//			PdfTemplate
+//			PdfWatermark
+//			PdfExtGState
+//			PdfPattern
+//			PdfSpotColor
+//		PdfCatalog
+//			PdfMetadata
+//			PdfOutputIntent
+//				PdfICCProfile
+//			PdfFileSpec
+//				PdfEmbeddedFile
+//			PdfAcroForm
+//				PdfFormField
+//			PdfOutlines
+//				Bookmark
+//			PdfPages
+//				PdfPage
+//					PdfPageContent
+//
+// Drawing Order
+// =============
+//
+// Everything drawn on a page is painted in the order the methods are called: text, lines, shapes and images drawn
+// later cover those drawn earlier, and each shape is stroked or filled with the colours and line style current when
+// it is drawn.
+package gopdf
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"fmt"
+	"hash"
+	"image"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// PdfDocument represents the top level document
+type PdfDocument struct {
+	PdfObject
+	resources       *PdfResources
+	catalog         *PdfCatalog
+	metadata        *PdfInfo
+	encryption      *PdfEncrypt
+	formFont        *PdfFont // Helvetica for form fields and annotations, and for text shown with no font set
+	pdfA            bool
+	pdfVersion      string // set with SetPDFVersion, or empty for the default
+	objectStreams   bool
+	stream          *countingWriter // set while the document is written with StartWriting
+	written         []int64         // offsets of the objects already written by FinishPage, or 0
+	objects         []PdfObjectWriter
+	imageCache      map[string]*PdfImage // images by the path of their file and by a hash of their contents
+	currentPage     *PdfPage
+	pageSize        PageSize
+	orientation     Orientation
+	margins         [4]float64 // left, top, right, bottom in points
+	unit            Unit
+	origin          Origin
+	positioned      atomic.Bool // a position has been given from the origin
+	noInitialPage   bool
+	defaultFont     *PdfFont
+	defaultFontSize float64
+
+	replacement     byte
+	replacementRune rune
+	strictEncoding  bool
+	noSubsetting    bool
+	noCompression   bool
+	asciiImages     bool
+	imageMaxDPI     float64
+	header          func(p *PdfPage, pageNum int)
+	footer          func(p *PdfPage, pageNum, totalPages int)
+	headings        []heading
+	toc             *tocSettings          // set by InsertTOC
+	warnings        []string              // returned by Warnings
+	cutMarks        bool                  // set by SetCutMarks
+	progress        func(done, total int) // set by OnProgress
+	fixedTime       *time.Time            // set by SetDeterministic
+	debugContent    bool                  // set by SetDebugContent
+	// coreCMaps holds the ToUnicode CMaps of the core fonts, by encoding
+	coreCMaps map[string]*PdfToUnicodeCMap
+	// mu guards what pages being built at the same time by AddPages share
+	mu sync.Mutex
+	// err holds the first error from a method that has no error result, it is returned when the document is written
+	err error
+}
+
+func (d *PdfDocument) addObject(o PdfObjectWriter) {
+	o.setID(len(d.objects) + 1)
+	o.setDocument(d)
+	d.objects = append(d.objects, o)
+}
+
+// NewPdfDocument creates a new document with a single A4 page, or configured by the options given
+func NewPdfDocument(opts ...Option) *PdfDocument {
+	return NewPdfDocumentWithPageSize(A4, opts...)
+}
+
+// NewPdfDocumentWithPageSize creates a new single page document using size as the default page size, in the unit set
+// by WithUnit
+func NewPdfDocumentWithPageSize(size PageSize, opts ...Option) *PdfDocument {
+	d := &PdfDocument{pageSize: size, margins: [4]float64{72, 72, 72, 72}, unit: UnitPoint, replacement: '?', replacementRune: '?',
+		defaultFontSize: 10}
+	d.catalog = new(PdfCatalog)
+	d.addObject(d.catalog)
+	d.catalog.pdfPages = new(PdfPages)
+	d.addObject(d.catalog.pdfPages)
+	d.catalog.outlines = new(PdfOutlines)
+	d.addObject(d.catalog.outlines)
+	d.resources = &PdfResources{imagesByName: map[string]*PdfImage{}}
+	d.imageCache = map[string]*PdfImage{}
+	d.addObject(d.resources)
+	for _, opt := range opts {
+		opt(d)
+	}
+	// size is converted once the unit is known, unless WithPageSize has replaced it
+	d.pageSize = d.pageSize.inPoints(d.unit)
+	if !d.noInitialPage {
+		d.AddPage()
+	}
+	return d
+}
+
+// Err returns the first error recorded by a method that does not return an error itself, such as printing an
+// unmappable character in strict encoding mode. The same error is returned when the document is written.
+func (d *PdfDocument) Err() error {
+	return d.err
+}
+
+func (d *PdfDocument) setErr(err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.err == nil {
+		d.err = err
+	}
+}
+
+// SetCompression controls whether page content is compressed. Compression is on by default; turning it off makes
+// the content streams readable in a text editor, which helps when debugging.
+func (d *PdfDocument) SetCompression(compress bool) {
+	d.noCompression = !compress
+}
+
+// SetASCIIImages controls whether image data is written ASCII85 encoded, which makes the file 7 bit clean for tools
+// that need it at the cost of making the images a quarter larger. Images are written as binary by default.
+func (d *PdfDocument) SetASCIIImages(ascii bool) {
+	d.asciiImages = ascii
+}
+
+// CurrentPage returns the page most recently added to the document
+func (d *PdfDocument) CurrentPage() *PdfPage {
+	return d.currentPage
+}
+
+// SetPageSize sets the default size for pages added after this call, in the document's unit unless it is one of
+// the standard sizes
+func (d *PdfDocument) SetPageSize(size PageSize) {
+	d.pageSize = size.inPoints(d.unit)
+}
+
+// SetOrientation sets the default orientation for pages added after this call
+func (d *PdfDocument) SetOrientation(orientation Orientation) {
+	d.orientation = orientation
+}
+
+// SetMargins sets the default margins in the document's unit for pages added after this call
+func (d *PdfDocument) SetMargins(left, top, right, bottom float64) {
+	u := float64(d.unit)
+	d.margins = [4]float64{left * u, top * u, right * u, bottom * u}
+}
+
+// AddPage adds a new page of the default size and orientation to the end of the document and makes it the current page
+func (d *PdfDocument) AddPage() *PdfPage {
+	return d.AddPageWithSize(d.pageSize, d.orientation)
+}
+
+// AddPageWithSize adds a new page of the given size, in the document's unit unless it is one of the standard sizes,
+// and orientation to the end of the document and makes it the current page. Landscape pages have their width and
+// height swapped so that the page is wider than it is tall.
+func (d *PdfDocument) AddPageWithSize(size PageSize, orientation Orientation) *PdfPage {
+	size = size.inPoints(d.unit).oriented(orientation)
+	p := d.newPage(size.Width, size.Height, d.margins)
+	p.parent = d.catalog.pdfPages
+	p.content.page = p
+	d.currentPage = p
+	d.catalog.pdfPages.pages = append(d.catalog.pdfPages.pages, p)
+	d.addObject(p)
+	d.addObject(p.content)
+	return p
+}
+
+// newPage returns a page w by h points with the default font and the cursor at the top left margin, which hasn't
+// been added to the document
+func (d *PdfDocument) newPage(w, h float64, margins [4]float64) *PdfPage {
+	// measurements are in points
+	p := &PdfPage{
+		height:            h,
+		width:             w,
+		leftMargin:        margins[0],
+		topMargin:         margins[1],
+		rightMargin:       margins[2],
+		bottomMargin:      margins[3],
+		font:              d.defaultFont,
+		fontSize:          d.defaultFontSize,
+		horizontalScaling: 100,
+		tabSize:           4,
+		qrQuietZone:       4,
+		extGState:         extGStateParams{fillAlpha: 1, strokeAlpha: 1},
+		cellFill:          RGBColor{230, 230, 230},
+	}
+	p.document = d
+	p.x = p.leftMargin
+	p.y = p.height - p.topMargin - p.fontSize
+	p.content = new(PdfPageContent)
+	p.content.document = d
+	p.content.addGraphics("0.5 w\r\n")
+	if p.font != nil {
+		p.use(p.font)
+		p.content.addTextf("%v %v Tf\r\n", formatName(p.font.name), formatNumber(p.fontSize))
+	}
+	p.content.addTextf("1 0 0 1 %v %v Tm\r\n%v TL\r\n", formatNumber(p.x), formatNumber(p.y),
+		formatNumber(p.fontSize))
+	return p
+}
+
+// AddFont adds one of the 14 core fonts to the document under the given name
+func (d *PdfDocument) AddFont(name string, id int) (*PdfFont, error) {
+	if err := d.checkFontName(name); err != nil {
+		return nil, err
+	}
+	font, err := NewFont(name, id)
+	if err != nil {
+		return nil, err
+	}
+	d.addCoreFont(&font)
+	d.resources.fonts = append(d.resources.fonts, &font)
+	return &font, nil
+}
+
+// addCoreFont adds one of the 14 core fonts to the document along with the ToUnicode CMap for its encoding, which
+// is added once and shared by the fonts that use it
+func (d *PdfDocument) addCoreFont(font *PdfFont) {
+	d.addObject(font)
+	key, mapping := coreFontUnicode(font)
+	cmap := d.coreCMaps[key]
+	if cmap == nil {
+		cmap = &PdfToUnicodeCMap{mapping: mapping}
+		d.addObject(cmap)
+		if d.coreCMaps == nil {
+			d.coreCMaps = map[string]*PdfToUnicodeCMap{}
+		}
+		d.coreCMaps[key] = cmap
+	}
+	font.toUnicode = cmap
+}
+
+// checkFontName returns ErrDuplicateName if a font has already been added as name
+func (d *PdfDocument) checkFontName(name string) error {
+	for _, f := range d.resources.fonts {
+		if f.name == name {
+			return fmt.Errorf("%w: font %v", ErrDuplicateName, name)
+		}
+	}
+	return nil
+}
+
+// checkImageName returns ErrDuplicateName if an image or template has already been added as name, since they share
+// the names of the XObject resources
+func (d *PdfDocument) checkImageName(name string) error {
+	if name == watermarkName {
+		return fmt.Errorf("%w: %v is reserved for the watermark", ErrDuplicateName, name)
+	}
+	if _, ok := d.resources.imagesByName[name]; ok {
+		return fmt.Errorf("%w: image %v", ErrDuplicateName, name)
+	}
+	for _, t := range d.resources.templates {
+		if t.name == name {
+			return fmt.Errorf("%w: template %v", ErrDuplicateName, name)
+		}
+	}
+	return nil
+}
+
+// AddImage loads an image file and adds it to the document under the given name. An image is only loaded and
+// written once however many times it is added: adding the same file or the same contents again, under the same name
+// or another one, returns the image already added.
+func (d *PdfDocument) AddImage(name string, filename string) (*PdfImage, error) {
+	if i, ok := d.imageCache["file:"+filename]; ok {
+		return d.nameImage(name, i)
+	}
+	file, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("gopdf: loading image %v: %w", name, err)
+	}
+	i, err := d.addImageData(name, filename, file)
+	if err != nil {
+		return nil, err
+	}
+	d.imageCache["file:"+filename] = i
+	return i, nil
+}
+
+// AddImageFromReader reads an image in any of the supported formats from r and adds it to the document under the
+// given name. As for AddImage, adding the same contents again returns the image already added.
+func (d *PdfDocument) AddImageFromReader(name string, r io.Reader) (*PdfImage, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("gopdf: loading image %v: %w", name, err)
+	}
+	return d.addImageData(name, "reader", data)
+}
+
+// addImageData adds the image in the contents of an image file, unless the same contents have been added already
+func (d *PdfDocument) addImageData(name string, source string, data []byte) (*PdfImage, error) {
+	sum := sha256.Sum256(data)
+	key := "sha256:" + string(sum[:])
+	if i, ok := d.imageCache[key]; ok {
+		return d.nameImage(name, i)
+	}
+	if err := d.checkImageName(name); err != nil {
+		return nil, err
+	}
+	i := &PdfImage{}
+	// the document is set first so that encoding the pixels reports progress
+	i.setDocument(d)
+	if err := i.loadImageData(name, source, data); err != nil {
+		return nil, err
+	}
+	d.setSource(i, func() (image.Image, error) { return decodeImage(data) })
+	d.addImage(i)
+	d.imageCache[key] = i
+	return i, nil
+}
+
+// nameImage makes an image already added available under name as well
+func (d *PdfDocument) nameImage(name string, i *PdfImage) (*PdfImage, error) {
+	if d.resources.imagesByName[name] == i {
+		return i, nil
+	}
+	if err := d.checkImageName(name); err != nil {
+		return nil, err
+	}
+	i.aliases = append(i.aliases, name)
+	d.resources.imagesByName[name] = i
+	return i, nil
+}
+
+// addImage adds a loaded image to the document resources
+func (d *PdfDocument) addImage(i *PdfImage) {
+	d.addObject(i)
+	d.resources.images = append(d.resources.images, i)
+	d.resources.imagesByName[i.name] = i
+}
+
+// AddImageFromImage adds img to the document under the given name
+func (d *PdfDocument) AddImageFromImage(name string, img image.Image) (*PdfImage, error) {
+	if err := d.checkImageName(name); err != nil {
+		return nil, err
+	}
+	i := &PdfImage{name: name}
+	i.setDocument(d)
+	if err := i.loadPixels(img); err != nil {
+		return nil, err
+	}
+	d.setSource(i, func() (image.Image, error) { return img, nil })
+	d.addImage(i)
+	return i, nil
+}
+
+// Bytes returns the byte representation of the PdfDocument
+func (d *PdfDocument) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := d.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteTo writes the PdfDocument to w, returning the number of bytes written.
+// It implements io.WriterTo.
+func (d *PdfDocument) WriteTo(w io.Writer) (int64, error) {
+	return d.write(context.Background(), w, nil)
+}
+
+// WriteToContext writes the PdfDocument to w as for WriteTo, stopping with the error of ctx if it is cancelled
+// before the whole document has been written. The context is checked before each object, and a document that is
+// stopped is left without its cross-reference table and trailer, so what has been written isn't a valid PDF file.
+func (d *PdfDocument) WriteToContext(ctx context.Context, w io.Writer) (int64, error) {
+	return d.write(ctx, w, nil)
+}
+
+// OnProgress sets a function called as the document is written with the number of objects written so far and the
+// number there are in all. It is also called while the pixels of an image are compressed, when the image is added or
+// resampled, with the number of rows of the image done so far and its height. nil turns it off.
+func (d *PdfDocument) OnProgress(f func(done, total int)) {
+	d.progress = f
+}
+
+// write writes the document to w, stopping when ctx is cancelled, with its pages arranged on sheets by impose when it
+// isn't nil. impose returns a function that puts the pages back.
+func (d *PdfDocument) write(ctx context.Context, w io.Writer, impose func() (restore func(), err error)) (int64,
+	error) {
+	if d.stream != nil {
+		return 0, ErrStreaming
+	}
+	if d.err != nil {
+		return 0, d.err
+	}
+	removeTOC, err := d.insertTOC()
+	defer removeTOC()
+	if err != nil {
+		return 0, err
+	}
+	defer d.addHeadersAndFooters()()
+	if d.err != nil {
+		return 0, d.err
+	}
+	defer d.addPageResources()()
+	if err := d.checkPDFA(); err != nil {
+		return 0, err
+	}
+	d.downsampleImages()
+	if err := d.checkDestinations(); err != nil {
+		return 0, err
+	}
+	if impose != nil {
+		restore, err := impose()
+		defer restore()
+		if err != nil {
+			return 0, err
+		}
+	}
+	cw := &countingWriter{w: w, ctx: ctx, hash: md5.New()}
+	if cw.cancelled() {
+		return 0, cw.err
+	}
+	d.writeHeader(cw)
+	d.writeBody(cw, nil)
+	return cw.n, cw.err
+}
+
+// writeHeader writes the header that starts the file
+func (d *PdfDocument) writeHeader(cw *countingWriter) {
+	fmt.Fprintf(cw, "%%PDF-%v\r\n", d.version())
+	// a comment of bytes above 127 so that the file is treated as binary
+	cw.Write([]byte{'%', 0xE2, 0xE3, 0xCF, 0xD3, '\r', '\n'})
+}
+
+// writeObject writes the serialized object with the given id, encrypting it if needed, and returns its offset
+func (d *PdfDocument) writeObject(cw *countingWriter, id int, data []byte) int64 {
+	offset := cw.n
+	if d.encryption != nil && d.objects[id-1] != d.encryption {
+		data = d.encryption.encryptObject(id, data)
+	}
+	cw.Write(data)
+	return offset
+}
+
+// writeBody writes the objects of the document followed by the cross-reference table and trailer. Objects with an
+// offset in written have already been written by FinishPage and are only listed in the cross-reference table.
+func (d *PdfDocument) writeBody(cw *countingWriter, written []int64) {
+	if d.objectStreams {
+		d.writeObjectStreams(cw, written)
+		return
+	}
+
+	xref := make([]int64, len(d.objects))
+	for i := range d.objects {
+		if cw.cancelled() {
+			return
+		}
+		if i < len(written) && written[i] != 0 {
+			xref[i] = written[i]
+		} else {
+			xref[i] = d.writeObject(cw, i+1, d.objects[i].bytes())
+		}
+		d.reportProgress(i+1, len(d.objects))
+	}
+
+	startxref := cw.n
+
+	fmt.Fprintf(cw, "xref\r\n")
+	fmt.Fprintf(cw, "0 %v\r\n", len(xref)+1)
+	fmt.Fprintf(cw, "0000000000 65535 f\r\n")
+	for i := range xref {
+		fmt.Fprintf(cw, "%010d 00000 n\r\n", xref[i])
+	}
+	fmt.Fprintf(cw, "trailer\r\n")
+	fmt.Fprintf(cw, "<<\r\n")
+	// the size counts the free entry for object 0 as well as the objects
+	fmt.Fprintf(cw, "/Size %v\r\n", len(xref)+1)
+	fmt.Fprint(cw, d.trailerEntries(cw))
+	fmt.Fprintf(cw, ">>\r\n")
+	fmt.Fprintf(cw, "startxref\r\n")
+	fmt.Fprintf(cw, "%v\r\n", startxref)
+	fmt.Fprintf(cw, "%%%%EOF\r\n")
+}
+
+// trailerEntries returns the entries of the trailer other than /Size, which also go in a cross-reference stream, for
+// a document written to cw
+func (d *PdfDocument) trailerEntries(cw *countingWriter) string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "/Root %v\r\n", d.catalog.objectRef())
+	if d.metadata != nil {
+		fmt.Fprintf(&buf, "/Info %v\r\n", d.metadata.objectRef())
+	}
+	id := d.fileID(cw)
+	if d.encryption != nil {
+		fmt.Fprintf(&buf, "/Encrypt %v\r\n", d.encryption.objectRef())
+	}
+	fmt.Fprintf(&buf, "/ID [ <%X> <%X> ]\r\n", id, id)
+	return buf.String()
+}
+
+// SetPDFVersion sets the PDF version written in the header, 1.4 by default. Features that need a later version,
+// such as AES-256 encryption and object streams, raise the version the document is written with.
+func (d *PdfDocument) SetPDFVersion(major, minor int) error {
+	if !(major == 1 && minor >= 0 && minor <= 7) && !(major == 2 && minor == 0) {
+		return fmt.Errorf("gopdf: unsupported PDF version %v.%v", major, minor)
+	}
+	d.pdfVersion = fmt.Sprintf("%v.%v", major, minor)
+	return nil
+}
+
+// version returns the PDF version to write in the header, the version set with SetPDFVersion unless the features
+// used need a later one
+func (d *PdfDocument) version() string {
+	version := "1.4"
+	if d.pdfVersion != "" {
+		version = d.pdfVersion
+	}
+	required := "1.0"
+	switch {
+	case d.encryption != nil && d.encryption.revision == 6:
+		required = "1.7"
+	case d.objectStreams:
+		required = "1.5"
+	}
+	// versions are all a single digit, a point and another digit, so they compare as strings
+	if required > version {
+		return required
+	}
+	return version
+}
+
+// reportProgress calls the function set by OnProgress, if there is one
+func (d *PdfDocument) reportProgress(done, total int) {
+	if d.progress != nil {
+		d.progress(done, total)
+	}
+}
+
+// countingWriter keeps track of the number of bytes written so that the xref offsets can be recorded.
+// After the first error all further writes are discarded and the error is kept.
+type countingWriter struct {
+	w    io.Writer
+	n    int64
+	err  error
+	ctx  context.Context // stops the writing when it is cancelled, or nil
+	hash hash.Hash       // a hash of everything written, from which the file ID is derived, or nil
+}
+
+// cancelled keeps the error of the writer's context once it has been cancelled, so that nothing more is written,
+// and reports whether writing has stopped
+func (cw *countingWriter) cancelled() bool {
+	if cw.err == nil && cw.ctx != nil {
+		cw.err = cw.ctx.Err()
+	}
+	return cw.err != nil
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	if cw.err != nil {
+		return 0, cw.err
+	}
+	n, err := cw.w.Write(p)
+	if cw.hash != nil {
+		cw.hash.Write(p[:n])
+	}
+	cw.n += int64(n)
+	cw.err = err
+	return n, err
+}
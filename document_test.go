@@ -0,0 +1,155 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"testing"
+)
+
+// testDocument returns a document of three pages using two fonts and an image
+func testDocument(t *testing.T) *PdfDocument {
+	t.Helper()
+	d := NewPdfDocument()
+	for _, f := range []struct {
+		name string
+		id   int
+	}{{"Helv", Helvetica}, {"Times", TimesRoman}} {
+		if _, err := d.AddFont(f.name, f.id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := d.AddImage("gopher", "gopher.jpg"); err != nil {
+		t.Fatal(err)
+	}
+	p := d.CurrentPage()
+	p.SetFont("Helv")
+	p.Println("first")
+	p = d.AddPage()
+	p.SetFont("Helv")
+	p.Println("second")
+	if err := p.DrawImage("gopher", 100, 400); err != nil {
+		t.Fatal(err)
+	}
+	p = d.AddPage()
+	p.SetFont("Helv")
+	p.Println("third")
+	return d
+}
+
+func TestXrefOffsets(t *testing.T) {
+	data, err := testDocument(t).Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// read the table directly, rather than with the reader, which forgives some mistakes
+	at := bytes.LastIndex(data, []byte("startxref\r\n"))
+	if at < 0 {
+		t.Fatal("no startxref")
+	}
+	line := data[at+len("startxref\r\n"):]
+	start, err := strconv.Atoi(string(line[:bytes.IndexByte(line, '\r')]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	table := data[start:]
+	var first, count int
+	if _, err := fmt.Sscanf(string(table[:64]), "xref\r\n%d %d\r\n", &first, &count); err != nil || first != 0 {
+		t.Fatalf("bad xref header %q: %v", table[:32], err)
+	}
+	entries := table[bytes.Index(table, []byte("65535 f\r\n"))+len("65535 f\r\n"):]
+	for num := 1; num < count; num++ {
+		// every entry is exactly 20 bytes long
+		entry := entries[20*(num-1) : 20*num]
+		if len(entry) != 20 || string(entry[10:]) != " 00000 n\r\n" {
+			t.Fatalf("bad entry for object %v: %q", num, entry)
+		}
+		offset, _ := strconv.Atoi(string(entry[:10]))
+		if prefix := fmt.Sprintf("%v 0 obj\r\n", num); !bytes.HasPrefix(data[offset:], []byte(prefix)) {
+			t.Errorf("object %v: offset %v points at %q", num, offset, excerpt(data, offset))
+		}
+	}
+	if !bytes.Contains(entries[20*(count-1):], []byte(fmt.Sprintf("/Size %v\r\n", count))) {
+		t.Errorf("trailer Size isn't %v", count)
+	}
+}
+
+// excerpt returns the bytes of data around at
+func excerpt(data []byte, at int) []byte {
+	return data[max(at-40, 0):min(at+40, len(data))]
+}
+
+func TestXrefStreamOffsets(t *testing.T) {
+	d := testDocument(t)
+	d.SetObjectStreams(true)
+	data, err := d.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if problems := validatePDF(data); len(problems) > 0 {
+		t.Error(problems)
+	}
+}
+
+func TestResourceDictionaries(t *testing.T) {
+	data, err := testDocument(t).Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pr, err := parsePDF(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pages, err := pr.pages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := func(page sourcePage, kind pdfName) string {
+		resources, _ := pr.resolve(page.dict.get("Resources")).(pdfDict)
+		dict, _ := pr.resolve(resources.get(kind)).(pdfDict)
+		var keys []string
+		for _, e := range dict {
+			keys = append(keys, string(e.key))
+		}
+		return fmt.Sprint(keys)
+	}
+	for i, want := range []struct{ fonts, xobjects string }{{"[Helv]", "[]"}, {"[Helv]", "[gopher]"}, {"[Helv]", "[]"}} {
+		if got := names(pages[i], "Font"); got != want.fonts {
+			t.Errorf("page %v fonts %v, want %v", i+1, got, want.fonts)
+		}
+		if got := names(pages[i], "XObject"); got != want.xobjects {
+			t.Errorf("page %v images %v, want %v", i+1, got, want.xobjects)
+		}
+	}
+	// pages that use the same resources share a dictionary
+	if r1, r3 := pages[0].dict.get("Resources"), pages[2].dict.get("Resources"); r1 != r3 {
+		t.Errorf("pages 1 and 3 have resources %v and %v", r1, r3)
+	}
+	if r1, r2 := pages[0].dict.get("Resources"), pages[1].dict.get("Resources"); r1 == r2 {
+		t.Errorf("pages 1 and 2 share resources %v", r1)
+	}
+}
+
+func TestHeaderBytes(t *testing.T) {
+	for _, c := range []struct {
+		objectStreams bool
+		want          []byte
+	}{
+		{false, []byte{'%', 'P', 'D', 'F', '-', '1', '.', '4', '\r', '\n', '%', 0xE2, 0xE3, 0xCF, 0xD3, '\r', '\n'}},
+		{true, []byte{'%', 'P', 'D', 'F', '-', '1', '.', '5', '\r', '\n', '%', 0xE2, 0xE3, 0xCF, 0xD3, '\r', '\n'}},
+	} {
+		d := NewPdfDocument()
+		d.SetObjectStreams(c.objectStreams)
+		data, err := d.Bytes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		// the version line, then a comment of four bytes above 127 so that tools treat the file as binary
+		if got := data[:len(c.want)]; !bytes.Equal(got, c.want) {
+			t.Errorf("header % X, want % X", got, c.want)
+		}
+		if data[len(c.want)] < '1' || data[len(c.want)] > '9' {
+			t.Errorf("the header is followed by %q, not the first object", excerpt(data, len(c.want)))
+		}
+	}
+}
@@ -0,0 +1,464 @@
+package gopdf
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/rc4"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"strconv"
+)
+
+// Permissions controls what a user who opens an encrypted document with the user password may do with it
+type Permissions uint32
+
+// Permission flags, which can be combined with |
+const (
+	PermPrint            Permissions = 1 << 2
+	PermModify           Permissions = 1 << 3
+	PermCopy             Permissions = 1 << 4
+	PermAnnotate         Permissions = 1 << 5
+	PermFillForms        Permissions = 1 << 8
+	PermExtract          Permissions = 1 << 9
+	PermAssemble         Permissions = 1 << 10
+	PermPrintHighQuality Permissions = 1 << 11
+
+	PermAll = PermPrint | PermModify | PermCopy | PermAnnotate | PermFillForms | PermExtract | PermAssemble |
+		PermPrintHighQuality
+)
+
+// passwordPadding pads passwords to 32 bytes, from the standard security handler
+var passwordPadding = []byte{
+	0x28, 0xBF, 0x4E, 0x5E, 0x4E, 0x75, 0x8A, 0x41, 0x64, 0x00, 0x4E, 0x56, 0xFF, 0xFA, 0x01, 0x08,
+	0x2E, 0x2E, 0x00, 0xB6, 0xD0, 0x68, 0x3E, 0x80, 0x2F, 0x0C, 0xA9, 0xFE, 0x64, 0x53, 0x69, 0x7A,
+}
+
+// PdfEncrypt is the encryption dictionary of the standard security handler
+type PdfEncrypt struct {
+	PdfObject
+	keyLength   int // bytes
+	revision    int
+	owner, user []byte
+	permissions int32
+	fileID      []byte
+	key         []byte
+
+	// revision 6 only
+	ownerKey, userKey []byte // the file key encrypted with keys from the passwords, OE and UE
+	perms             []byte
+}
+
+// Encrypt protects the document with RC4 encryption using a 128 bit key. Viewers ask for a password when it is
+// opened: the user password gives the permissions listed, and the owner password gives full access. The user
+// password can be empty so that the document opens without one but still restricts what can be done with it.
+func (d *PdfDocument) Encrypt(userPw, ownerPw string, permissions Permissions) {
+	d.EncryptWithKeyLength(userPw, ownerPw, permissions, 128)
+}
+
+// EncryptWithKeyLength is Encrypt with a choice of key length, either 40 bits for compatibility with very old
+// viewers or 128 bits
+func (d *PdfDocument) EncryptWithKeyLength(userPw, ownerPw string, permissions Permissions, bits int) error {
+	if bits != 40 && bits != 128 {
+		return fmt.Errorf("gopdf: unsupported encryption key length %v", bits)
+	}
+	if ownerPw == "" {
+		ownerPw = userPw
+	}
+	e := &PdfEncrypt{keyLength: bits / 8, revision: 3}
+	// the reserved bits must be set, and revision 2 only has the first four permissions
+	e.permissions = int32(uint32(permissions) | 0xFFFFF0C0)
+	if bits == 40 {
+		e.revision = 2
+		e.permissions = int32(uint32(permissions)&0x3C | 0xFFFFFFC0)
+	}
+	id := md5.Sum([]byte(fmt.Sprintf("%v %v %v", d.now().UnixNano(), len(d.objects), ownerPw)))
+	e.fileID = id[:]
+	e.owner = e.ownerValue(padPassword(userPw), padPassword(ownerPw))
+	e.key = e.fileKey(padPassword(userPw))
+	e.user = e.userValue()
+	d.setEncryption(e)
+	return nil
+}
+
+// EncryptAES256 protects the document with AES encryption using a 256 bit key, as defined in PDF 2.0. The
+// passwords and permissions work as for Encrypt.
+func (d *PdfDocument) EncryptAES256(userPw, ownerPw string, permissions Permissions) {
+	if ownerPw == "" {
+		ownerPw = userPw
+	}
+	e := &PdfEncrypt{keyLength: 32, revision: 6, permissions: int32(uint32(permissions) | 0xFFFFF0C0)}
+	id := md5.Sum([]byte(fmt.Sprintf("%v %v", d.now().UnixNano(), len(d.objects))))
+	e.fileID = id[:]
+	e.key = randomBytes(32)
+	user, owner := aes256Password(userPw), aes256Password(ownerPw)
+
+	// U is a hash of the password with a validation salt, followed by the salts
+	salts := randomBytes(16)
+	e.user = append(aes256Hash(user, salts[:8], nil), salts...)
+	e.userKey = aes256EncryptKey(aes256Hash(user, salts[8:], nil), e.key)
+	salts = randomBytes(16)
+	e.owner = append(aes256Hash(owner, salts[:8], e.user), salts...)
+	e.ownerKey = aes256EncryptKey(aes256Hash(owner, salts[8:], e.user), e.key)
+
+	// Perms repeats the permissions encrypted with the file key so that they can't be changed
+	perms := make([]byte, 16)
+	binary.LittleEndian.PutUint32(perms, uint32(e.permissions))
+	copy(perms[4:], []byte{0xFF, 0xFF, 0xFF, 0xFF, 'T', 'a', 'd', 'b'})
+	copy(perms[12:], randomBytes(4))
+	block, _ := aes.NewCipher(e.key)
+	e.perms = make([]byte, 16)
+	block.Encrypt(e.perms, perms)
+	d.setEncryption(e)
+}
+
+// setEncryption sets the encryption dictionary, replacing any set before
+func (d *PdfDocument) setEncryption(e *PdfEncrypt) {
+	if d.encryption == nil {
+		d.addObject(e)
+	} else {
+		e.PdfObject = d.encryption.PdfObject
+		d.objects[e.id-1] = e
+	}
+	d.encryption = e
+}
+
+func randomBytes(n int) []byte {
+	b := make([]byte, n)
+	rand.Read(b)
+	return b
+}
+
+// aes256Password converts a password to the UTF-8 bytes used by revision 6, which are limited to 127 bytes
+func aes256Password(pw string) []byte {
+	b := []byte(pw)
+	if len(b) > 127 {
+		b = b[:127]
+	}
+	return b
+}
+
+// aes256Hash is the hardened hash of revision 6. Rounds of AES encryption and a choice of SHA-2 hashes make it
+// slow to try passwords.
+func aes256Hash(pw, salt, userData []byte) []byte {
+	sum := sha256.Sum256(append(append(append([]byte(nil), pw...), salt...), userData...))
+	k := sum[:]
+	for round := 1; ; round++ {
+		k1 := append(append(append([]byte(nil), pw...), k...), userData...)
+		data := bytes.Repeat(k1, 64)
+		block, _ := aes.NewCipher(k[:16])
+		cipher.NewCBCEncrypter(block, k[16:32]).CryptBlocks(data, data)
+		total := 0
+		for _, b := range data[:16] {
+			total += int(b)
+		}
+		var h hash.Hash
+		switch total % 3 {
+		case 0:
+			h = sha256.New()
+		case 1:
+			h = sha512.New384()
+		default:
+			h = sha512.New()
+		}
+		h.Write(data)
+		k = h.Sum(nil)
+		if round >= 64 && int(data[len(data)-1]) <= round-32 {
+			break
+		}
+	}
+	return k[:32]
+}
+
+// aes256EncryptKey encrypts the file key with a key derived from a password, for the OE and UE entries
+func aes256EncryptKey(key, fileKey []byte) []byte {
+	block, _ := aes.NewCipher(key)
+	out := make([]byte, len(fileKey))
+	cipher.NewCBCEncrypter(block, make([]byte, aes.BlockSize)).CryptBlocks(out, fileKey)
+	return out
+}
+
+// padPassword truncates or pads a password to 32 bytes
+func padPassword(pw string) []byte {
+	padded := append([]byte(pw), passwordPadding...)
+	return padded[:32]
+}
+
+// md5Rounds hashes data, then for revision 3 hashes the first n bytes of the result 50 more times
+func (e *PdfEncrypt) md5Rounds(data []byte) []byte {
+	sum := md5.Sum(data)
+	if e.revision >= 3 {
+		for i := 0; i < 50; i++ {
+			sum = md5.Sum(sum[:e.keyLength])
+		}
+	}
+	return sum[:e.keyLength]
+}
+
+// rc4Rounds encrypts data with key, then for revision 3 encrypts it 19 more times with the key XORed with the round
+func (e *PdfEncrypt) rc4Rounds(key, data []byte) []byte {
+	out := rc4Crypt(key, data)
+	if e.revision >= 3 {
+		roundKey := make([]byte, len(key))
+		for i := 1; i <= 19; i++ {
+			for j := range key {
+				roundKey[j] = key[j] ^ byte(i)
+			}
+			out = rc4Crypt(roundKey, out)
+		}
+	}
+	return out
+}
+
+// ownerValue computes the O entry, the user password encrypted with a key from the owner password
+func (e *PdfEncrypt) ownerValue(user, owner []byte) []byte {
+	return e.rc4Rounds(e.md5Rounds(owner), user)
+}
+
+// fileKey computes the key used to derive the key of each object
+func (e *PdfEncrypt) fileKey(user []byte) []byte {
+	data := append([]byte(nil), user...)
+	data = append(data, e.owner...)
+	data = binary.LittleEndian.AppendUint32(data, uint32(e.permissions))
+	data = append(data, e.fileID...)
+	return e.md5Rounds(data)
+}
+
+// userValue computes the U entry, which lets a viewer check the user password
+func (e *PdfEncrypt) userValue() []byte {
+	if e.revision == 2 {
+		return rc4Crypt(e.key, passwordPadding)
+	}
+	sum := md5.Sum(append(append([]byte(nil), passwordPadding...), e.fileID...))
+	// the last 16 bytes are arbitrary padding
+	return append(e.rc4Rounds(e.key, sum[:]), make([]byte, 16)...)
+}
+
+// objectKey derives the key for the strings and streams of an object
+func (e *PdfEncrypt) objectKey(id int) []byte {
+	data := append([]byte(nil), e.key...)
+	data = append(data, byte(id), byte(id>>8), byte(id>>16), 0, 0)
+	sum := md5.Sum(data)
+	n := len(e.key) + 5
+	if n > 16 {
+		n = 16
+	}
+	return sum[:n]
+}
+
+// encryptData encrypts a string or stream of an object
+func (e *PdfEncrypt) encryptData(id int, data []byte) []byte {
+	if e.revision == 6 {
+		// AES-256 uses the file key for every object, with a random initialisation vector before the padded data
+		padding := aes.BlockSize - len(data)%aes.BlockSize
+		out := append(randomBytes(aes.BlockSize), data...)
+		out = append(out, bytes.Repeat([]byte{byte(padding)}, padding)...)
+		block, _ := aes.NewCipher(e.key)
+		cipher.NewCBCEncrypter(block, out[:aes.BlockSize]).CryptBlocks(out[aes.BlockSize:], out[aes.BlockSize:])
+		return out
+	}
+	return rc4Crypt(e.objectKey(id), data)
+}
+
+func rc4Crypt(key, data []byte) []byte {
+	c, _ := rc4.NewCipher(key)
+	out := make([]byte, len(data))
+	c.XORKeyStream(out, data)
+	return out
+}
+
+func (e *PdfEncrypt) bytes() []byte {
+	v := 1
+	switch e.revision {
+	case 3:
+		v = 2
+	case 6:
+		v = 5
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%v 0 obj\r\n", e.id)
+	fmt.Fprintf(&buf, "<<\r\n")
+	fmt.Fprintf(&buf, "/Filter /Standard\r\n")
+	fmt.Fprintf(&buf, "/V %v\r\n", v)
+	fmt.Fprintf(&buf, "/R %v\r\n", e.revision)
+	fmt.Fprintf(&buf, "/Length %v\r\n", e.keyLength*8)
+	if e.revision == 6 {
+		fmt.Fprintf(&buf, "/CF << /StdCF << /AuthEvent /DocOpen /CFM /AESV3 /Length 32 >> >>\r\n")
+		fmt.Fprintf(&buf, "/StmF /StdCF\r\n")
+		fmt.Fprintf(&buf, "/StrF /StdCF\r\n")
+		fmt.Fprintf(&buf, "/OE <%X>\r\n", e.ownerKey)
+		fmt.Fprintf(&buf, "/UE <%X>\r\n", e.userKey)
+		fmt.Fprintf(&buf, "/Perms <%X>\r\n", e.perms)
+	}
+	fmt.Fprintf(&buf, "/O <%X>\r\n", e.owner)
+	fmt.Fprintf(&buf, "/U <%X>\r\n", e.user)
+	fmt.Fprintf(&buf, "/P %v\r\n", e.permissions)
+	fmt.Fprintf(&buf, ">>\r\n")
+	fmt.Fprintf(&buf, "endobj\r\n")
+	return buf.Bytes()
+}
+
+// encryptObject encrypts the strings and stream of a serialized object. Literal and hex strings in the object's
+// dictionaries and arrays are rewritten as encrypted hex strings and the stream data is encrypted in place.
+func (e *PdfEncrypt) encryptObject(id int, obj []byte) []byte {
+	var out bytes.Buffer
+	for i := 0; i < len(obj); {
+		switch {
+		case obj[i] == '(':
+			s, end := parseLiteralString(obj, i)
+			fmt.Fprintf(&out, "<%X>", e.encryptData(id, s))
+			i = end
+		case obj[i] == '<' && i+1 < len(obj) && obj[i+1] == '<':
+			out.WriteString("<<")
+			i += 2
+		case obj[i] == '<':
+			s, end := parseHexString(obj, i)
+			fmt.Fprintf(&out, "<%X>", e.encryptData(id, s))
+			i = end
+		case bytes.HasPrefix(obj[i:], []byte("stream\r\n")) || bytes.HasPrefix(obj[i:], []byte("stream\n")):
+			start := bytes.IndexByte(obj[i:], '\n') + i + 1
+			length := streamLength(out.Bytes())
+			if length < 0 || start+length > len(obj) {
+				out.Write(obj[i:])
+				return out.Bytes()
+			}
+			data := e.encryptData(id, obj[start:start+length])
+			dict := replaceStreamLength(out.Bytes(), len(data))
+			out.Reset()
+			out.Write(dict)
+			out.Write(obj[i:start])
+			out.Write(data)
+			out.Write(obj[start+length:])
+			return out.Bytes()
+		default:
+			out.WriteByte(obj[i])
+			i++
+		}
+	}
+	return out.Bytes()
+}
+
+// parseLiteralString decodes the literal string starting at obj[start] and returns it with the offset just after it
+func parseLiteralString(obj []byte, start int) ([]byte, int) {
+	var s []byte
+	depth := 0
+	for i := start; i < len(obj); i++ {
+		c := obj[i]
+		switch {
+		case c == '\\' && i+1 < len(obj):
+			i++
+			switch e := obj[i]; e {
+			case 'n':
+				s = append(s, '\n')
+			case 'r':
+				s = append(s, '\r')
+			case 't':
+				s = append(s, '\t')
+			case 'b':
+				s = append(s, '\b')
+			case 'f':
+				s = append(s, '\f')
+			case '\r':
+				// line continuation
+				if i+1 < len(obj) && obj[i+1] == '\n' {
+					i++
+				}
+			case '\n':
+			default:
+				if e >= '0' && e <= '7' {
+					v := 0
+					for n := 0; n < 3 && i < len(obj) && obj[i] >= '0' && obj[i] <= '7'; n++ {
+						v = v*8 + int(obj[i]-'0')
+						i++
+					}
+					i--
+					s = append(s, byte(v))
+				} else {
+					s = append(s, e)
+				}
+			}
+		case c == '(':
+			if depth > 0 {
+				s = append(s, c)
+			}
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 {
+				return s, i + 1
+			}
+			s = append(s, c)
+		default:
+			s = append(s, c)
+		}
+	}
+	return s, len(obj)
+}
+
+// parseHexString decodes the hex string starting at obj[start] and returns it with the offset just after it
+func parseHexString(obj []byte, start int) ([]byte, int) {
+	var s []byte
+	digits := 0
+	var b byte
+	for i := start + 1; i < len(obj); i++ {
+		c := obj[i]
+		var v byte
+		switch {
+		case c == '>':
+			if digits%2 == 1 {
+				s = append(s, b<<4)
+			}
+			return s, i + 1
+		case c >= '0' && c <= '9':
+			v = c - '0'
+		case c >= 'A' && c <= 'F':
+			v = c - 'A' + 10
+		case c >= 'a' && c <= 'f':
+			v = c - 'a' + 10
+		default:
+			continue
+		}
+		if digits%2 == 0 {
+			b = v
+		} else {
+			s = append(s, b<<4|v)
+		}
+		digits++
+	}
+	return s, len(obj)
+}
+
+// streamLength finds the /Length entry in the dictionary of a stream object
+func streamLength(dict []byte) int {
+	at := bytes.Index(dict, []byte("/Length "))
+	if at < 0 {
+		return -1
+	}
+	rest := dict[at+len("/Length "):]
+	end := 0
+	for end < len(rest) && rest[end] >= '0' && rest[end] <= '9' {
+		end++
+	}
+	n, err := strconv.Atoi(string(rest[:end]))
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+// replaceStreamLength sets the /Length entry in the dictionary of a stream object
+func replaceStreamLength(dict []byte, length int) []byte {
+	at := bytes.Index(dict, []byte("/Length ")) + len("/Length ")
+	end := at
+	for end < len(dict) && dict[end] >= '0' && dict[end] <= '9' {
+		end++
+	}
+	out := append([]byte(nil), dict[:at]...)
+	out = strconv.AppendInt(out, int64(length), 10)
+	return append(out, dict[end:]...)
+}
@@ -0,0 +1,78 @@
+package gopdf
+
+import "errors"
+
+var (
+	// ErrInvalidFont is returned when a font constant is not one of the 14 core fonts
+	ErrInvalidFont = errors.New("gopdf: invalid font")
+	// ErrFontNotFound is returned when a font name has not been added to the document
+	ErrFontNotFound = errors.New("gopdf: font not found")
+	// ErrDuplicateName is returned when a font or image is added with the name of one already added
+	ErrDuplicateName = errors.New("gopdf: name already used")
+	// ErrImageNotFound is returned when an image name has not been added to the document
+	ErrImageNotFound = errors.New("gopdf: image not found")
+	// ErrTemplateNotFound is returned when a template name has not been added to the document
+	ErrTemplateNotFound = errors.New("gopdf: template not found")
+	// ErrSpotColorNotFound is returned when a spot colour name has not been added to the document
+	ErrSpotColorNotFound = errors.New("gopdf: spot colour not found")
+	// ErrInvalidICCProfile is returned when an ICC profile is too short or not for an RGB, CMYK or gray colour space
+	ErrInvalidICCProfile = errors.New("gopdf: invalid ICC profile")
+	// ErrDestinationNotFound is returned when writing a document with a link or bookmark to a named destination that
+	// has not been added
+	ErrDestinationNotFound = errors.New("gopdf: named destination not found")
+	// ErrInvalidPageLabel is returned when a page label range starts before the first page or has an unknown style
+	ErrInvalidPageLabel = errors.New("gopdf: invalid page label range")
+	// ErrInvalidViewerPreferences is returned for an unknown page mode or one that can't be used on leaving full
+	// screen mode
+	ErrInvalidViewerPreferences = errors.New("gopdf: invalid viewer preferences")
+	// ErrInvalidBarcode is returned when data contains a character that the barcode symbology can't encode, or is
+	// too long for it
+	ErrInvalidBarcode = errors.New("gopdf: invalid barcode data")
+	// ErrInvalidChart is returned when chart labels don't match the data or a pie chart has a negative value
+	ErrInvalidChart = errors.New("gopdf: invalid chart data")
+	// ErrInvalidImageMask is returned when a stencil is drawn from an image that isn't one, or an image can't be
+	// masked with the colour given
+	ErrInvalidImageMask = errors.New("gopdf: invalid image mask")
+	// ErrUnsupportedImage is returned when an image file uses a feature of its format that can't be read, such as a
+	// TIFF compression scheme
+	ErrUnsupportedImage = errors.New("gopdf: unsupported image")
+	// ErrUnmappableRune is returned in strict encoding mode when text contains a character that the font cannot
+	// represent
+	ErrUnmappableRune = errors.New("gopdf: character cannot be encoded")
+	// ErrTooFewPoints is returned when a polygon or polyline has fewer than two points
+	ErrTooFewPoints = errors.New("gopdf: too few points")
+	// ErrNoCurrentPoint is returned when a path is extended before MoveTo has started it
+	ErrNoCurrentPoint = errors.New("gopdf: path has no current point")
+	// ErrNoSavedState is returned by RestoreState when there is no matching SaveState
+	ErrNoSavedState = errors.New("gopdf: no saved graphics state to restore")
+	// ErrMixedOrigins is returned when the coordinate origin is changed after positions have been given from the
+	// other one
+	ErrMixedOrigins = errors.New("gopdf: coordinate origin can't be changed")
+	// ErrInvalidRotation is returned when a page rotation is not a multiple of 90 degrees
+	ErrInvalidRotation = errors.New("gopdf: rotation must be a multiple of 90 degrees")
+	// ErrNotPDFA is returned when writing a document in PDF/A mode that uses features PDF/A forbids
+	ErrNotPDFA = errors.New("gopdf: document does not conform to PDF/A-1b")
+	// ErrStreaming is returned when a document being written with StartWriting is written again
+	ErrStreaming = errors.New("gopdf: document is being written with StartWriting")
+	// ErrNotStreaming is returned by FinishPage and Close when StartWriting has not been called
+	ErrNotStreaming = errors.New("gopdf: document is not being written with StartWriting")
+	// ErrInvalidTOC is returned when the table of contents goes after a page that isn't in the document
+	ErrInvalidTOC = errors.New("gopdf: invalid table of contents position")
+	// ErrInvalidPDF is returned when a PDF file can't be read, because it is damaged or uses a feature that isn't
+	// supported
+	ErrInvalidPDF = errors.New("gopdf: can't read PDF file")
+	// ErrEncryptedPDF is returned when reading a PDF file that is encrypted
+	ErrEncryptedPDF = errors.New("gopdf: can't read encrypted PDF file")
+	// ErrPageOutOfRange is returned when a page index is not the index of a page
+	ErrPageOutOfRange = errors.New("gopdf: page index out of range")
+	// ErrInvalidNUp is returned by WriteNUp when the number of columns or rows is less than 1
+	ErrInvalidNUp = errors.New("gopdf: invalid n-up layout")
+	// ErrInvalidDocument is returned by Validate when the document as written breaks the rules of the PDF file
+	// structure
+	ErrInvalidDocument = errors.New("gopdf: invalid document structure")
+	// ErrInvalidRawObject is returned when a raw object or catalog entry holds a value that can't be written, refers
+	// to an object of another document or leaves out an entry it needs
+	ErrInvalidRawObject = errors.New("gopdf: invalid raw object")
+	// ErrPageFinished is recorded when a page is changed after FinishPage has written it
+	ErrPageFinished = errors.New("gopdf: page has already been written")
+)
@@ -0,0 +1,80 @@
+package gopdf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+// exifOrientation returns the Orientation tag from the contents of a JPEG APP1 segment, or 0 if it isn't an EXIF
+// segment with a valid orientation. Orientations 2 to 8 are the mirroring and rotation needed to show the image
+// upright.
+func exifOrientation(segment []byte) int {
+	tiff, ok := bytes.CutPrefix(segment, []byte("Exif\x00\x00"))
+	if !ok || len(tiff) < 8 {
+		return 0
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0
+	}
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 0
+	}
+	entries := int(order.Uint16(tiff[ifd:]))
+	for i := 0; i < entries; i++ {
+		at := ifd + 2 + 12*i
+		if at+12 > len(tiff) {
+			return 0
+		}
+		// a SHORT tag, whose value is at the start of the value field
+		if order.Uint16(tiff[at:]) == 0x0112 && order.Uint16(tiff[at+2:]) == 3 {
+			if o := int(order.Uint16(tiff[at+8:])); o >= 1 && o <= 8 {
+				return o
+			}
+			return 0
+		}
+	}
+	return 0
+}
+
+// displaySize returns the size of the image in pixels once it is turned upright, which swaps the width and height
+// of images that are rotated a quarter turn
+func (pi *PdfImage) displaySize() (float64, float64) {
+	if pi.orientation >= 5 {
+		return float64(pi.height), float64(pi.width)
+	}
+	return float64(pi.width), float64(pi.height)
+}
+
+// placement returns the cm operator that draws the image upright in the w by h box with its bottom left corner at
+// x, y, mirroring and rotating it as its EXIF orientation asks
+func (pi *PdfImage) placement(x, y, w, h float64) string {
+	var m [6]float64
+	switch pi.orientation {
+	case 2:
+		m = [6]float64{-w, 0, 0, h, x + w, y}
+	case 3:
+		m = [6]float64{-w, 0, 0, -h, x + w, y + h}
+	case 4:
+		m = [6]float64{w, 0, 0, -h, x, y + h}
+	case 5:
+		m = [6]float64{0, -h, -w, 0, x + w, y + h}
+	case 6:
+		m = [6]float64{0, -h, w, 0, x, y + h}
+	case 7:
+		m = [6]float64{0, h, w, 0, x, y}
+	case 8:
+		m = [6]float64{0, h, -w, 0, x + w, y}
+	default:
+		m = [6]float64{w, 0, 0, h, x, y}
+	}
+	return fmt.Sprintf("%v %v %v %v %v %v cm", formatNumber(m[0]), formatNumber(m[1]), formatNumber(m[2]),
+		formatNumber(m[3]), formatNumber(m[4]), formatNumber(m[5]))
+}
@@ -0,0 +1,53 @@
+package gopdf
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestExifOrientation(t *testing.T) {
+	// each file is 8 by 4 pixels, with its orientation in an EXIF segment, big endian for 3 and 6 and little endian
+	// for 8
+	for _, c := range []struct {
+		orientation   int
+		natural, wide string
+	}{
+		{3, "-8 0 0 -4 108 104 cm", "-40 0 0 -20 140 120 cm"},
+		{6, "0 -8 4 0 100 108 cm", "0 -80 40 0 100 180 cm"},
+		{8, "0 8 -4 0 104 100 cm", "0 80 -40 0 140 100 cm"},
+	} {
+		d := NewPdfDocument()
+		name := fmt.Sprintf("o%v", c.orientation)
+		i, err := d.AddImage(name, fmt.Sprintf("testdata/orientation%v.jpg", c.orientation))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i.orientation != c.orientation {
+			t.Errorf("orientation %v read as %v", c.orientation, i.orientation)
+		}
+		p := d.CurrentPage()
+		// drawn a point to a pixel, then 40 points wide with the height from the upright aspect ratio
+		if err := p.DrawImage(name, 100, 100); err != nil {
+			t.Fatal(err)
+		}
+		if err := p.DrawImageScaled(name, 100, 100, 40, 0); err != nil {
+			t.Fatal(err)
+		}
+		content := p.content.stream.String()
+		for _, want := range []string{c.natural, c.wide} {
+			if !strings.Contains(content, "q\r\n"+want+"\r\n/"+name+" Do\r\n") {
+				t.Errorf("orientation %v isn't drawn with %v in %q", c.orientation, want, content)
+			}
+		}
+		data, err := d.Bytes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		// the image is written as it is stored, and only drawn upright
+		image := imageXObject(t, data, name)
+		if w, h := image.dict.get("Width"), image.dict.get("Height"); w != 8 || h != 4 {
+			t.Errorf("orientation %v written %v by %v, want 8 by 4", c.orientation, w, h)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+)
+
+// BlendMode selects how colours drawn are combined with those already on the page
+type BlendMode int
+
+// Blend modes. Normal paints over what is underneath, Multiply darkens it like a highlighter pen, and Screen
+// lightens it.
+const (
+	BlendNormal BlendMode = iota
+	BlendMultiply
+	BlendScreen
+	BlendOverlay
+	BlendDarken
+	BlendLighten
+	BlendColorDodge
+	BlendColorBurn
+	BlendHardLight
+	BlendSoftLight
+	BlendDifference
+	BlendExclusion
+	BlendHue
+	BlendSaturation
+	BlendColor
+	BlendLuminosity
+)
+
+var blendModeNames = [...]string{"Normal", "Multiply", "Screen", "Overlay", "Darken", "Lighten", "ColorDodge",
+	"ColorBurn", "HardLight", "SoftLight", "Difference", "Exclusion", "Hue", "Saturation", "Color", "Luminosity"}
+
+// extGStateParams are the graphics state parameters that are set with an ExtGState
+type extGStateParams struct {
+	fillAlpha, strokeAlpha float64
+	blendMode              BlendMode
+}
+
+// PdfExtGState is a graphics state parameter dictionary, which sets the transparency and blend mode of what is
+// drawn after it
+type PdfExtGState struct {
+	PdfObject
+	name string
+	extGStateParams
+}
+
+// SetAlpha sets the opacity of what is drawn afterwards, from 0 for invisible to 1 for opaque. Fill alpha applies to
+// text, filled shapes and images, and stroke alpha to lines and the outlines of shapes. SetAlpha(1, 1) goes back to
+// drawing opaquely.
+func (p *PdfPage) SetAlpha(fill, stroke float64) {
+	p.extGState.fillAlpha = math.Max(0, math.Min(fill, 1))
+	p.extGState.strokeAlpha = math.Max(0, math.Min(stroke, 1))
+	p.setExtGState()
+}
+
+// SetBlendMode sets how what is drawn afterwards is combined with what is already on the page. It is kept along with
+// the alpha set by SetAlpha.
+func (p *PdfPage) SetBlendMode(mode BlendMode) {
+	if mode < 0 || int(mode) >= len(blendModeNames) {
+		mode = BlendNormal
+	}
+	p.extGState.blendMode = mode
+	p.setExtGState()
+}
+
+// setExtGState selects the graphics state for the page's current alpha and blend mode
+func (p *PdfPage) setExtGState() {
+	if p.build != nil {
+		p.setPendingExtGState()
+		return
+	}
+	gs := p.document.extGState(p.extGState)
+	p.use(gs)
+	p.content.addStatef("%v gs\r\n", formatName(gs.name))
+}
+
+// extGState returns the graphics state with the given parameters, adding it to the document if it hasn't been used
+// yet
+func (d *PdfDocument) extGState(params extGStateParams) *PdfExtGState {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if gs := d.findExtGState(params); gs != nil {
+		return gs
+	}
+	gs := &PdfExtGState{name: fmt.Sprintf("GS%v", len(d.resources.extGStates)+1), extGStateParams: params}
+	d.addObject(gs)
+	d.resources.extGStates = append(d.resources.extGStates, gs)
+	return gs
+}
+
+// findExtGState returns the document's graphics state with the given parameters, or nil if it hasn't been used yet
+func (d *PdfDocument) findExtGState(params extGStateParams) *PdfExtGState {
+	for _, gs := range d.resources.extGStates {
+		if gs.extGStateParams == params {
+			return gs
+		}
+	}
+	return nil
+}
+
+func (gs PdfExtGState) bytes() []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%v 0 obj\r\n", gs.id)
+	fmt.Fprintf(&buf, "<<\r\n")
+	fmt.Fprintf(&buf, "/Type /ExtGState\r\n")
+	fmt.Fprintf(&buf, "/ca %v\r\n", formatNumber(gs.fillAlpha))
+	fmt.Fprintf(&buf, "/CA %v\r\n", formatNumber(gs.strokeAlpha))
+	fmt.Fprintf(&buf, "/BM /%v\r\n", blendModeNames[gs.blendMode])
+	fmt.Fprintf(&buf, ">>\r\n")
+	fmt.Fprintf(&buf, "endobj\r\n")
+	return buf.Bytes()
+}
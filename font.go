@@ -0,0 +1,96 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// 14 core fonts
+const (
+	Courier = iota + 1
+	CourierBold
+	CourierBoldOblique
+	CourierOblique
+	Helvetica
+	HelveticaBold
+	HelveticaBoldOblique
+	HelveticaOblique
+	TimesRoman
+	TimesBold
+	TimesItalic
+	TimesBoldItalic
+	Symbol
+	ZapfDingbats
+)
+
+// PdfFont stores the details of one of the 14 base fonts
+type PdfFont struct {
+	PdfObject
+	name     string
+	baseFont string
+	subtype  string
+	encoding string
+	unicode  *unicodeFont // set for Type0 fonts added with AddUnicodeFont
+	// toUnicode is set for core fonts added to a document, and shared by those with the same encoding
+	toUnicode *PdfToUnicodeCMap
+}
+
+// NewFont creates one of the 14 base fonts
+func NewFont(name string, font int) (PdfFont, error) {
+	var result PdfFont
+	switch font {
+	case Courier:
+		result = PdfFont{name: name, baseFont: "Courier", subtype: "Type1", encoding: "WinAnsiEncoding"}
+	case CourierBold:
+		result = PdfFont{name: name, baseFont: "Courier-Bold", subtype: "Type1", encoding: "WinAnsiEncoding"}
+	case CourierBoldOblique:
+		result = PdfFont{name: name, baseFont: "Courier-BoldOblique", subtype: "Type1", encoding: "WinAnsiEncoding"}
+	case CourierOblique:
+		result = PdfFont{name: name, baseFont: "Courier-Oblique", subtype: "Type1", encoding: "WinAnsiEncoding"}
+	case Helvetica:
+		result = PdfFont{name: name, baseFont: "Helvetica", subtype: "Type1", encoding: "WinAnsiEncoding"}
+	case HelveticaBold:
+		result = PdfFont{name: name, baseFont: "Helvetica-Bold", subtype: "Type1", encoding: "WinAnsiEncoding"}
+	case HelveticaBoldOblique:
+		result = PdfFont{name: name, baseFont: "Helvetica-BoldOblique", subtype: "Type1", encoding: "WinAnsiEncoding"}
+	case HelveticaOblique:
+		result = PdfFont{name: name, baseFont: "Helvetica-Oblique", subtype: "Type1", encoding: "WinAnsiEncoding"}
+	case TimesRoman:
+		result = PdfFont{name: name, baseFont: "Times-Roman", subtype: "Type1", encoding: "WinAnsiEncoding"}
+	case TimesBold:
+		result = PdfFont{name: name, baseFont: "Times-Bold", subtype: "Type1", encoding: "WinAnsiEncoding"}
+	case TimesBoldItalic:
+		result = PdfFont{name: name, baseFont: "Times-BoldItalic", subtype: "Type1", encoding: "WinAnsiEncoding"}
+	case TimesItalic:
+		result = PdfFont{name: name, baseFont: "Times-Italic", subtype: "Type1", encoding: "WinAnsiEncoding"}
+	case Symbol:
+		result = PdfFont{name: name, baseFont: "Symbol", subtype: "Type1", encoding: "StandardEncoding"}
+	case ZapfDingbats:
+		result = PdfFont{name: name, baseFont: "ZapfDingbats", subtype: "Type1", encoding: "StandardEncoding"}
+	default:
+		return result, fmt.Errorf("%w %v", ErrInvalidFont, font)
+	}
+	return result, nil
+}
+
+func (f PdfFont) bytes() []byte {
+	if f.unicode != nil {
+		return f.type0Bytes()
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%v 0 obj\r\n", f.id)
+	fmt.Fprintf(&buf, "<<\r\n")
+	fmt.Fprintf(&buf, "/Type /Font \r\n")
+	fmt.Fprintf(&buf, "/Subtype /%v \r\n", f.subtype)
+	fmt.Fprintf(&buf, "/Name %v \r\n", formatName(f.name))
+	fmt.Fprintf(&buf, "/BaseFont /%v \r\n", f.baseFont)
+	if f.encoding != "StandardEncoding" {
+		fmt.Fprintf(&buf, "/Encoding /%v\r\n", f.encoding)
+	}
+	if f.toUnicode != nil {
+		fmt.Fprintf(&buf, "/ToUnicode %v\r\n", f.toUnicode.objectRef())
+	}
+	fmt.Fprintf(&buf, ">>\r\n")
+	fmt.Fprintf(&buf, "endobj\r\n")
+	return buf.Bytes()
+}
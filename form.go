@@ -0,0 +1,168 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// PdfAcroForm is the interactive form of the document, which lists its fields
+type PdfAcroForm struct {
+	PdfObject
+	fields []*PdfFormField
+}
+
+// PdfFormField is a form field with a single widget annotation that shows it on a page
+type PdfFormField struct {
+	PdfObject
+	page       *PdfPage
+	fieldType  string
+	name       string
+	rect       [4]float64
+	value      string
+	checked    bool
+	font       *PdfFont
+	fontSize   float64
+	appearance [2]*appearanceStream // on and off, for checkboxes
+}
+
+// acroForm returns the form of the document, creating it on first use
+func (d *PdfDocument) acroForm() *PdfAcroForm {
+	if d.catalog.acroForm == nil {
+		d.catalog.acroForm = &PdfAcroForm{}
+		d.addObject(d.catalog.acroForm)
+	}
+	return d.catalog.acroForm
+}
+
+// AddTextField adds a text field with its corner at x, y that the reader can type into. The text is
+// shown in the current font and size, or Helvetica when the current font is a Unicode font.
+func (p *PdfPage) AddTextField(name string, x, y, w, h float64, defaultValue string) {
+	x, y, w, h = p.rect(x, y, w, h)
+	f := &PdfFormField{page: p, fieldType: "Tx", name: name, rect: [4]float64{x, y, x + w, y + h}, value: defaultValue}
+	f.font, f.fontSize = p.fieldFont(), p.fontSize
+	p.addField(f)
+}
+
+// AddCheckbox adds a square checkbox with sides of length size and its corner at x, y
+func (p *PdfPage) AddCheckbox(name string, x, y, size float64, checked bool) {
+	if p.measure != nil {
+		return
+	}
+	x, y, size, _ = p.rect(x, y, size, size)
+	f := &PdfFormField{page: p, fieldType: "Btn", name: name, rect: [4]float64{x, y, x + size, y + size}, checked: checked}
+	for i := range f.appearance {
+		f.appearance[i] = p.addAppearance(size, size, "", checkboxOps(size, i == 0))
+	}
+	p.addField(f)
+}
+
+func (p *PdfPage) addField(f *PdfFormField) {
+	if p.measure != nil {
+		return
+	}
+	p.addObject(f)
+	p.annots = append(p.annots, f)
+	if p.build != nil {
+		p.build.fields = append(p.build.fields, f)
+		return
+	}
+	form := p.document.acroForm()
+	form.fields = append(form.fields, f)
+}
+
+// fieldFont returns the core font to use in the default appearance of a text field or annotation, the current font
+// if it is a core font, or else any core font of the document, or else Helvetica
+func (p *PdfPage) fieldFont() *PdfFont {
+	if p.font != nil && p.font.unicode == nil {
+		return p.font
+	}
+	for _, font := range p.document.resources.fonts {
+		if font.unicode == nil {
+			return font
+		}
+	}
+	return p.document.helvetica()
+}
+
+// helvetica returns the Helvetica font added to the document for form fields and annotations, and for text shown
+// when no font has been set, adding it the first time it is needed
+func (d *PdfDocument) helvetica() *PdfFont {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.formFont == nil {
+		font, _ := NewFont("Helv", Helvetica)
+		d.formFont = &font
+		d.addCoreFont(d.formFont)
+	}
+	return d.formFont
+}
+
+func (a PdfAcroForm) bytes() []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%v 0 obj\r\n", a.id)
+	fmt.Fprintf(&buf, "<<\r\n")
+	fmt.Fprintf(&buf, "/Fields [ ")
+	for _, f := range a.fields {
+		fmt.Fprintf(&buf, "%v ", f.objectRef())
+	}
+	fmt.Fprintf(&buf, "]\r\n")
+	// the fonts named in default appearances
+	fmt.Fprintf(&buf, "/DR << /Font << ")
+	for _, font := range a.document.resources.fonts {
+		if font.unicode == nil {
+			fmt.Fprintf(&buf, "%v %v ", formatName(font.name), font.objectRef())
+		}
+	}
+	if font := a.document.formFont; font != nil {
+		fmt.Fprintf(&buf, "%v %v ", formatName(font.name), font.objectRef())
+	}
+	fmt.Fprintf(&buf, ">> >>\r\n")
+	// viewers draw text fields themselves, which PDF/A forbids
+	if !a.document.pdfA {
+		fmt.Fprintf(&buf, "/NeedAppearances true\r\n")
+	}
+	fmt.Fprintf(&buf, ">>\r\n")
+	fmt.Fprintf(&buf, "endobj\r\n")
+	return buf.Bytes()
+}
+
+func (f PdfFormField) bytes() []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%v 0 obj\r\n", f.id)
+	fmt.Fprintf(&buf, "<<\r\n")
+	fmt.Fprintf(&buf, "/Type /Annot\r\n")
+	fmt.Fprintf(&buf, "/Subtype /Widget\r\n")
+	fmt.Fprintf(&buf, "/FT /%v\r\n", f.fieldType)
+	fmt.Fprintf(&buf, "/T %v\r\n", formatTextString(f.name))
+	fmt.Fprintf(&buf, "/Rect %v\r\n", formatRect(f.rect))
+	fmt.Fprintf(&buf, "/P %v\r\n", f.page.objectRef())
+	fmt.Fprintf(&buf, "/F 4\r\n") // print
+	if f.fieldType == "Tx" {
+		fmt.Fprintf(&buf, "/DA (%v %v Tf 0 g)\r\n", formatName(f.font.name), formatNumber(f.fontSize))
+		fmt.Fprintf(&buf, "/V %v\r\n", formatTextString(f.value))
+		fmt.Fprintf(&buf, "/DV %v\r\n", formatTextString(f.value))
+	} else {
+		state := "/Off"
+		if f.checked {
+			state = "/Yes"
+		}
+		fmt.Fprintf(&buf, "/V %v\r\n", state)
+		fmt.Fprintf(&buf, "/AS %v\r\n", state)
+		fmt.Fprintf(&buf, "/AP << /N << /Yes %v /Off %v >> >>\r\n", f.appearance[0].objectRef(), f.appearance[1].objectRef())
+	}
+	fmt.Fprintf(&buf, "/MK << /BC [ 0 ] >>\r\n") // black border
+	fmt.Fprintf(&buf, ">>\r\n")
+	fmt.Fprintf(&buf, "endobj\r\n")
+	return buf.Bytes()
+}
+
+// checkboxOps draws a checkbox, a square border with a tick when it is on
+func checkboxOps(size float64, on bool) string {
+	ops := fmt.Sprintf("0.5 w\r\n0.25 0.25 %v %v re\r\nS\r\n", formatNumber(size-0.5), formatNumber(size-0.5))
+	if on {
+		ops += fmt.Sprintf("%v w\r\n%v %v m\r\n%v %v l\r\n%v %v l\r\nS\r\n", formatNumber(size/10),
+			formatNumber(size*0.2), formatNumber(size*0.5), formatNumber(size*0.4), formatNumber(size*0.25),
+			formatNumber(size*0.8), formatNumber(size*0.75))
+	}
+	return ops
+}
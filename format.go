@@ -0,0 +1,139 @@
+package gopdf
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// formatNumber formats f for use as an operand in a content stream, using at most three decimal places and
+// dropping trailing zeros so that whole numbers are written as integers
+func formatNumber(f float64) string {
+	s := strconv.FormatFloat(f, 'f', 3, 64)
+	s = strings.TrimRight(s, "0")
+	s = strings.TrimSuffix(s, ".")
+	if s == "-0" {
+		return "0"
+	}
+	return s
+}
+
+// formatNumbers formats values with formatNumber, separated by spaces
+func formatNumbers(values ...float64) string {
+	s := make([]string, len(values))
+	for i, v := range values {
+		s[i] = formatNumber(v)
+	}
+	return strings.Join(s, " ")
+}
+
+// formatRect formats a rectangle given by its lower left and upper right corners as a PDF array
+func formatRect(r [4]float64) string {
+	return fmt.Sprintf("[ %v %v %v %v ]", formatNumber(r[0]), formatNumber(r[1]), formatNumber(r[2]), formatNumber(r[3]))
+}
+
+// formatTextString formats s as a PDF text string, for the strings of dictionaries that viewers show, such as
+// bookmark titles, document information and form field values. Text that PDFDocEncoding can represent is written
+// as a literal string in that encoding and anything else, such as Cyrillic, Greek or CJK text, as a UTF-16BE hex
+// string with a byte order mark, which viewers show correctly whatever the characters.
+func formatTextString(s string) string {
+	encoded := encodeTextString(s)
+	if strings.HasPrefix(encoded, "\xFE\xFF") {
+		return fmt.Sprintf("<%X>", encoded)
+	}
+	return formatString(encoded)
+}
+
+// encodeTextString returns the bytes of s as a PDF text string, in PDFDocEncoding when it has every character of s
+// or else in UTF-16BE with a byte order mark
+func encodeTextString(s string) string {
+	encoded := make([]byte, 0, len(s))
+	for _, r := range s {
+		b, ok := toPDFDoc(r)
+		if !ok {
+			var sb strings.Builder
+			sb.WriteString("\xFE\xFF")
+			for _, u := range utf16.Encode([]rune(s)) {
+				sb.WriteByte(byte(u >> 8))
+				sb.WriteByte(byte(u))
+			}
+			return sb.String()
+		}
+		encoded = append(encoded, b)
+	}
+	return string(encoded)
+}
+
+// pdfDocSpecial maps the characters that PDFDocEncoding has in place of control characters and in the 0x80 to 0xA0
+// block to their byte values. The rest of the encoding matches ASCII and Latin-1, except that 0x7F, 0x9F and 0xAD
+// are undefined.
+var pdfDocSpecial = map[rune]byte{
+	'˘': 0x18, 'ˇ': 0x19, 'ˆ': 0x1A, '˙': 0x1B, '˝': 0x1C, '˛': 0x1D, '˚': 0x1E, '˜': 0x1F,
+	'•': 0x80, '†': 0x81, '‡': 0x82, '…': 0x83, '—': 0x84, '–': 0x85, 'ƒ': 0x86, '⁄': 0x87,
+	'‹': 0x88, '›': 0x89, '−': 0x8A, '‰': 0x8B, '„': 0x8C, '“': 0x8D, '”': 0x8E, '‘': 0x8F,
+	'’': 0x90, '‚': 0x91, '™': 0x92, 'ﬁ': 0x93, 'ﬂ': 0x94, 'Ł': 0x95, 'Œ': 0x96, 'Š': 0x97,
+	'Ÿ': 0x98, 'Ž': 0x99, 'ı': 0x9A, 'ł': 0x9B, 'œ': 0x9C, 'š': 0x9D, 'ž': 0x9E, '€': 0xA0,
+}
+
+// toPDFDoc returns the PDFDocEncoding byte for r
+func toPDFDoc(r rune) (byte, bool) {
+	if r == '\t' || r == '\n' || r == '\r' || (r >= ' ' && r < 0x7F) || (r > 0xA0 && r <= 0xFF && r != 0xAD) {
+		return byte(r), true
+	}
+	b, ok := pdfDocSpecial[r]
+	return b, ok
+}
+
+// formatName formats s as a PDF name object. Bytes that can't appear in a name as they are, such as spaces,
+// delimiters and #, are written as # followed by two hex digits.
+func formatName(s string) string {
+	var sb strings.Builder
+	sb.WriteByte('/')
+	for i := 0; i < len(s); i++ {
+		b := s[i]
+		if b < '!' || b > '~' || strings.IndexByte("#%()/<>[]{}", b) >= 0 {
+			fmt.Fprintf(&sb, "#%02X", b)
+		} else {
+			sb.WriteByte(b)
+		}
+	}
+	return sb.String()
+}
+
+// literalEscapes are the short escapes for bytes that can't appear as they are in a literal string
+var literalEscapes = map[byte]string{
+	'\\': `\\`, '(': `\(`, ')': `\)`, '\r': `\r`, '\n': `\n`, '\t': `\t`, '\b': `\b`, '\f': `\f`,
+}
+
+// formatString formats the bytes of s as a PDF string using only printable ASCII. It is written as a literal string
+// with escapes for the bytes outside 0x20 to 0x7E, or as a hex string when that is shorter.
+func formatString(s string) string {
+	length := 2
+	for i := 0; i < len(s); i++ {
+		if e, ok := literalEscapes[s[i]]; ok {
+			length += len(e)
+		} else if s[i] < ' ' || s[i] > '~' {
+			length += 4
+		} else {
+			length++
+		}
+	}
+	if length > 2*len(s)+2 {
+		return fmt.Sprintf("<%X>", s)
+	}
+	var sb strings.Builder
+	sb.Grow(length)
+	sb.WriteByte('(')
+	for i := 0; i < len(s); i++ {
+		if e, ok := literalEscapes[s[i]]; ok {
+			sb.WriteString(e)
+		} else if s[i] < ' ' || s[i] > '~' {
+			fmt.Fprintf(&sb, "\\%03o", s[i])
+		} else {
+			sb.WriteByte(s[i])
+		}
+	}
+	sb.WriteByte(')')
+	return sb.String()
+}
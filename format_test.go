@@ -0,0 +1,140 @@
+package gopdf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestFormatString(t *testing.T) {
+	for _, c := range []struct{ in, want string }{
+		{"plain", "(plain)"},
+		{"", "()"},
+		{"(nested (parens))", `(\(nested \(parens\)\))`},
+		{`back\slash`, `(back\\slash)`},
+		{"line\r\nbreak\ttab", `(line\r\nbreak\ttab)`},
+		{"caf\xE9", `(caf\351)`},
+		{"\x00\x01\x02\x03", "<00010203>"},
+	} {
+		if got := formatString(c.in); got != c.want {
+			t.Errorf("formatString(%q) = %v, want %v", c.in, got, c.want)
+		}
+	}
+}
+
+func TestFormatName(t *testing.T) {
+	for _, c := range []struct{ in, want string }{
+		{"F1", "/F1"},
+		{"My Font", "/My#20Font"},
+		{"a#b(c)/d", "/a#23b#28c#29#2Fd"},
+	} {
+		if got := formatName(c.in); got != c.want {
+			t.Errorf("formatName(%q) = %v, want %v", c.in, got, c.want)
+		}
+	}
+}
+
+func TestOutputTextEscaping(t *testing.T) {
+	d := NewPdfDocument()
+	if _, err := d.AddFont("Helv", Helvetica); err != nil {
+		t.Fatal(err)
+	}
+	p := d.CurrentPage()
+	if err := p.SetFont("Helv"); err != nil {
+		t.Fatal(err)
+	}
+	p.SetXY(100, 700)
+	p.Print(`a(b)\c é`)
+	content := p.content.stream.String()
+	if want := `(a\(b\)\\c \351) Tj`; !strings.Contains(content, want) {
+		t.Errorf("content %q doesn't contain %v", content, want)
+	}
+}
+
+func TestFormatTextString(t *testing.T) {
+	for _, c := range []struct{ in, want string }{
+		{"Chapter 1", "(Chapter 1)"},
+		{"Café (draft)", `(Caf\351 \(draft\))`},
+		{"“Quoted” — €5", `(\215Quoted\216 \204 \2405)`},
+		{"Глава 1", "<FEFF0413043B04300432043000200031>"},
+		{"1: Введение", "<FEFF0031003A002004120432043504340435043D04380435>"},
+		{"Ελληνικά", "<FEFF039503BB03BB03B703BD03B903BA03AC>"},
+		{"日本語のタイトル", "<FEFF65E5672C8A9E306E30BF30A430C830EB>"},
+		{"Café 東京", "<FEFF00430061006600E9002067714EAC>"},
+		{"Emoji 😀", "<FEFF0045006D006F006A00690020D83DDE00>"},
+	} {
+		if got := formatTextString(c.in); got != c.want {
+			t.Errorf("formatTextString(%q) = %v, want %v", c.in, got, c.want)
+		}
+	}
+}
+
+func TestTextStringsInDictionaries(t *testing.T) {
+	d := NewPdfDocument()
+	d.SetCompression(false)
+	title := "Глава 1 — Ελληνικά και 日本語"
+	want := formatTextString(title)
+	if !strings.HasPrefix(want, "<FEFF") {
+		t.Fatalf("%q isn't written as UTF-16BE: %v", title, want)
+	}
+	d.SetTitle(title)
+	p := d.CurrentPage()
+	d.AddBookmark(title, p, 0, nil)
+	p.AddNote(100, 700, title, title)
+	p.AddTextField("name", 100, 600, 200, 20, title)
+	data, err := d.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"/Title", "/T", "/Contents", "/V"} {
+		if !bytes.Contains(data, []byte(key+" "+want+"\r\n")) {
+			t.Errorf("%v isn't written as %v", key, want)
+		}
+	}
+}
+
+// checkFormatString checks that formatString writes s in printable ASCII that parses back to s
+func checkFormatString(t *testing.T, s string) {
+	t.Helper()
+	out := formatString(s)
+	for i := 0; i < len(out); i++ {
+		if out[i] < ' ' || out[i] > '~' {
+			t.Fatalf("formatString(%q) = %q has byte %#x", s, out, out[i])
+		}
+	}
+	var parsed []byte
+	var end int
+	switch out[0] {
+	case '(':
+		parsed, end = parseLiteralString([]byte(out), 0)
+	case '<':
+		parsed, end = parseHexString([]byte(out), 0)
+	default:
+		t.Fatalf("formatString(%q) = %q isn't a string", s, out)
+	}
+	if string(parsed) != s || end != len(out) {
+		t.Errorf("formatString(%q) = %q parses back as %q ending at %v", s, out, parsed, end)
+	}
+}
+
+func TestFormatStringRoundTrip(t *testing.T) {
+	var all []byte
+	for b := 0; b < 256; b++ {
+		all = append(all, byte(b))
+		// alone, between printable characters so that the string is literal, and followed by octal digits
+		checkFormatString(t, string([]byte{byte(b)}))
+		checkFormatString(t, "abc"+string([]byte{byte(b)})+"def")
+		checkFormatString(t, string([]byte{byte(b)})+"777")
+	}
+	checkFormatString(t, string(all))
+	checkFormatString(t, "((unbalanced (parens")
+	checkFormatString(t, "")
+}
+
+func FuzzFormatString(f *testing.F) {
+	f.Add("plain")
+	f.Add("caf\xE9 (draft)\r\n")
+	f.Add("\x00\x01\x02\xFF")
+	f.Add(`back\slash)(`)
+	f.Fuzz(checkFormatString)
+}
@@ -0,0 +1,3 @@
+module github.com/rickymclaren/gopdf
+
+go 1.21
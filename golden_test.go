@@ -0,0 +1,209 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+)
+
+// updateGolden rewrites the golden files with the output of the tests instead of comparing them, for when the
+// output has been changed on purpose. Run the tests with UPDATE_GOLDEN=1 and check the differences before
+// committing them.
+var updateGolden = os.Getenv("UPDATE_GOLDEN") != ""
+
+// normalizer replaces the parts of a file that differ each time it is written with something that doesn't
+type normalizer func(data []byte) []byte
+
+// zeroGroups returns a normalizer that replaces what the groups of re match with zeros, which keeps the length of
+// the file and so the offsets in its cross-reference table
+func zeroGroups(re *regexp.Regexp) normalizer {
+	return func(data []byte) []byte {
+		data = bytes.Clone(data)
+		for _, m := range re.FindAllSubmatchIndex(data, -1) {
+			for g := 2; g+1 < len(m); g += 2 {
+				for i := m[g]; i >= 0 && i < m[g+1]; i++ {
+					data[i] = '0'
+				}
+			}
+		}
+		return data
+	}
+}
+
+// volatile normalizes the dates in the Info dictionary and the file ID, which are written for every document
+var volatile = []normalizer{
+	zeroGroups(regexp.MustCompile(`D:(\d{14})(?:Z|[+-](\d{2})'(\d{2})')`)),
+	zeroGroups(regexp.MustCompile(`/ID \[ <([0-9A-F]+)> <([0-9A-F]+)> \]`)),
+}
+
+// goldenCase is a document compared with a golden file in testdata/golden
+type goldenCase struct {
+	name      string
+	build     func(d *PdfDocument) error
+	normalize []normalizer // applied after volatile, for what only this document has
+}
+
+var goldenCases = []goldenCase{
+	{name: "text", build: func(d *PdfDocument) error {
+		if _, err := d.AddFont("Times", TimesRoman); err != nil {
+			return err
+		}
+		p := d.CurrentPage()
+		if err := p.SetFont("Times"); err != nil {
+			return err
+		}
+		p.SetFontSize(14)
+		p.Println("Hello, world")
+		p.Println(`Escapes: (parentheses) and \backslash`)
+		p.Print("Tab\tstop and accents: café")
+		return nil
+	}},
+	{name: "image", build: func(d *PdfDocument) error {
+		if _, err := d.AddImage("gopher", "gopher.jpg"); err != nil {
+			return err
+		}
+		img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+		for i := 0; i < 16; i++ {
+			img.Set(i%4, i/4, color.RGBA{uint8(i * 16), 0, uint8(255 - i*16), 255})
+		}
+		if _, err := d.AddImageFromImage("gradient", img); err != nil {
+			return err
+		}
+		p := d.CurrentPage()
+		if err := p.DrawImage("gopher", 100, 500); err != nil {
+			return err
+		}
+		return p.DrawImageScaled("gradient", 100, 300, 80, 80)
+	}},
+	{name: "graphics", build: func(d *PdfDocument) error {
+		p := d.CurrentPage()
+		p.SetLineWidth(2)
+		p.SetStrokeColor(200, 0, 0)
+		p.DrawLine(72, 72, 300, 300)
+		p.SetDash([]float64{4, 2}, 0)
+		p.DrawBox(100, 400, 200, 100)
+		p.FillRect(350, 400, 100, 50, 0, 128, 255)
+		p.DrawCircle(300, 650, 50, FillStroke)
+		p.SetAlpha(0.5, 1)
+		return p.DrawPolygon([]Point{{100, 700}, {150, 780}, {200, 700}}, Fill)
+	}},
+	{name: "multipage", build: func(d *PdfDocument) error {
+		d.SetTitle("Multi-page")
+		d.SetFooterFunc(func(p *PdfPage, pageNum, totalPages int) {
+			p.SetXY(280, 40)
+			p.Print(fmt.Sprintf("%v of %v", pageNum, totalPages))
+		})
+		for i := 1; i <= 3; i++ {
+			p := d.CurrentPage()
+			if i > 1 {
+				p = d.AddPage()
+			}
+			p.Println(fmt.Sprintf("Page %v", i))
+			d.AddBookmark(fmt.Sprintf("Page %v", i), p, 0, nil)
+		}
+		return nil
+	}},
+}
+
+func TestMain(m *testing.M) {
+	// dates are written with the offset of the local time zone, which would change the length of the golden files
+	time.Local = time.UTC
+	os.Exit(m.Run())
+}
+
+func TestGolden(t *testing.T) {
+	for _, c := range goldenCases {
+		t.Run(c.name, func(t *testing.T) {
+			d := NewPdfDocument()
+			d.SetCompression(false)
+			if err := c.build(d); err != nil {
+				t.Fatal(err)
+			}
+			got, err := d.Bytes()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if problems := validatePDF(got); len(problems) > 0 {
+				t.Errorf("invalid output: %v", problems)
+			}
+			checkPageResources(t, got)
+			for _, normalize := range append(volatile[:len(volatile):len(volatile)], c.normalize...) {
+				got = normalize(got)
+			}
+			compareGolden(t, filepath.Join("testdata", "golden", c.name+".pdf"), got)
+		})
+	}
+}
+
+// compareGolden compares got with the golden file at path, or writes it there if the golden files are being updated
+func compareGolden(t *testing.T, path string, got []byte) {
+	t.Helper()
+	if updateGolden {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v; run the tests with UPDATE_GOLDEN=1 to create it", err)
+	}
+	if bytes.Equal(got, want) {
+		return
+	}
+	at := 0
+	for at < len(got) && at < len(want) && got[at] == want[at] {
+		at++
+	}
+	t.Errorf("output differs from %v at byte %v:\n got %q\nwant %q", path, at, excerpt(got, at), excerpt(want, at))
+}
+
+// checkPageResources checks that the fonts, images, templates and graphics states named by the content of each page
+// of a PDF file are in the page's resources, which validatePDF doesn't look at
+func checkPageResources(t *testing.T, data []byte) {
+	t.Helper()
+	pr, err := parsePDF(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pages, err := pr.pages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, page := range pages {
+		content, err := pr.pageContent(page.dict)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resources, _ := pr.resolve(page.dict.get("Resources")).(pdfDict)
+		for _, used := range usedResources(content) {
+			if used.kind != "Font" && used.kind != "XObject" && used.kind != "ExtGState" {
+				continue
+			}
+			if dict, _ := pr.resolve(resources.get(used.kind)).(pdfDict); pr.resolve(dict.get(used.name)) == nil {
+				t.Errorf("page %v uses %v %v, which isn't in its resources", i+1, used.kind, formatName(string(used.name)))
+			}
+		}
+	}
+}
+
+func TestNormalizeKeepsLength(t *testing.T) {
+	data := []byte("/CreationDate (D:20240102030405+01'00')\r\n/ID [ <0123ABCD> <0123ABCD> ]\r\n")
+	got := data
+	for _, normalize := range volatile {
+		got = normalize(got)
+	}
+	want := "/CreationDate (D:00000000000000+00'00')\r\n/ID [ <00000000> <00000000> ]\r\n"
+	if string(got) != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
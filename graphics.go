@@ -0,0 +1,85 @@
+package gopdf
+
+import "strings"
+
+// Line cap styles for SetLineCap
+const (
+	ButtCap = iota
+	RoundCap
+	SquareCap
+)
+
+// Line join styles for SetLineJoin
+const (
+	MiterJoin = iota
+	RoundJoin
+	BevelJoin
+)
+
+// SetLineWidth sets the width in points of lines drawn after this call
+func (p *PdfPage) SetLineWidth(w float64) {
+	p.content.addGraphicsf("%v w\r\n", formatNumber(w))
+}
+
+// SetLineCap sets how the ends of lines are drawn, one of ButtCap, RoundCap or SquareCap
+func (p *PdfPage) SetLineCap(cap int) {
+	p.content.addGraphicsf("%v J\r\n", cap)
+}
+
+// SetLineJoin sets how the corners of boxes and joined lines are drawn, one of MiterJoin, RoundJoin or BevelJoin
+func (p *PdfPage) SetLineJoin(join int) {
+	p.content.addGraphicsf("%v j\r\n", join)
+}
+
+// SetDash sets the dash pattern of lines, alternating lengths of dashes and gaps in points, starting phase points
+// into the pattern. A nil pattern draws solid lines.
+func (p *PdfPage) SetDash(pattern []float64, phase float64) {
+	lengths := make([]string, len(pattern))
+	for i, l := range pattern {
+		lengths[i] = formatNumber(l)
+	}
+	p.content.addGraphicsf("[%v] %v d\r\n", strings.Join(lengths, " "), formatNumber(phase))
+}
+
+// DrawStyle selects how shapes are painted
+type DrawStyle int
+
+// Paint styles for shapes. The even-odd styles fill only the areas enclosed an odd number of times, which leaves
+// holes where shapes overlap.
+const (
+	Stroke DrawStyle = iota
+	Fill
+	FillStroke
+	FillEvenOdd
+	FillStrokeEvenOdd
+)
+
+// operator returns the path painting operator for the style
+func (s DrawStyle) operator() string {
+	switch s {
+	case Fill:
+		return "f"
+	case FillStroke:
+		return "B"
+	case FillEvenOdd:
+		return "f*"
+	case FillStrokeEvenOdd:
+		return "B*"
+	}
+	return "S"
+}
+
+// DrawBoxStyled draws a rectangle with its corner at x, y, painted with the current fill and stroke
+// colours according to style
+func (p *PdfPage) DrawBoxStyled(x, y, w, h float64, style DrawStyle) {
+	x, y, w, h = p.rect(x, y, w, h)
+	p.content.addGraphicsf("%v re\r\n%v\r\n", formatNumbers(p.columnX(x), y, w, h), style.operator())
+}
+
+// FillRect fills a rectangle with its corner at x, y in the given colour, with components from 0 to
+// 255. The current fill colour is unchanged.
+func (p *PdfPage) FillRect(x, y, w, h float64, red, green, blue int) {
+	x, y, w, h = p.rect(x, y, w, h)
+	p.content.addGraphicsf("q\r\n%v rg\r\n%v re\r\nf\r\nQ\r\n", rgb(red, green, blue),
+		formatNumbers(p.columnX(x), y, w, h))
+}
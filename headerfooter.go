@@ -0,0 +1,65 @@
+package gopdf
+
+// SetHeaderFunc sets a function that draws the header of each page. It is called for every page when the document
+// is written, with the cursor at the left margin in the middle of the top margin.
+func (d *PdfDocument) SetHeaderFunc(header func(p *PdfPage, pageNum int)) {
+	d.header = header
+}
+
+// SetFooterFunc sets a function that draws the footer of each page. It is called for every page when the document
+// is written, once the number of pages is known, with the cursor at the left margin in the middle of the bottom
+// margin.
+func (d *PdfDocument) SetFooterFunc(footer func(p *PdfPage, pageNum, totalPages int)) {
+	d.footer = footer
+}
+
+// addHeadersAndFooters adds the headers and footers to the end of each page's content and returns a function that
+// removes them again, so that the document can still be changed and written again afterwards
+func (d *PdfDocument) addHeadersAndFooters() (remove func()) {
+	if d.header == nil && d.footer == nil {
+		return func() {}
+	}
+	type savedPage struct {
+		page      PdfPage
+		streamLen int
+		inText    bool
+	}
+	pages := d.catalog.pdfPages.pages
+	saved := make([]savedPage, len(pages))
+	objects := len(d.objects)
+	for i, p := range pages {
+		saved[i] = savedPage{*p, p.content.stream.Len(), p.content.inText}
+		if !p.content.finished {
+			d.drawHeaderAndFooter(p, i+1, len(pages))
+		}
+	}
+	return func() {
+		for i, p := range pages {
+			*p = saved[i].page
+			p.content.stream.Truncate(saved[i].streamLen)
+			p.content.inText = saved[i].inText
+		}
+		d.objects = d.objects[:objects]
+	}
+}
+
+// drawHeaderAndFooter adds the header and footer to the end of the content of a page
+func (d *PdfDocument) drawHeaderAndFooter(p *PdfPage, pageNum, totalPages int) {
+	current := d.currentPage
+	d.currentPage = p
+	// isolate the graphics state so the header and footer don't change the page's content or each other
+	p.closeSavedStates()
+	if d.header != nil {
+		p.SaveState()
+		p.x, p.y = p.leftMargin, p.height-(p.topMargin+p.fontSize)/2
+		d.header(p, pageNum)
+		p.closeSavedStates()
+	}
+	if d.footer != nil {
+		p.SaveState()
+		p.x, p.y = p.leftMargin, (p.bottomMargin-p.fontSize)/2
+		d.footer(p, pageNum, totalPages)
+		p.closeSavedStates()
+	}
+	d.currentPage = current
+}
@@ -0,0 +1,131 @@
+package gopdf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// SetOutputIntent embeds an ICC profile describing the colours the document is intended for, such as those of a
+// printing press, under the name identifier. The output intent is for PDF/A in PDF/A mode and for PDF/X otherwise.
+// The profile must be for an RGB, CMYK or gray colour space, otherwise ErrInvalidICCProfile is returned.
+func (d *PdfDocument) SetOutputIntent(profile []byte, identifier string) error {
+	if len(profile) < 128 {
+		return fmt.Errorf("%w: too short", ErrInvalidICCProfile)
+	}
+	var components int
+	switch space := string(profile[16:20]); space {
+	case "GRAY":
+		components = 1
+	case "RGB ":
+		components = 3
+	case "CMYK":
+		components = 4
+	default:
+		return fmt.Errorf("%w: colour space %q", ErrInvalidICCProfile, space)
+	}
+	d.setOutputIntent(profile, components, identifier)
+	return nil
+}
+
+// setOutputIntent sets the output intent to an ICC profile with the given number of colour components
+func (d *PdfDocument) setOutputIntent(profile []byte, components int, identifier string) {
+	if intent := d.catalog.outputIntent; intent != nil {
+		intent.identifier, intent.automatic = identifier, false
+		intent.profile.components, intent.profile.data = components, profile
+		return
+	}
+	intent := &PdfOutputIntent{identifier: identifier, profile: &PdfICCProfile{components: components, data: profile}}
+	d.addObject(intent)
+	d.addObject(intent.profile)
+	d.catalog.outputIntent = intent
+}
+
+// SetICCColors turns on or off drawing colours and images in the colour space of the output intent's profile, rather
+// than in the device colour space with the same number of components, so that they are reproduced accurately
+func (d *PdfDocument) SetICCColors(enabled bool) {
+	d.resources.iccColors = enabled
+}
+
+// defaultColorSpace returns the resource entry that replaces the device colour space with the output intent's
+// profile, or an empty string if there is none
+func (r PdfResources) defaultColorSpace() string {
+	intent := r.document.catalog.outputIntent
+	if !r.iccColors || intent == nil {
+		return ""
+	}
+	name := map[int]string{1: "DefaultGray", 3: "DefaultRGB", 4: "DefaultCMYK"}[intent.profile.components]
+	return fmt.Sprintf("/%v [ /ICCBased %v ] ", name, intent.profile.objectRef())
+}
+
+// srgbProfile builds a version 2 ICC profile for the sRGB colour space, with the primaries adapted to the D50
+// white point of the profile connection space and the sRGB tone curve sampled at 1024 points
+func srgbProfile() []byte {
+	xyz := func(x, y, z float64) []byte {
+		data := []byte("XYZ \x00\x00\x00\x00")
+		for _, v := range []float64{x, y, z} {
+			data = binary.BigEndian.AppendUint32(data, uint32(int32(math.Round(v*65536))))
+		}
+		return data
+	}
+	curve := []byte("curv\x00\x00\x00\x00")
+	curve = binary.BigEndian.AppendUint32(curve, 1024)
+	for i := 0; i < 1024; i++ {
+		v := float64(i) / 1023
+		if v <= 0.04045 {
+			v /= 12.92
+		} else {
+			v = math.Pow((v+0.055)/1.055, 2.4)
+		}
+		curve = binary.BigEndian.AppendUint16(curve, uint16(math.Round(v*65535)))
+	}
+	description := "sRGB IEC61966-2.1"
+	desc := []byte("desc\x00\x00\x00\x00")
+	desc = binary.BigEndian.AppendUint32(desc, uint32(len(description)+1))
+	desc = append(append(desc, description...), 0)
+	// empty Unicode and ScriptCode descriptions
+	desc = append(desc, make([]byte, 4+4+2+1+67)...)
+	copyright := append([]byte("text\x00\x00\x00\x00No copyright, use freely"), 0)
+
+	tags := []struct {
+		signature string
+		data      []byte
+	}{
+		{"desc", desc},
+		{"cprt", copyright},
+		{"wtpt", xyz(0.9642, 1, 0.8249)},
+		{"rXYZ", xyz(0.4361, 0.2225, 0.0139)},
+		{"gXYZ", xyz(0.3851, 0.7169, 0.0971)},
+		{"bXYZ", xyz(0.1431, 0.0606, 0.7141)},
+		{"rTRC", curve},
+		{"gTRC", curve},
+		{"bTRC", curve},
+	}
+
+	var table, data bytes.Buffer
+	offset := 128 + 4 + 12*len(tags)
+	binary.Write(&table, binary.BigEndian, uint32(len(tags)))
+	for _, tag := range tags {
+		table.WriteString(tag.signature)
+		binary.Write(&table, binary.BigEndian, uint32(offset+data.Len()))
+		binary.Write(&table, binary.BigEndian, uint32(len(tag.data)))
+		data.Write(tag.data)
+		// tag data starts on a four byte boundary
+		for data.Len()%4 != 0 {
+			data.WriteByte(0)
+		}
+	}
+
+	header := make([]byte, 128)
+	binary.BigEndian.PutUint32(header[0:], uint32(128+table.Len()+data.Len()))
+	binary.BigEndian.PutUint32(header[8:], 0x02100000) // version 2.1
+	copy(header[12:], "mntrRGB XYZ ")
+	binary.BigEndian.PutUint16(header[24:], 2000) // creation date, 1 January 2000
+	binary.BigEndian.PutUint16(header[26:], 1)
+	binary.BigEndian.PutUint16(header[28:], 1)
+	copy(header[36:], "acsp")
+	copy(header[68:], xyz(0.9642, 1, 0.8249)[8:]) // D50 illuminant
+	profile := append(header, table.Bytes()...)
+	return append(profile, data.Bytes()...)
+}
@@ -0,0 +1,444 @@
+package gopdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/ascii85"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"  // register GIF decoder
+	_ "image/jpeg" // register JPEG decoder
+	_ "image/png"  // register PNG decoder
+	"io"
+)
+
+// PdfImage represents an image resource
+type PdfImage struct {
+	PdfObject
+	name                    string
+	aliases                 []string                    // other names the image was added under
+	source                  func() (image.Image, error) // decodes the image again for resampling, if SetImageMaxDPI is on
+	drawnWidth, drawnHeight float64                     // the largest size the image has been drawn at in points
+	width                   int
+	height                  int
+	colorSpace              string // a name or, for images with a palette, an Indexed colour space array
+	bits                    int    // bits per component
+	palette                 []byte // the gray values or RGB triples of an Indexed colour space
+	mask                    string // the colour key /Mask array
+	stencil                 bool   // an /ImageMask painted in the fill colour
+	orientation             int    // the EXIF orientation of a JPEG, or 0
+	decode                  string // the Decode array, for Adobe CMYK JPEGs with inverted components
+	decodeParms             string // the parameters of the filter, for CCITT fax images
+	filter                  string
+	data                    []byte
+}
+
+// loadImageData loads an image from the contents of an image file, source names where it came from in errors
+func (pi *PdfImage) loadImageData(name string, source string, file []byte) error {
+	pi.name = name
+	if pi.loadJPEG(file) {
+		return nil
+	}
+	if isTIFF(file) {
+		if err := pi.loadTIFF(file); err != nil {
+			return fmt.Errorf("gopdf: decoding image %v from %v: %w", name, source, err)
+		}
+		return nil
+	}
+	image, err := decodeImage(file)
+	if err != nil {
+		return fmt.Errorf("gopdf: decoding image %v from %v: %w", name, source, err)
+	}
+	return pi.loadPixels(image)
+}
+
+// decodeImage decodes an image file in any of the supported formats
+func decodeImage(file []byte) (image.Image, error) {
+	switch {
+	case isTIFF(file):
+		return decodeTIFF(file)
+	case isBMP(file):
+		return decodeBMP(file)
+	}
+	img, _, err := image.Decode(bytes.NewReader(file))
+	return img, err
+}
+
+// loadPixels stores the pixels of img compressed in the smallest form that keeps them exactly: one bit per pixel for
+// black and white images, palette indexes for images with a palette, one byte per pixel for grayscale and three for
+// colour. CMYK images keep their four components.
+func (pi *PdfImage) loadPixels(img image.Image) error {
+	bounds := img.Bounds()
+	pi.width = bounds.Size().X
+	pi.height = bounds.Size().Y
+	pi.bits = 8
+	var pixels []byte
+	switch img := img.(type) {
+	case *image.CMYK:
+		pixels = make([]byte, 0, pi.width*pi.height*4)
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			at := img.PixOffset(bounds.Min.X, y)
+			pixels = append(pixels, img.Pix[at:at+4*pi.width]...)
+		}
+		pi.colorSpace = "/DeviceCMYK"
+	case *image.Paletted:
+		pixels = pi.indexedPixels(img)
+	}
+	if pixels == nil {
+		var gray bool
+		pixels, gray = pixelData(img)
+		pi.colorSpace = "/DeviceRGB"
+		if gray {
+			pi.colorSpace = "/DeviceGray"
+			if isBilevel(pixels) {
+				pi.bits = 1
+				pixels = packBits(pixels, pi.width, 1, func(v byte) byte { return v >> 7 })
+			}
+		}
+	}
+	var compressed bytes.Buffer
+	fw := zlib.NewWriter(&compressed)
+	pi.compressRows(fw, pixels)
+	if err := fw.Close(); err != nil {
+		return err
+	}
+	pi.filter = "/FlateDecode"
+	pi.data = compressed.Bytes()
+	return nil
+}
+
+// progressRows is the number of rows of an image compressed between calls to the function set by OnProgress
+const progressRows = 256
+
+// compressRows writes the pixels of the image to fw, a batch of rows at a time when the document reports progress
+func (pi *PdfImage) compressRows(fw io.Writer, pixels []byte) {
+	if pi.document == nil || pi.document.progress == nil || pi.height == 0 {
+		fw.Write(pixels)
+		return
+	}
+	// every row takes the same number of bytes
+	rowBytes := len(pixels) / pi.height
+	for y := 0; y < pi.height; y += progressRows {
+		end := min(y+progressRows, pi.height)
+		if end == pi.height {
+			fw.Write(pixels[y*rowBytes:])
+		} else {
+			fw.Write(pixels[y*rowBytes : end*rowBytes])
+		}
+		pi.document.reportProgress(end, pi.height)
+	}
+}
+
+// indexedPixels returns the pixels of img as packed palette indexes with as few bits as the palette needs, and sets
+// an Indexed colour space with the palette and a colour key mask for a transparent palette entry. It returns nil for a black and white image, which is smaller as one bit
+// gray, or an image with pixels outside its palette.
+func (pi *PdfImage) indexedPixels(img *image.Paletted) []byte {
+	palette := make([]byte, 0, 3*len(img.Palette))
+	transparent := -1
+	for i, c := range img.Palette {
+		r, g, b, a := c.RGBA()
+		palette = append(palette, byte(r>>8), byte(g>>8), byte(b>>8))
+		if a == 0 && transparent < 0 {
+			transparent = i
+		}
+	}
+	gray := isGrayRGB(palette)
+	if gray {
+		palette = grayFromRGB(palette)
+	}
+	bounds := img.Bounds()
+	indexes := make([]byte, 0, pi.width*pi.height)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		at := img.PixOffset(bounds.Min.X, y)
+		indexes = append(indexes, img.Pix[at:at+pi.width]...)
+	}
+	for _, index := range indexes {
+		if int(index) >= len(img.Palette) {
+			return nil
+		}
+	}
+	if len(img.Palette) == 0 || gray && isBilevel(palette) && transparent < 0 {
+		return nil
+	}
+	if transparent >= 0 {
+		// a transparent palette entry, as GIFs have, is masked with a colour key
+		pi.mask = fmt.Sprintf("[ %v %v ]", transparent, transparent)
+	}
+	pi.palette = palette
+	pi.bits = 8
+	for _, bits := range []int{4, 2, 1} {
+		if len(img.Palette) <= 1<<bits {
+			pi.bits = bits
+		}
+	}
+	base := "/DeviceRGB"
+	if gray {
+		base = "/DeviceGray"
+	}
+	pi.colorSpace = fmt.Sprintf("[ /Indexed %v %v <%X> ]", base, len(img.Palette)-1, palette)
+	return packBits(indexes, pi.width, pi.bits, func(v byte) byte { return v })
+}
+
+// isBilevel reports whether every gray value in pixels is black or white
+func isBilevel(pixels []byte) bool {
+	for _, v := range pixels {
+		if v != 0 && v != 255 {
+			return false
+		}
+	}
+	return true
+}
+
+// packBits packs rows of width values into bits bits each, with value giving the bits of each one, starting each
+// row on a new byte as image data requires
+func packBits(values []byte, width, bits int, value func(v byte) byte) []byte {
+	if bits == 8 {
+		return values
+	}
+	rowBytes := (width*bits + 7) / 8
+	packed := make([]byte, 0, rowBytes*len(values)/max(width, 1))
+	for row := 0; row+width <= len(values) && width > 0; row += width {
+		var b byte
+		used := 0
+		for _, v := range values[row : row+width] {
+			b = b<<bits | value(v)
+			used += bits
+			if used == 8 {
+				packed = append(packed, b)
+				b, used = 0, 0
+			}
+		}
+		if used > 0 {
+			packed = append(packed, b<<(8-used))
+		}
+	}
+	return packed
+}
+
+// pixelData returns the pixels of img as 8 bit gray values when every pixel is a shade of gray, otherwise as 8 bit
+// RGB triples. The common image types are read directly rather than a pixel at a time through At.
+func pixelData(img image.Image) (pixels []byte, gray bool) {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	switch img := img.(type) {
+	case *image.Gray:
+		pixels = make([]byte, 0, w*h)
+		for y := 0; y < h; y++ {
+			at := img.PixOffset(bounds.Min.X, bounds.Min.Y+y)
+			pixels = append(pixels, img.Pix[at:at+w]...)
+		}
+		return pixels, true
+	case *image.RGBA:
+		return fourBytePixels(img.Pix, img.Stride, img.PixOffset(bounds.Min.X, bounds.Min.Y), w, h)
+	case *image.NRGBA:
+		return fourBytePixels(img.Pix, img.Stride, img.PixOffset(bounds.Min.X, bounds.Min.Y), w, h)
+	case *image.Paletted:
+		palette := make([][3]byte, len(img.Palette))
+		gray = true
+		for i, c := range img.Palette {
+			r, g, b, _ := c.RGBA()
+			palette[i] = [3]byte{byte(r >> 8), byte(g >> 8), byte(b >> 8)}
+			gray = gray && palette[i][0] == palette[i][1] && palette[i][1] == palette[i][2]
+		}
+		pixels = make([]byte, 0, w*h*3)
+		for y := 0; y < h; y++ {
+			at := img.PixOffset(bounds.Min.X, bounds.Min.Y+y)
+			for _, index := range img.Pix[at : at+w] {
+				var c [3]byte
+				if int(index) < len(palette) {
+					c = palette[index]
+				}
+				if gray {
+					pixels = append(pixels, c[0])
+				} else {
+					pixels = append(pixels, c[:]...)
+				}
+			}
+		}
+		return pixels, gray
+	}
+
+	gray = img.ColorModel() == color.GrayModel || img.ColorModel() == color.Gray16Model
+	pixels = make([]byte, 0, w*h*3)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r, g, b, _ := img.At(x, y).RGBA()
+			pixels = append(pixels, byte(r>>8), byte(g>>8), byte(b>>8))
+		}
+	}
+	if !gray {
+		gray = isGrayRGB(pixels)
+	}
+	if gray {
+		pixels = grayFromRGB(pixels)
+	}
+	return pixels, gray
+}
+
+// fourBytePixels converts RGBA or NRGBA pixel data to RGB or, when every pixel is gray, to gray values. The alpha
+// channel is dropped.
+func fourBytePixels(pix []byte, stride, offset, w, h int) ([]byte, bool) {
+	pixels := make([]byte, 0, w*h*3)
+	for y := 0; y < h; y++ {
+		row := pix[offset+y*stride : offset+y*stride+4*w]
+		for x := 0; x < len(row); x += 4 {
+			pixels = append(pixels, row[x], row[x+1], row[x+2])
+		}
+	}
+	if isGrayRGB(pixels) {
+		return grayFromRGB(pixels), true
+	}
+	return pixels, false
+}
+
+// isGrayRGB reports whether every RGB triple in pixels is a shade of gray
+func isGrayRGB(pixels []byte) bool {
+	for i := 0; i+2 < len(pixels); i += 3 {
+		if pixels[i] != pixels[i+1] || pixels[i+1] != pixels[i+2] {
+			return false
+		}
+	}
+	return true
+}
+
+// grayFromRGB keeps one value from each RGB triple of a gray image
+func grayFromRGB(pixels []byte) []byte {
+	gray := make([]byte, len(pixels)/3)
+	for i := range gray {
+		gray[i] = pixels[3*i]
+	}
+	return gray
+}
+
+// loadJPEG uses the data of a JPEG file as it is, since viewers can decode it themselves. It reports false for
+// anything other than a baseline or progressive JPEG, such as lossless or arithmetic coded files that viewers
+// rarely support, so that the image is decoded and re-encoded instead.
+func (pi *PdfImage) loadJPEG(file []byte) bool {
+	if len(file) < 4 || file[0] != 0xFF || file[1] != 0xD8 {
+		return false
+	}
+	adobe := false
+	for at := 2; at+4 <= len(file); {
+		if file[at] != 0xFF {
+			return false
+		}
+		marker := file[at+1]
+		if marker == 0xFF {
+			// fill byte
+			at++
+			continue
+		}
+		length := int(file[at+2])<<8 | int(file[at+3])
+		switch marker {
+		case 0xC0, 0xC1, 0xC2: // baseline, extended sequential and progressive
+			if length < 8 || at+10 > len(file) {
+				return false
+			}
+			sof := file[at+4:]
+			if sof[0] != 8 {
+				return false
+			}
+			switch sof[5] {
+			case 1:
+				pi.colorSpace = "/DeviceGray"
+			case 3:
+				pi.colorSpace = "/DeviceRGB"
+			case 4:
+				pi.colorSpace = "/DeviceCMYK"
+				if adobe {
+					// Photoshop writes CMYK JPEGs with the components inverted
+					pi.decode = "[ 1 0 1 0 1 0 1 0 ]"
+				}
+			default:
+				return false
+			}
+			pi.height = int(sof[1])<<8 | int(sof[2])
+			pi.width = int(sof[3])<<8 | int(sof[4])
+			pi.bits = 8
+			pi.filter = "/DCTDecode"
+			pi.data = file
+			return pi.width > 0 && pi.height > 0
+		case 0xD9, 0xDA: // end of image or start of scan before a frame header
+			return false
+		case 0xE1: // APP1
+			if o := exifOrientation(file[at+4 : min(at+2+length, len(file))]); o != 0 {
+				pi.orientation = o
+			}
+		case 0xEE: // APP14
+			adobe = adobe || bytes.HasPrefix(file[at+4:], []byte("Adobe"))
+		}
+		if 0xC2 <= marker && marker <= 0xCF && marker != 0xC4 && marker != 0xC8 && marker != 0xCC {
+			// lossless or arithmetic coded
+			return false
+		}
+		at += 2 + length
+	}
+	return false
+}
+
+// image returns the image added under name, or ErrImageNotFound listing the images that have been added
+func (r *PdfResources) image(name string) (*PdfImage, error) {
+	if i, ok := r.imagesByName[name]; ok {
+		return i, nil
+	}
+	var names []string
+	for _, image := range r.images {
+		names = append(names, image.name)
+		names = append(names, image.aliases...)
+	}
+	return nil, fmt.Errorf("%w: %v (%v)", ErrImageNotFound, name, availableNames("images", names))
+}
+
+// scaledSize returns the size to draw the image at when asked for w by h. A size of 0 is worked out from the other
+// to keep the aspect ratio, and if both are 0 the size in pixels is used. Sizes are of the image once it is upright.
+func (pi *PdfImage) scaledSize(w, h float64) (float64, float64) {
+	width, height := pi.displaySize()
+	switch {
+	case w == 0 && h == 0:
+		return width, height
+	case w == 0:
+		return h * width / height, h
+	case h == 0:
+		return w, w * height / width
+	}
+	return w, h
+}
+
+func (pi PdfImage) bytes() []byte {
+	data, filter, parms := pi.data, pi.filter, pi.decodeParms
+	if pi.document.asciiImages {
+		var ascii bytes.Buffer
+		encoder := ascii85.NewEncoder(&ascii)
+		encoder.Write(data)
+		encoder.Close()
+		ascii.WriteString("~>")
+		data, filter = ascii.Bytes(), fmt.Sprintf("[ /ASCII85Decode %v ]", filter)
+		if parms != "" {
+			parms = fmt.Sprintf("[ null %v ]", parms)
+		}
+	}
+	var entries bytes.Buffer
+	fmt.Fprintf(&entries, "/Type /XObject\r\n")
+	fmt.Fprintf(&entries, "/Subtype /Image\r\n")
+	fmt.Fprintf(&entries, "/Name %v\r\n", formatName(pi.name))
+	fmt.Fprintf(&entries, "/Width %v\r\n", pi.width)
+	fmt.Fprintf(&entries, "/Height %v\r\n", pi.height)
+	fmt.Fprintf(&entries, "/BitsPerComponent %v\r\n", pi.bits)
+	if pi.stencil {
+		fmt.Fprintf(&entries, "/ImageMask true\r\n")
+	} else {
+		fmt.Fprintf(&entries, "/ColorSpace %v\r\n", pi.colorSpace)
+	}
+	if pi.mask != "" {
+		fmt.Fprintf(&entries, "/Mask %v\r\n", pi.mask)
+	}
+	if pi.decode != "" {
+		fmt.Fprintf(&entries, "/Decode %v\r\n", pi.decode)
+	}
+	fmt.Fprintf(&entries, "/Filter %v\r\n", filter)
+	if parms != "" {
+		fmt.Fprintf(&entries, "/DecodeParms %v\r\n", parms)
+	}
+	return streamObject(pi.id, entries.String(), data)
+}
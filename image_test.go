@@ -0,0 +1,117 @@
+package gopdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"image"
+	"image/color"
+	"io"
+	"os"
+	"testing"
+)
+
+// imageXObject returns the stream of the image drawn under name on the first page of a PDF file
+func imageXObject(t *testing.T, data []byte, name string) *pdfStream {
+	t.Helper()
+	pr, err := parsePDF(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pages, err := pr.pages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	resources, _ := pr.resolve(pages[0].dict.get("Resources")).(pdfDict)
+	xobjects, _ := pr.resolve(resources.get("XObject")).(pdfDict)
+	stream, ok := pr.resolve(xobjects.get(pdfName(name))).(*pdfStream)
+	if !ok {
+		t.Fatalf("image %v isn't on the page", name)
+	}
+	return stream
+}
+
+func TestJPEGPassthrough(t *testing.T) {
+	file, err := os.ReadFile("gopher.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := NewPdfDocument()
+	if _, err := d.AddImage("gopher", "gopher.jpg"); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.CurrentPage().DrawImage("gopher", 100, 100); err != nil {
+		t.Fatal(err)
+	}
+	data, err := d.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stream := imageXObject(t, data, "gopher")
+	if filter := stream.dict.get("Filter"); filter != pdfName("DCTDecode") {
+		t.Errorf("the JPEG is written with filter %v", filter)
+	}
+	if length := stream.dict.get("Length"); length != len(file) || !bytes.Equal(stream.data, file) {
+		t.Errorf("the JPEG is written as %v bytes, not the %v of the file", length, len(file))
+	}
+	if len(data) > len(file)+4096 {
+		t.Errorf("the document is %v bytes for a JPEG of %v", len(data), len(file))
+	}
+}
+
+func TestGrayImage(t *testing.T) {
+	// the same noise, which doesn't compress, with the blue of every pixel of the colour image off by one
+	const w, h = 64, 64
+	gray, rgb := image.NewRGBA(image.Rect(0, 0, w, h)), image.NewRGBA(image.Rect(0, 0, w, h))
+	v := uint32(1)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			v = v*1664525 + 1013904223
+			c := uint8(v >> 24)
+			gray.Set(x, y, color.RGBA{c, c, c, 255})
+			rgb.Set(x, y, color.RGBA{c, c, c ^ 1, 255})
+		}
+	}
+	stream := func(img image.Image) (*pdfStream, []byte) {
+		d := NewPdfDocument()
+		if _, err := d.AddImageFromImage("img", img); err != nil {
+			t.Fatal(err)
+		}
+		if err := d.CurrentPage().DrawImage("img", 100, 100); err != nil {
+			t.Fatal(err)
+		}
+		data, err := d.Bytes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		s := imageXObject(t, data, "img")
+		r, err := zlib.NewReader(bytes.NewReader(s.data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		pixels, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s, pixels
+	}
+	grayStream, grayPixels := stream(gray)
+	rgbStream, rgbPixels := stream(rgb)
+	if cs := grayStream.dict.get("ColorSpace"); cs != pdfName("DeviceGray") {
+		t.Errorf("the gray image has colour space %v", cs)
+	}
+	if cs := rgbStream.dict.get("ColorSpace"); cs != pdfName("DeviceRGB") {
+		t.Errorf("the colour image has colour space %v", cs)
+	}
+	if len(grayPixels) != w*h || len(rgbPixels) != 3*w*h {
+		t.Errorf("the images have %v and %v bytes of pixels, want %v and %v", len(grayPixels), len(rgbPixels), w*h,
+			3*w*h)
+	}
+	for i := 0; i < min(len(grayPixels), len(rgbPixels)/3); i++ {
+		if grayPixels[i] != rgbPixels[3*i] {
+			t.Fatalf("gray pixel %v is %v, want %v", i, grayPixels[i], rgbPixels[3*i])
+		}
+	}
+	if ratio := float64(len(grayStream.data)) / float64(len(rgbStream.data)); ratio < 0.25 || ratio > 0.4 {
+		t.Errorf("the gray image stream is %v bytes and the colour one %v", len(grayStream.data), len(rgbStream.data))
+	}
+}
@@ -0,0 +1,266 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+)
+
+// ImportPageAsTemplate adds the page at pageIndex, counting from 0, of the PDF file read from r, which is size bytes
+// long, to the document as a template under the given name, so that it can be drawn under or over other content
+// with UseTemplate, such as a letterhead designed elsewhere. The template is the size of the page's media box, turned
+// by the page's rotation. Resources of the page with the same names as the document's own are renamed in the
+// template's resources and in its content, and the page's annotations are not copied.
+func (d *PdfDocument) ImportPageAsTemplate(name string, r io.ReaderAt, size int64, pageIndex int) (*PdfTemplate,
+	error) {
+	if err := d.checkImageName(name); err != nil {
+		return nil, err
+	}
+	pr, err := newPDFReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+	pages, err := pr.pages()
+	if err != nil {
+		return nil, err
+	}
+	if pageIndex < 0 || pageIndex >= len(pages) {
+		return nil, fmt.Errorf("%w: page %v of %v", ErrPageOutOfRange, pageIndex, len(pages))
+	}
+	page := pages[pageIndex].dict
+
+	pageSize := d.pageSize.oriented(d.orientation)
+	box := [4]float64{0, 0, pageSize.Width, pageSize.Height}
+	if mediaBox, ok := pr.resolve(page.get("MediaBox")).([]any); ok && len(mediaBox) == 4 {
+		for i, v := range mediaBox {
+			box[i] = boxNumber(pr, v)
+		}
+	}
+	content, err := pr.pageContent(page)
+	if err != nil {
+		return nil, err
+	}
+	resources, _ := pr.resolve(page.get("Resources")).(pdfDict)
+	renames := d.importNames(pr, resources)
+	if content, err = renameResources(content, renames); err != nil {
+		return nil, err
+	}
+
+	im := newPDFImporter(pr, d)
+	copied := pdfDict{}
+	for _, e := range resources {
+		v := e.value
+		if category, ok := renames[e.key]; ok {
+			dict := pdfDict{}
+			for _, resource := range pr.resolve(v).(pdfDict) {
+				if renamed, ok := category[resource.key]; ok {
+					resource.key = renamed
+				}
+				dict = append(dict, resource)
+			}
+			v = dict
+		}
+		copied = append(copied, pdfEntry{e.key, im.copyValue(v)})
+	}
+	if err := im.copyPending(); err != nil {
+		return nil, err
+	}
+
+	// the matrix moves the corner of the media box to the origin, where UseTemplate places the corner of a template,
+	// and turns the page by its rotation
+	matrix, w, h := formMatrix(box, (int(boxNumber(pr, page.get("Rotate")))%360+360)%360)
+	form := pdfDict{{"BBox", []any{box[0], box[1], box[2], box[3]}}, {"Matrix", matrix}, {"Resources", copied}}
+	t := &PdfTemplate{name: name, width: w, height: h, imported: &pdfStream{dict: form, data: content}}
+	d.addObject(t)
+	d.resources.templates = append(d.resources.templates, t)
+	return t, nil
+}
+
+// ImportPageAsTemplateFile adds a page of a PDF file to the document as a template, as for ImportPageAsTemplate
+func (d *PdfDocument) ImportPageAsTemplateFile(name string, filename string, pageIndex int) (*PdfTemplate, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	return d.ImportPageAsTemplate(name, f, info.Size(), pageIndex)
+}
+
+// pageContent returns the decoded content of a page, joining its content streams when it has more than one
+func (pr *pdfReader) pageContent(page pdfDict) ([]byte, error) {
+	contents := pr.resolve(page.get("Contents"))
+	if s, ok := contents.(*pdfStream); ok {
+		contents = []any{s}
+	}
+	list, _ := contents.([]any)
+	var content []byte
+	for _, v := range list {
+		s, ok := pr.resolve(v).(*pdfStream)
+		if !ok {
+			continue
+		}
+		data, err := pr.decode(s)
+		if err != nil {
+			return nil, err
+		}
+		content = append(append(content, data...), '\n')
+	}
+	return content, nil
+}
+
+// importNames returns the new names of the resources of an imported page that have the same names as the
+// document's own resources of the same kind, by the kind of resource. The new names are the old ones with a number
+// added.
+func (d *PdfDocument) importNames(pr *pdfReader, resources pdfDict) map[pdfName]map[pdfName]pdfName {
+	renames := map[pdfName]map[pdfName]pdfName{}
+	for _, e := range resources {
+		dict, ok := pr.resolve(e.value).(pdfDict)
+		native := d.resourceNames(e.key)
+		if !ok || len(native) == 0 {
+			continue
+		}
+		taken := map[pdfName]bool{}
+		for _, resource := range dict {
+			taken[resource.key] = true
+		}
+		clashes := map[pdfName]bool{}
+		for _, name := range native {
+			clashes[pdfName(name)] = taken[pdfName(name)]
+			taken[pdfName(name)] = true
+		}
+		category := map[pdfName]pdfName{}
+		for _, resource := range dict {
+			if !clashes[resource.key] {
+				continue
+			}
+			for n := 1; ; n++ {
+				renamed := pdfName(string(resource.key) + "_" + strconv.Itoa(n))
+				if !taken[renamed] {
+					category[resource.key] = renamed
+					taken[renamed] = true
+					break
+				}
+			}
+		}
+		if len(category) > 0 {
+			renames[e.key] = category
+		}
+	}
+	return renames
+}
+
+// resourceNames returns the names the document uses for its own resources of a kind, such as Font or XObject
+func (d *PdfDocument) resourceNames(kind pdfName) []string {
+	r := d.resources
+	var names []string
+	switch kind {
+	case "Font":
+		for _, font := range r.fonts {
+			names = append(names, font.name)
+		}
+	case "XObject":
+		for name := range r.imagesByName {
+			names = append(names, name)
+		}
+		for _, template := range r.templates {
+			names = append(names, template.name)
+		}
+		names = append(names, watermarkName)
+	case "ExtGState":
+		for _, gs := range r.extGStates {
+			names = append(names, gs.name)
+		}
+	case "Pattern":
+		for _, pattern := range r.patterns {
+			names = append(names, pattern.name)
+		}
+	case "ColorSpace":
+		for _, s := range r.spotColors {
+			names = append(names, s.resource)
+		}
+		names = append(names, "DefaultGray", "DefaultRGB", "DefaultCMYK")
+	}
+	return names
+}
+
+// resourceOperators are the content stream operators that name a resource, and the kind of resource they name
+var resourceOperators = map[string]pdfName{
+	"Tf": "Font", "Do": "XObject", "gs": "ExtGState", "cs": "ColorSpace", "CS": "ColorSpace", "scn": "Pattern",
+	"SCN": "Pattern", "sh": "Shading", "BDC": "Properties", "DP": "Properties",
+}
+
+// renameResources returns content with the names of resources changed as given by kind of resource. Only the
+// operands that name resources are changed, and everything else is copied as it is.
+func renameResources(content []byte, renames map[pdfName]map[pdfName]pdfName) ([]byte, error) {
+	type operand struct {
+		start, end int
+		value      any
+	}
+	var out bytes.Buffer
+	copied := 0
+	var operands []operand
+	p := &pdfParser{data: content}
+	for {
+		p.skipSpace()
+		if p.pos >= len(content) {
+			break
+		}
+		start := p.pos
+		if c := content[p.pos]; !isPDFDelimiter(c) {
+			token := p.keyword()
+			if _, err := strconv.ParseFloat(token, 64); err != nil && token != "true" && token != "false" &&
+				token != "null" {
+				if token == "ID" {
+					// the data of an inline image runs to EI
+					p.pos = inlineImageEnd(content, p.pos)
+				}
+				kind := resourceOperators[token]
+				if len(operands) > 0 && kind != "" {
+					// Tf names its font first, and the other operators name their resource last
+					o := operands[len(operands)-1]
+					if token == "Tf" {
+						o = operands[0]
+					}
+					if name, ok := o.value.(pdfName); ok {
+						if renamed, ok := renames[kind][name]; ok {
+							out.Write(content[copied:o.start])
+							out.WriteString(formatName(string(renamed)))
+							copied = o.end
+						}
+					}
+				}
+				operands = operands[:0]
+				continue
+			}
+			p.pos = start
+		} else if c == ')' || c == '>' || c == ']' || c == '{' || c == '}' {
+			p.pos++
+			continue
+		}
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, operand{start, p.pos, v})
+	}
+	out.Write(content[copied:])
+	return out.Bytes(), nil
+}
+
+// inlineImageEnd returns the position of the EI operator that ends the data of an inline image starting after the
+// ID operator at pos
+func inlineImageEnd(content []byte, pos int) int {
+	for i := pos + 1; i+2 <= len(content); i++ {
+		if content[i] == 'E' && content[i+1] == 'I' && isPDFSpace(content[i-1]) &&
+			(i+2 == len(content) || isPDFDelimiter(content[i+2])) {
+			return i
+		}
+	}
+	return len(content)
+}
@@ -0,0 +1,283 @@
+package gopdf
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// cutMarkGap is the space in points left around each page on a sheet for its cut marks
+const cutMarkGap = 12
+
+// SetCutMarks sets whether WriteNUp and WriteBooklet draw marks at the corners of each page on a sheet to show where
+// to cut. The pages are made smaller to leave room for the marks.
+func (d *PdfDocument) SetCutMarks(on bool) {
+	d.cutMarks = on
+}
+
+// WriteNUp writes the document to w with its pages arranged cols by rows on each sheet, along the rows from the top
+// left, such as 2 by 1 for proofing two pages side by side. The sheets are the document's default page size, turned
+// landscape when there are more columns than rows, and each page is scaled to fit its share of a sheet without
+// changing its proportions. Links, bookmarks and form fields are left out. The document itself isn't changed, so it
+// can still be written as it is.
+func (d *PdfDocument) WriteNUp(w io.Writer, cols, rows int) error {
+	if cols < 1 || rows < 1 {
+		return fmt.Errorf("%w: %v by %v", ErrInvalidNUp, cols, rows)
+	}
+	_, err := d.write(context.Background(), w, func() (func(), error) {
+		order := make([]int, len(d.catalog.pdfPages.pages))
+		for i := range order {
+			order[i] = i
+		}
+		return d.impose(cols, rows, order)
+	})
+	return err
+}
+
+// WriteBooklet writes the document to w as a booklet, with two pages side by side on each side of a landscape sheet
+// ordered so that the sheets can be printed on both sides, stacked and folded in half. The first sheet has the last
+// and first pages on its front and the second and second to last on its back, and so on inwards. Blank pages are
+// added at the end to make the number of pages a multiple of four. As for WriteNUp, the document itself isn't
+// changed.
+func (d *PdfDocument) WriteBooklet(w io.Writer) error {
+	_, err := d.write(context.Background(), w, func() (func(), error) {
+		n := len(d.catalog.pdfPages.pages)
+		sheets := (n + 3) / 4
+		page := func(i int) int {
+			if i >= n {
+				return -1
+			}
+			return i
+		}
+		var order []int
+		for i := 0; i < sheets; i++ {
+			last := sheets*4 - 1 - 2*i
+			order = append(order, page(last), page(2*i), page(2*i+1), page(last-1))
+		}
+		return d.impose(2, 1, order)
+	})
+	return err
+}
+
+// impose replaces the pages of the document with sheets that show them cols by rows as form XObjects, and returns a
+// function that puts the pages back. order lists the index of the page in each place on the sheets in turn, or -1
+// to leave a place blank. The objects only the pages need are left out while the sheets are written, along with the
+// bookmarks, named destinations and form, since they refer to the pages.
+func (d *PdfDocument) impose(cols, rows int, order []int) (restore func(), err error) {
+	objects := slices.Clone(d.objects)
+	pages := d.catalog.pdfPages.pages
+	catalog := *d.catalog
+	bookmarks := d.catalog.outlines.bookmarks
+	restore = func() {
+		d.objects = objects
+		for i, o := range objects {
+			o.setID(i + 1)
+		}
+		*d.catalog = catalog
+		d.catalog.outlines.bookmarks = bookmarks
+		d.catalog.pdfPages.pages = pages
+	}
+
+	forms := make([]*PdfTemplate, len(pages))
+	remove := map[PdfObjectWriter]bool{}
+	for i, p := range pages {
+		if forms[i], err = p.form(fmt.Sprintf("Pg%v", i+1)); err != nil {
+			return restore, err
+		}
+		remove[p] = true
+		if p.imported == nil {
+			remove[p.content] = true
+		} else {
+			// the content of an imported page is copied into its form
+			contents := p.imported.get("Contents")
+			if ref, ok := contents.(importedRef); ok && ref.imported != nil {
+				remove[ref.imported] = true
+				contents = ref.imported.value
+			}
+			list, _ := contents.([]any)
+			for _, item := range list {
+				if ref, ok := item.(importedRef); ok && ref.imported != nil {
+					remove[ref.imported] = true
+				}
+			}
+		}
+		for _, a := range p.annots {
+			removeAnnotation(a, remove)
+		}
+	}
+	var removeBookmarks func(bookmarks []*Bookmark)
+	removeBookmarks = func(bookmarks []*Bookmark) {
+		for _, b := range bookmarks {
+			remove[b] = true
+			removeBookmarks(b.children)
+		}
+	}
+	removeBookmarks(bookmarks)
+	if d.catalog.acroForm != nil {
+		remove[d.catalog.acroForm] = true
+	}
+	d.catalog.outlines.bookmarks, d.catalog.dests, d.catalog.pageLabels = nil, nil, nil
+	d.catalog.openAction, d.catalog.acroForm = nil, nil
+	d.removeObjects(remove)
+
+	for _, form := range forms {
+		d.addObject(form)
+	}
+	resources := &PdfResources{templates: forms}
+	d.addObject(resources)
+	size := d.pageSize
+	if (cols > rows) != (size.Width > size.Height) {
+		size.Width, size.Height = size.Height, size.Width
+	}
+	cellW, cellH := size.Width/float64(cols), size.Height/float64(rows)
+	var sheets []*PdfPage
+	for start := 0; start < len(order); start += cols * rows {
+		sheet := d.newPage(size.Width, size.Height, [4]float64{})
+		sheet.parent = d.catalog.pdfPages
+		sheet.resources = resources
+		for place, index := range order[start:min(start+cols*rows, len(order))] {
+			if index >= 0 {
+				x, y := float64(place%cols)*cellW, size.Height-float64(place/cols+1)*cellH
+				sheet.placeForm(forms[index], x, y, cellW, cellH)
+			}
+		}
+		d.addObject(sheet)
+		d.addObject(sheet.content)
+		sheets = append(sheets, sheet)
+	}
+	d.catalog.pdfPages.pages = sheets
+	return restore, nil
+}
+
+// placeForm draws the form of a page scaled to fit the rectangle with its bottom left corner at x, y measured in
+// points, centred in it, with cut marks at its corners if they are on
+func (p *PdfPage) placeForm(form *PdfTemplate, x, y, w, h float64) {
+	if p.document.cutMarks {
+		x, y, w, h = x+cutMarkGap, y+cutMarkGap, w-2*cutMarkGap, h-2*cutMarkGap
+	}
+	scale := min(w/form.width, h/form.height)
+	x, y = x+(w-form.width*scale)/2, y+(h-form.height*scale)/2
+	w, h = form.width*scale, form.height*scale
+	p.content.addGraphicsf("q\r\n%v 0 0 %v %v %v cm\r\n%v Do\r\nQ\r\n", formatNumber(scale), formatNumber(scale),
+		formatNumber(x), formatNumber(y), formatName(form.name))
+	if !p.document.cutMarks {
+		return
+	}
+	var marks bytes.Buffer
+	for _, corner := range [][4]float64{{x, y, -1, -1}, {x + w, y, 1, -1}, {x, y + h, -1, 1}, {x + w, y + h, 1, 1}} {
+		// each mark runs outwards along an edge from just beyond the corner
+		cx, cy, dx, dy := corner[0], corner[1], corner[2], corner[3]
+		fmt.Fprintf(&marks, "%v m %v l\r\n", formatNumbers(cx+dx*3, cy), formatNumbers(cx+dx*cutMarkGap, cy))
+		fmt.Fprintf(&marks, "%v m %v l\r\n", formatNumbers(cx, cy+dy*3), formatNumbers(cx, cy+dy*cutMarkGap))
+	}
+	p.content.addGraphicsf("q\r\n0.25 w 0 G\r\n%vS\r\nQ\r\n", marks.String())
+}
+
+// form returns the page as a form XObject named name that shows what a viewer would, the page's crop box turned by
+// its rotation
+func (p *PdfPage) form(name string) (*PdfTemplate, error) {
+	box := [4]float64{0, 0, p.width, p.height}
+	rotate := p.rotate
+	dict := pdfDict{}
+	var data []byte
+	if p.imported == nil {
+		if p.cropBox != nil {
+			box = *p.cropBox
+		}
+		resources := p.document.resources
+		if p.resources != nil {
+			resources = p.resources
+		}
+		dict = append(dict, pdfEntry{"Resources", importedRef{o: &resources.PdfObject}})
+		data = p.content.data()
+	} else {
+		for _, key := range []pdfName{"MediaBox", "CropBox"} {
+			if v, ok := importedValue(p.imported.get(key)).([]any); ok && len(v) == 4 {
+				for i := range box {
+					box[i] = importedNumber(v[i])
+				}
+			}
+		}
+		rotate = (int(importedNumber(p.imported.get("Rotate")))%360 + 360) % 360
+		if resources := p.imported.get("Resources"); resources != nil {
+			dict = append(dict, pdfEntry{"Resources", resources})
+		}
+		var err error
+		if data, err = importedContent(p.imported.get("Contents")); err != nil {
+			return nil, err
+		}
+	}
+	matrix, w, h := formMatrix(box, rotate)
+	dict = append(pdfDict{{"BBox", []any{box[0], box[1], box[2], box[3]}}, {"Matrix", matrix}}, dict...)
+	t := &PdfTemplate{name: name, width: w, height: h, imported: &pdfStream{dict: dict, data: data}}
+	t.setDocument(p.document)
+	return t, nil
+}
+
+// formMatrix returns the matrix of a form XObject that shows the box llx lly urx ury of a page with its corner at
+// the origin, turned clockwise by rotate degrees, and the width and height it is shown at
+func formMatrix(box [4]float64, rotate int) (matrix []any, w, h float64) {
+	llx, lly := box[0], box[1]
+	w, h = box[2]-box[0], box[3]-box[1]
+	m := []float64{1, 0, 0, 1, -llx, -lly}
+	switch rotate {
+	case 90:
+		m = []float64{0, -1, 1, 0, -lly, w + llx}
+		w, h = h, w
+	case 180:
+		m = []float64{-1, 0, 0, -1, w + llx, h + lly}
+	case 270:
+		m = []float64{0, 1, -1, 0, h + lly, -llx}
+		w, h = h, w
+	}
+	for _, v := range m {
+		// adding 0 writes -0 as 0
+		matrix = append(matrix, v+0)
+	}
+	return matrix, w, h
+}
+
+// importedValue returns the value of an object copied from another file when v refers to one, or else v itself
+func importedValue(v any) any {
+	if ref, ok := v.(importedRef); ok && ref.imported != nil {
+		return ref.imported.value
+	}
+	return v
+}
+
+// importedNumber returns a number copied from another file, or 0 if v isn't a number
+func importedNumber(v any) float64 {
+	switch v := importedValue(v).(type) {
+	case int:
+		return float64(v)
+	case float64:
+		return v
+	}
+	return 0
+}
+
+// importedContent returns the decoded content of a page copied from another file, joining its content streams when
+// it has more than one
+func importedContent(contents any) ([]byte, error) {
+	contents = importedValue(contents)
+	list, ok := contents.([]any)
+	if !ok {
+		list = []any{contents}
+	}
+	var data []byte
+	for _, v := range list {
+		s, ok := importedValue(v).(*pdfStream)
+		if !ok {
+			continue
+		}
+		// the filters of a copied stream don't refer to other objects, so no file is needed to decode it
+		decoded, err := (&pdfReader{}).decode(s)
+		if err != nil {
+			return nil, err
+		}
+		data = append(append(data, decoded...), '\n')
+	}
+	return data, nil
+}
@@ -0,0 +1,87 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+)
+
+// PdfInfo holds the document metadata shown by viewers in their document properties
+type PdfInfo struct {
+	PdfObject
+	title, author, subject, keywords, creator, producer string
+	created, modified                                   time.Time
+}
+
+// info returns the Info dictionary, adding it to the document the first time metadata is set
+func (d *PdfDocument) info() *PdfInfo {
+	if d.metadata == nil {
+		now := d.now()
+		d.metadata = &PdfInfo{producer: "gopdf", created: now, modified: now}
+		d.addObject(d.metadata)
+	}
+	return d.metadata
+}
+
+// SetTitle sets the title of the document
+func (d *PdfDocument) SetTitle(title string) {
+	d.info().title = title
+}
+
+// SetAuthor sets the name of the person who created the document
+func (d *PdfDocument) SetAuthor(author string) {
+	d.info().author = author
+}
+
+// SetSubject sets the subject of the document
+func (d *PdfDocument) SetSubject(subject string) {
+	d.info().subject = subject
+}
+
+// SetKeywords sets the keywords associated with the document
+func (d *PdfDocument) SetKeywords(keywords string) {
+	d.info().keywords = keywords
+}
+
+// SetCreator sets the name of the application that created the content of the document
+func (d *PdfDocument) SetCreator(creator string) {
+	d.info().creator = creator
+}
+
+// formatDate formats t as a PDF date string, D:YYYYMMDDHHmmSS followed by the offset from UTC
+func formatDate(t time.Time) string {
+	date := t.Format("D:20060102150405")
+	_, offset := t.Zone()
+	if offset == 0 {
+		return date + "Z"
+	}
+	sign := '+'
+	if offset < 0 {
+		sign = '-'
+		offset = -offset
+	}
+	return fmt.Sprintf("%v%c%02d'%02d'", date, sign, offset/3600, offset/60%60)
+}
+
+func (i *PdfInfo) bytes() []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%v 0 obj\r\n", i.id)
+	fmt.Fprintf(&buf, "<<\r\n")
+	for _, entry := range []struct{ key, value string }{
+		{"Title", i.title},
+		{"Author", i.author},
+		{"Subject", i.subject},
+		{"Keywords", i.keywords},
+		{"Creator", i.creator},
+		{"Producer", i.producer},
+	} {
+		if entry.value != "" {
+			fmt.Fprintf(&buf, "/%v %v\r\n", entry.key, formatTextString(entry.value))
+		}
+	}
+	fmt.Fprintf(&buf, "/CreationDate (%v)\r\n", formatDate(i.created))
+	fmt.Fprintf(&buf, "/ModDate (%v)\r\n", formatDate(i.modified))
+	fmt.Fprintf(&buf, ">>\r\n")
+	fmt.Fprintf(&buf, "endobj\r\n")
+	return buf.Bytes()
+}
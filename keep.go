@@ -0,0 +1,65 @@
+package gopdf
+
+// measurement records the text output while KeepTogether measures a block, with positions in points
+type measurement struct {
+	lowest     float64 // lowest baseline of the text
+	pageBreaks int     // times the block moved to the next column or page
+}
+
+// KeepTogether outputs the content drawn by f on one page, so that a heading isn't left at the bottom of a page with
+// the paragraph after it on the next. f is first run on a scratch copy of the page to measure it. When the text it
+// outputs would go below the bottom margin, a new page is added and f is run on that instead, and text output on p
+// afterwards also goes to the new page. Content taller than a page is output where it is with the usual page breaks.
+// Inside columns the content is kept in one column instead. Links, notes and form fields added by f are left out
+// while it is measured, but f should not add anything else to the document, such as bookmarks, as it would be added
+// twice.
+func (p *PdfPage) KeepTogether(f func(p *PdfPage)) {
+	p = p.flow()
+	if p.measure != nil {
+		f(p)
+		return
+	}
+	top := p.height - p.topMargin - p.fontSize
+	if p.columns != nil {
+		top = p.columns.top
+	}
+	m := &measurement{lowest: top}
+	scratch := p.scratch(m)
+	scratch.y = top
+	f(scratch)
+	// content that doesn't fit on a page by itself isn't moved
+	fitsPage := m.pageBreaks == 0 && m.lowest >= p.bottomMargin
+	if fitsPage && p.y-(top-m.lowest) < p.bottomMargin {
+		if p.columns != nil {
+			p.nextColumn()
+		} else {
+			p.next = p.continuation(p.fontName(), p.fontSize)
+		}
+		p = p.flow()
+	}
+	f(p)
+}
+
+// scratch returns a copy of the page for measuring content with m, which outputs to a content stream that is thrown
+// away
+func (p *PdfPage) scratch(m *measurement) *PdfPage {
+	s := *p
+	s.content = &PdfPageContent{page: &s}
+	s.content.document = p.document
+	s.annots, s.used = nil, nil
+	s.savedStates = append([]pageState(nil), p.savedStates...)
+	s.next = nil
+	if p.columns != nil {
+		c := *p.columns
+		s.columns = &c
+	}
+	s.measure = m
+	return &s
+}
+
+// measureBaseline records the current baseline when the page is being measured by KeepTogether
+func (p *PdfPage) measureBaseline() {
+	if p.measure != nil {
+		p.measure.lowest = min(p.measure.lowest, p.y)
+	}
+}
@@ -0,0 +1,140 @@
+package gopdf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ListStyle selects the markers of the items in a list
+type ListStyle int
+
+// List styles. Numbered items are followed by a full stop, as in 1. a. A. i. and I.
+const (
+	ListBullet ListStyle = iota
+	ListDecimal
+	ListLowerAlpha
+	ListUpperAlpha
+	ListLowerRoman
+	ListUpperRoman
+)
+
+// ListOptions controls how WriteList draws a list. The zero value draws a bulleted list with each level indented by
+// 18 points.
+type ListOptions struct {
+	Styles     []ListStyle // style of each level of nesting, the last repeated for deeper levels, or nil for bullets
+	Bullet     string      // text of the bullets, or empty for •
+	BulletFont string      // name of a font added to the document for the bullets, or empty for the current font
+	Indent     float64     // indent of each level in the document's unit, which its markers sit in, or 0 for 18 points
+	Start      int         // number of the first item at each level of a numbered list, or 0 for 1
+}
+
+// WriteList outputs a list starting at the cursor, one item for each string. Items starting with tabs are nested
+// that many levels deep in the item before them. The marker of an item sits in the indent of its level and its text
+// starts after the indent, wrapped to fit before the right margin with the wrapped lines aligned under the start of
+// the text. When an item doesn't fit above the bottom margin the list continues on a new page, so that items are
+// only split across pages when they are too long for a page by themselves. WriteList returns the page the list ends
+// on, with the cursor at the left margin below the list.
+func (p *PdfPage) WriteList(items []string, opts ListOptions) (*PdfPage, error) {
+	var bulletFont *PdfFont
+	if opts.BulletFont != "" {
+		var err error
+		if bulletFont, err = p.document.resources.font(opts.BulletFont); err != nil {
+			return p, err
+		}
+	}
+	indent := 18.0
+	if opts.Indent > 0 {
+		indent = p.pt(opts.Indent)
+	}
+	start := max(opts.Start, 1)
+	font := p.fontName()
+
+	left := p.x
+	var counts []int // the number of the current item at each level
+	for _, item := range items {
+		level := len(item) - len(strings.TrimLeft(item, "\t"))
+		// an item can only be nested one level deeper than the one before, and starts a new list when it is
+		level = min(level, len(counts))
+		if level == len(counts) {
+			counts = append(counts, 0)
+		}
+		counts = counts[:level+1]
+		counts[level]++
+		style := ListBullet
+		if n := len(opts.Styles); n > 0 {
+			style = opts.Styles[min(level, n-1)]
+		}
+
+		markerX := left + float64(level)*indent
+		textX := markerX + indent
+		width := p.lineRight() - textX
+		lines := p.wrapText(strings.TrimLeft(item, "\t"), width, width)
+		// keep the item on one page unless it doesn't fit on a page by itself
+		height := float64(len(lines)-1) * p.lineAdvance()
+		top := p.height - p.topMargin - p.fontSize
+		if p.y-height < p.bottomMargin && p.y < top && top-height >= p.bottomMargin {
+			p = p.continuation(font, p.fontSize)
+		}
+
+		if style == ListBullet {
+			bullet := opts.Bullet
+			if bullet == "" {
+				bullet = "•"
+			}
+			p.outputRichPiece(TextStyle{Font: opts.BulletFont}, bulletFont, p.fontSize, bullet, markerX)
+		} else {
+			p.outputTextAt(listMarker(style, start+counts[level]-1), markerX)
+		}
+		for i, line := range lines {
+			if i > 0 {
+				p.y -= p.lineAdvance()
+				if p.y < p.bottomMargin {
+					p = p.continuation(font, p.fontSize)
+				}
+			}
+			p.outputTextAt(line, textX)
+		}
+		p.y -= p.lineAdvance()
+	}
+	p.x = p.leftMargin
+	return p, nil
+}
+
+// listMarker returns the marker of the nth item of a numbered list
+func listMarker(style ListStyle, n int) string {
+	switch style {
+	case ListLowerAlpha:
+		return alphaNumber(n) + "."
+	case ListUpperAlpha:
+		return strings.ToUpper(alphaNumber(n)) + "."
+	case ListLowerRoman:
+		return romanNumber(n) + "."
+	case ListUpperRoman:
+		return strings.ToUpper(romanNumber(n)) + "."
+	}
+	return fmt.Sprintf("%v.", n)
+}
+
+// alphaNumber returns n as a, b, ... z, aa, ab and so on
+func alphaNumber(n int) string {
+	var s string
+	for ; n > 0; n = (n - 1) / 26 {
+		s = string(rune('a'+(n-1)%26)) + s
+	}
+	return s
+}
+
+// romanNumber returns n in lower case roman numerals
+func romanNumber(n int) string {
+	var sb strings.Builder
+	for _, numeral := range []struct {
+		value  int
+		digits string
+	}{{1000, "m"}, {900, "cm"}, {500, "d"}, {400, "cd"}, {100, "c"}, {90, "xc"}, {50, "l"}, {40, "xl"}, {10, "x"},
+		{9, "ix"}, {5, "v"}, {4, "iv"}, {1, "i"}} {
+		for ; n >= numeral.value; n -= numeral.value {
+			sb.WriteString(numeral.digits)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,134 @@
+package gopdf
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"strings"
+)
+
+// AddStencil adds a stencil made from img to the document under the given name. A stencil has no colours of its
+// own: DrawStencil paints its dark, opaque pixels in a colour and leaves the rest of it transparent, which suits
+// icons and scanned signatures. Stencils share their names with images.
+func (d *PdfDocument) AddStencil(name string, img image.Image) (*PdfImage, error) {
+	if err := d.checkImageName(name); err != nil {
+		return nil, err
+	}
+	bounds := img.Bounds()
+	i := &PdfImage{name: name, width: bounds.Dx(), height: bounds.Dy(), bits: 1, stencil: true}
+	// a 0 bit is painted
+	values := make([]byte, 0, i.width*i.height)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r, g, b, a := img.At(x, y).RGBA()
+			luma := (299*r + 587*g + 114*b) / 1000
+			if a >= 0x8000 && 2*luma < a {
+				values = append(values, 0)
+			} else {
+				values = append(values, 1)
+			}
+		}
+	}
+	i.filter = "/FlateDecode"
+	i.data = deflate(packBits(values, i.width, 1, func(v byte) byte { return v }))
+	d.addImage(i)
+	return i, nil
+}
+
+// DrawStencil paints a named stencil in colour c with its corner at x, y, scaled to w by h as for
+// DrawImageScaled
+func (p *PdfPage) DrawStencil(name string, x, y, w, h float64, c Color) error {
+	i, err := p.document.resources.image(name)
+	if err != nil {
+		return err
+	}
+	if !i.stencil {
+		return fmt.Errorf("%w: %v is not a stencil", ErrInvalidImageMask, name)
+	}
+	w, h = i.scaledSize(p.pt(w), p.pt(h))
+	x, y = p.columnX(p.pt(x)), p.ptY(y, h)
+	i.drawnAt(w, h)
+	p.use(i)
+	p.use(c)
+	p.content.addGraphicsf("q\r\n%v\r\n%v\r\n%v Do\r\nQ\r\n", c.operator(false), i.placement(x, y, w, h), formatName(name))
+	return nil
+}
+
+// SetTransparentColor makes the pixels of the image that are exactly colour c transparent. The colour is an
+// RGBColor or GrayColor, or a CMYKColor for CMYK images. Lossy JPEG images rarely have many pixels of exactly one
+// colour, so this is mostly useful for images with a palette or few colours. GIFs with a transparent colour are
+// masked this way when they are added.
+func (pi *PdfImage) SetTransparentColor(c Color) error {
+	var key []int
+	switch {
+	case pi.stencil:
+		return fmt.Errorf("%w: a stencil has no colours to make transparent", ErrInvalidImageMask)
+	case pi.palette != nil:
+		target := colorKeyRGB(c)
+		if strings.Contains(pi.colorSpace, "/DeviceGray") && target != nil {
+			if target[0] != target[1] || target[1] != target[2] {
+				break
+			}
+			target = target[:1]
+		}
+		for at := 0; target != nil && at+len(target) <= len(pi.palette); at += len(target) {
+			if colorKeyMatches(pi.palette[at:at+len(target)], target) {
+				key = []int{at / len(target)}
+				break
+			}
+		}
+	case pi.colorSpace == "/DeviceRGB":
+		key = colorKeyRGB(c)
+	case pi.colorSpace == "/DeviceGray":
+		if rgb := colorKeyRGB(c); rgb != nil && rgb[0] == rgb[1] && rgb[1] == rgb[2] {
+			key = rgb[:1]
+			if pi.bits == 1 {
+				key = nil
+				if rgb[0] == 0 || rgb[0] == 255 {
+					key = []int{rgb[0] / 255}
+				}
+			}
+		}
+	case pi.colorSpace == "/DeviceCMYK":
+		if cmyk, ok := c.(CMYKColor); ok {
+			for _, v := range []float64{cmyk.C, cmyk.M, cmyk.Y, cmyk.K} {
+				v = math.Round(v * 255)
+				if pi.decode != "" {
+					v = 255 - v
+				}
+				key = append(key, int(v))
+			}
+		}
+	}
+	if key == nil {
+		return fmt.Errorf("%w: %v can't be made transparent in a %v image", ErrInvalidImageMask, c, pi.colorSpace)
+	}
+	ranges := make([]string, len(key))
+	for i, v := range key {
+		ranges[i] = fmt.Sprintf("%v %v", v, v)
+	}
+	pi.mask = "[ " + strings.Join(ranges, " ") + " ]"
+	return nil
+}
+
+// colorKeyRGB returns the 8 bit red, green and blue components of an RGBColor or GrayColor, or nil for other colours
+func colorKeyRGB(c Color) []int {
+	switch c := c.(type) {
+	case RGBColor:
+		return []int{c.R, c.G, c.B}
+	case GrayColor:
+		v := int(math.Round(float64(c) * 255))
+		return []int{v, v, v}
+	}
+	return nil
+}
+
+// colorKeyMatches reports whether a palette entry is the colour of a key
+func colorKeyMatches(entry []byte, key []int) bool {
+	for i, v := range key {
+		if int(entry[i]) != v {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,246 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+)
+
+// importedObject is an object copied from another PDF file, with its references renumbered for this document
+type importedObject struct {
+	PdfObject
+	value any
+}
+
+func (o importedObject) bytes() []byte {
+	return valueObject(o.id, o.value)
+}
+
+// valueObject serializes the object with the given id holding v, a value in the form read from a PDF file. The
+// Length of a stream is written for its data as it is.
+func valueObject(id int, v any) []byte {
+	if s, ok := v.(*pdfStream); ok {
+		var entries bytes.Buffer
+		for _, e := range s.dict {
+			if e.key != "Length" {
+				fmt.Fprintf(&entries, "%v %v\r\n", formatName(string(e.key)), formatValue(e.value))
+			}
+		}
+		return streamObject(id, entries.String(), s.data)
+	}
+	return []byte(fmt.Sprintf("%v 0 obj\r\n%v\r\nendobj\r\n", id, formatValue(v)))
+}
+
+// importedRef is a reference in a copied value to an object of the document. It is written with the id the object
+// has when the document is written, since deleting pages renumbers the objects, or as null if it has been deleted.
+type importedRef struct {
+	o        *PdfObject
+	imported *importedObject // the object when it was copied from the file, or nil for a page
+}
+
+// pdfImporter copies objects from a PDF file into a document
+type pdfImporter struct {
+	r       *pdfReader
+	d       *PdfDocument
+	ids     map[int]importedRef // numbers of the objects in the file to the objects copied into the document
+	pending []pendingObject
+}
+
+// pendingObject is an object that has been added to the document but not yet copied from the file
+type pendingObject struct {
+	num int
+	o   *importedObject
+}
+
+func newPDFImporter(r *pdfReader, d *PdfDocument) *pdfImporter {
+	return &pdfImporter{r: r, d: d, ids: map[int]importedRef{}}
+}
+
+// copyValue returns a value read from the file with its references changed to the objects copied into the
+// document. Objects referred to are added to the document, and copied by copyPending.
+func (im *pdfImporter) copyValue(v any) any {
+	switch v := v.(type) {
+	case pdfRef:
+		ref, ok := im.ids[v.num]
+		if !ok {
+			o := &importedObject{}
+			im.d.addObject(o)
+			ref = importedRef{&o.PdfObject, o}
+			im.ids[v.num] = ref
+			im.pending = append(im.pending, pendingObject{v.num, o})
+		}
+		return ref
+	case []any:
+		array := make([]any, len(v))
+		for i, item := range v {
+			array[i] = im.copyValue(item)
+		}
+		return array
+	case pdfDict:
+		return im.copyDict(v, "")
+	case *pdfStream:
+		// the length is written again for the data as it is
+		return &pdfStream{dict: im.copyDict(v.dict, "Length"), data: v.data}
+	}
+	return v
+}
+
+// copyDict copies a dictionary as for copyValue, leaving out the entry skip
+func (im *pdfImporter) copyDict(dict pdfDict, skip pdfName) pdfDict {
+	copied := make(pdfDict, 0, len(dict))
+	for _, e := range dict {
+		if e.key != skip {
+			copied = append(copied, pdfEntry{e.key, im.copyValue(e.value)})
+		}
+	}
+	return copied
+}
+
+// copyPending copies the objects that have been referred to but not yet copied, and those they refer to in turn
+func (im *pdfImporter) copyPending() error {
+	for len(im.pending) > 0 {
+		next := im.pending[0]
+		im.pending = im.pending[1:]
+		v, err := im.r.object(next.num)
+		if err != nil {
+			return err
+		}
+		next.o.value = im.copyValue(v)
+	}
+	return nil
+}
+
+// sourcePage is a page of a PDF file being read, with the attributes it inherits from the page tree
+type sourcePage struct {
+	num  int
+	dict pdfDict
+}
+
+// pages returns the pages of the file in order
+func (pr *pdfReader) pages() ([]sourcePage, error) {
+	root, _ := pr.resolve(pr.trailer.get("Root")).(pdfDict)
+	tree, ok := root.get("Pages").(pdfRef)
+	if !ok {
+		return nil, fmt.Errorf("%w: no page tree", ErrInvalidPDF)
+	}
+	var pages []sourcePage
+	seen := map[int]bool{}
+	var walk func(ref pdfRef, inherited pdfDict)
+	walk = func(ref pdfRef, inherited pdfDict) {
+		node, _ := pr.resolve(ref).(pdfDict)
+		if node == nil || seen[ref.num] {
+			return
+		}
+		seen[ref.num] = true
+		// resources, the media and crop boxes and the rotation are inherited by the pages below a node
+		for _, key := range []pdfName{"Resources", "MediaBox", "CropBox", "Rotate"} {
+			if v := node.get(key); v != nil {
+				inherited = append(inherited[:len(inherited):len(inherited)], pdfEntry{key, v})
+			}
+		}
+		kids, _ := pr.resolve(node.get("Kids")).([]any)
+		if node.get("Type") == pdfName("Page") || kids == nil {
+			page := pdfDict{}
+			for _, e := range node {
+				if e.key != "Type" && e.key != "Parent" {
+					page = append(page, e)
+				}
+			}
+			for _, e := range inherited {
+				if page.get(e.key) == nil {
+					page = append(page, e)
+				}
+			}
+			pages = append(pages, sourcePage{ref.num, page})
+			return
+		}
+		for _, kid := range kids {
+			if kid, ok := kid.(pdfRef); ok {
+				walk(kid, inherited)
+			}
+		}
+	}
+	walk(tree, nil)
+	return pages, nil
+}
+
+// AppendPDF adds the pages of the PDF file read from r, which is size bytes long, to the end of the document. The
+// pages are copied with their content, resources and annotations, but they can't be drawn on, and the file's
+// bookmarks and form are not copied. Files with cross-reference and object streams can be read, but encrypted files
+// return ErrEncryptedPDF.
+func (d *PdfDocument) AppendPDF(r io.ReaderAt, size int64) error {
+	pr, err := newPDFReader(r, size)
+	if err != nil {
+		return err
+	}
+	sources, err := pr.pages()
+	if err != nil {
+		return err
+	}
+	im := newPDFImporter(pr, d)
+	// the pages are numbered first so that links between them refer to the copies
+	pages := make([]*PdfPage, len(sources))
+	for i, source := range sources {
+		size := d.pageSize.oriented(d.orientation)
+		p := &PdfPage{parent: d.catalog.pdfPages, width: size.Width, height: size.Height}
+		if box, ok := pr.resolve(source.dict.get("MediaBox")).([]any); ok && len(box) == 4 {
+			p.width = boxNumber(pr, box[2]) - boxNumber(pr, box[0])
+			p.height = boxNumber(pr, box[3]) - boxNumber(pr, box[1])
+		}
+		// an imported page's content is written as it was in the file, so nothing can be added to it
+		p.content = &PdfPageContent{finished: true, page: p}
+		p.content.document = d
+		d.addObject(p)
+		im.ids[source.num] = importedRef{o: &p.PdfObject}
+		pages[i] = p
+	}
+	for i, source := range sources {
+		pages[i].imported = im.copyDict(source.dict, "")
+	}
+	if err := im.copyPending(); err != nil {
+		return err
+	}
+	d.catalog.pdfPages.pages = append(d.catalog.pdfPages.pages, pages...)
+	return nil
+}
+
+// AppendPDFFile adds the pages of a PDF file to the end of the document, as for AppendPDF
+func (d *PdfDocument) AppendPDFFile(filename string) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	return d.AppendPDF(f, info.Size())
+}
+
+// boxNumber returns a coordinate of a rectangle read from a PDF file
+func boxNumber(pr *pdfReader, v any) float64 {
+	switch v := pr.resolve(v).(type) {
+	case int:
+		return float64(v)
+	case float64:
+		return v
+	}
+	return 0
+}
+
+// importedBytes returns the page object of a page copied from another file by AppendPDF
+func (p PdfPage) importedBytes() []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%v 0 obj\r\n", p.id)
+	fmt.Fprintf(&buf, "<<\r\n")
+	fmt.Fprintf(&buf, "/Type /Page\r\n")
+	fmt.Fprintf(&buf, "/Parent %v\r\n", p.parent.objectRef())
+	for _, e := range p.imported {
+		fmt.Fprintf(&buf, "%v %v\r\n", formatName(string(e.key)), formatValue(e.value))
+	}
+	fmt.Fprintf(&buf, ">>\r\n")
+	fmt.Fprintf(&buf, "endobj\r\n")
+	return buf.Bytes()
+}
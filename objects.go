@@ -0,0 +1,266 @@
+package gopdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+)
+
+// PdfObjectWriter is an interface that all objects implement to allow us to treat the PDF as a list of objects
+// and easily write it out.
+type PdfObjectWriter interface {
+	setID(id int)
+	setDocument(*PdfDocument)
+	bytes() []byte
+}
+
+// PdfObject is the base object that has an id and a reference to the containing document.
+// It implements PdfObjectWriter
+type PdfObject struct {
+	id       int
+	document *PdfDocument
+}
+
+func (o *PdfObject) setID(id int) {
+	o.id = id
+}
+
+func (o *PdfObject) setDocument(d *PdfDocument) {
+	o.document = d
+}
+
+func (o PdfObject) objectRef() string {
+	return fmt.Sprintf("%v 0 R", o.id)
+}
+
+// PdfPages represents the list of pages
+type PdfPages struct {
+	PdfObject
+	pages []*PdfPage
+}
+
+// mediaBox returns the default page size, which pages of a different size override
+func (p PdfPages) mediaBox() string {
+	return p.document.pageSize.oriented(p.document.orientation).mediaBox()
+}
+
+func (p PdfPages) bytes() []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%v 0 obj\r\n", p.id)
+	fmt.Fprintf(&buf, "<<\r\n")
+	fmt.Fprintf(&buf, "/Type /Pages\r\n")
+	fmt.Fprintf(&buf, "/MediaBox %v\r\n", p.mediaBox())
+	fmt.Fprintf(&buf, "/Count %v\r\n", len(p.pages))
+	fmt.Fprintf(&buf, "/Kids [ ")
+	for _, page := range p.pages {
+		fmt.Fprintf(&buf, page.objectRef()+" ")
+	}
+	fmt.Fprintf(&buf, "]\r\n")
+	fmt.Fprintf(&buf, ">>\r\n")
+	fmt.Fprintf(&buf, "endobj\r\n")
+	return buf.Bytes()
+}
+
+// PdfOutlines is the root of the bookmark tree
+type PdfOutlines struct {
+	PdfObject
+	bookmarks []*Bookmark
+}
+
+func (o PdfOutlines) bytes() []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%v 0 obj\r\n", o.id)
+	fmt.Fprintf(&buf, "<<\r\n")
+	fmt.Fprintf(&buf, "/Type /Outlines\r\n")
+	if len(o.bookmarks) > 0 {
+		fmt.Fprintf(&buf, "/First %v\r\n", o.bookmarks[0].objectRef())
+		fmt.Fprintf(&buf, "/Last %v\r\n", o.bookmarks[len(o.bookmarks)-1].objectRef())
+	}
+	fmt.Fprintf(&buf, "/Count %v\r\n", visibleBookmarks(o.bookmarks))
+	fmt.Fprintf(&buf, ">>\r\n")
+	fmt.Fprintf(&buf, "endobj\r\n")
+	return buf.Bytes()
+}
+
+// PdfCatalog ...
+type PdfCatalog struct {
+	PdfObject
+	outlines     *PdfOutlines
+	pdfPages     *PdfPages
+	acroForm     *PdfAcroForm
+	attachments  []*PdfFileSpec
+	metadata     *PdfMetadata
+	outputIntent *PdfOutputIntent
+	dests        []*namedDestination
+	pageLabels   []*pageLabelRange
+
+	pageMode          PageMode
+	openAction        *openAction
+	viewerPreferences *ViewerPreferences
+	entries           pdfDict // added by AddCatalogEntry
+}
+
+func (c PdfCatalog) bytes() []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%v 0 obj\r\n", c.id)
+	fmt.Fprintf(&buf, "<<\r\n")
+	// entries added by AddCatalogEntry replace those of the same key
+	entry := func(key pdfName, format string, args ...any) {
+		if !c.entries.has(key) {
+			fmt.Fprintf(&buf, "%v "+format+"\r\n", append([]any{formatName(string(key))}, args...)...)
+		}
+	}
+	fmt.Fprintf(&buf, "/Type /Catalog \r\n")
+	entry("Outlines", "%v", c.outlines.objectRef())
+	if c.pageMode != PageModeAuto {
+		entry("PageMode", "/%v", c.pageMode)
+	} else if len(c.outlines.bookmarks) > 0 {
+		entry("PageMode", "/UseOutlines")
+	}
+	if c.openAction != nil {
+		entry("OpenAction", "%v", zoomDestination(c.openAction.page, c.openAction.zoom))
+	}
+	if c.viewerPreferences != nil {
+		entry("ViewerPreferences", "%s", c.viewerPreferences.bytes())
+	}
+	fmt.Fprintf(&buf, "/Pages %v\r\n", c.pdfPages.objectRef())
+	if len(c.pageLabels) > 0 {
+		entry("PageLabels", "%v", pageLabelsTree(c.pageLabels))
+	}
+	if c.acroForm != nil {
+		entry("AcroForm", "%v", c.acroForm.objectRef())
+	}
+	if c.outputIntent != nil {
+		entry("OutputIntents", "[ %v ]", c.outputIntent.objectRef())
+	}
+	if c.metadata != nil {
+		entry("Metadata", "%v", c.metadata.objectRef())
+	}
+	if len(c.attachments) > 0 || len(c.dests) > 0 {
+		var names bytes.Buffer
+		if len(c.dests) > 0 {
+			fmt.Fprintf(&names, "/Dests %v ", destsTree(c.dests))
+		}
+		if len(c.attachments) > 0 {
+			fmt.Fprintf(&names, "/EmbeddedFiles %v ", embeddedFilesTree(c.attachments))
+		}
+		entry("Names", "<< %v>>", names.String())
+	}
+	for _, e := range c.entries {
+		fmt.Fprintf(&buf, "%v %v\r\n", formatName(string(e.key)), formatValue(e.value))
+	}
+	fmt.Fprintf(&buf, ">>\r\n")
+	fmt.Fprintf(&buf, "endobj\r\n")
+	return buf.Bytes()
+}
+
+// PdfResources represents the images and fonts for the document
+type PdfResources struct {
+	PdfObject
+	fonts        []*PdfFont
+	images       []*PdfImage
+	imagesByName map[string]*PdfImage // including the aliases of images added more than once
+	templates    []*PdfTemplate
+	watermark    *PdfWatermark
+	extGStates   []*PdfExtGState
+	patterns     []*PdfPattern
+	spotColors   []*PdfSpotColor
+	iccColors    bool
+}
+
+func (r PdfResources) bytes() []byte {
+	return []byte(fmt.Sprintf("%v 0 obj\r\n%vendobj\r\n", r.id, r.dictionary()))
+}
+
+// dictionary returns the resource dictionary listing the resources
+func (r PdfResources) dictionary() string {
+	var buf bytes.Buffer
+	procset := "[ /PDF "
+	if len(r.fonts) > 0 {
+		procset += "/Text "
+	}
+	if len(r.images) > 0 {
+		procset += "/ImageB "
+	}
+	procset += "]"
+
+	fmt.Fprintf(&buf, "<<\r\n")
+	fmt.Fprintf(&buf, "/Procset %v\r\n", procset)
+
+	if len(r.fonts) > 0 {
+		fmt.Fprintf(&buf, "/Font << ")
+		for _, font := range r.fonts {
+			fmt.Fprintf(&buf, "%v %v ", formatName(font.name), font.objectRef())
+		}
+		fmt.Fprintf(&buf, ">>\r\n")
+	}
+
+	if len(r.images) > 0 || len(r.templates) > 0 || r.watermark != nil {
+		fmt.Fprintf(&buf, "/XObject << ")
+		for _, image := range r.images {
+			fmt.Fprintf(&buf, "%v %v ", formatName(image.name), image.objectRef())
+			for _, alias := range image.aliases {
+				fmt.Fprintf(&buf, "%v %v ", formatName(alias), image.objectRef())
+			}
+		}
+		for _, template := range r.templates {
+			fmt.Fprintf(&buf, "%v %v ", formatName(template.name), template.objectRef())
+		}
+		if r.watermark != nil {
+			fmt.Fprintf(&buf, "%v %v ", formatName(watermarkName), r.watermark.objectRef())
+		}
+		fmt.Fprintf(&buf, ">>\r\n")
+	}
+
+	if len(r.spotColors) > 0 || r.defaultColorSpace() != "" {
+		fmt.Fprintf(&buf, "/ColorSpace << %v", r.defaultColorSpace())
+		for _, s := range r.spotColors {
+			fmt.Fprintf(&buf, "%v %v ", formatName(s.resource), s.objectRef())
+		}
+		fmt.Fprintf(&buf, ">>\r\n")
+	}
+
+	if len(r.patterns) > 0 {
+		fmt.Fprintf(&buf, "/Pattern << ")
+		for _, pattern := range r.patterns {
+			fmt.Fprintf(&buf, "%v %v ", formatName(pattern.name), pattern.objectRef())
+		}
+		fmt.Fprintf(&buf, ">>\r\n")
+	}
+
+	if len(r.extGStates) > 0 {
+		fmt.Fprintf(&buf, "/ExtGState << ")
+		for _, gs := range r.extGStates {
+			fmt.Fprintf(&buf, "%v %v ", formatName(gs.name), gs.objectRef())
+		}
+		fmt.Fprintf(&buf, ">>\r\n")
+	}
+
+	fmt.Fprintf(&buf, ">>\r\n")
+	return buf.String()
+}
+
+// streamObject returns a complete stream object. entries holds any dictionary entries other than /Length, each
+// terminated by an end of line, and /Length is added for data.
+func streamObject(id int, entries string, data []byte) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%v 0 obj\r\n", id)
+	fmt.Fprintf(&buf, "<<\r\n")
+	fmt.Fprint(&buf, entries)
+	fmt.Fprintf(&buf, "/Length %v\r\n", len(data))
+	fmt.Fprintf(&buf, ">>\r\n")
+	fmt.Fprintf(&buf, "stream\r\n")
+	buf.Write(data)
+	fmt.Fprintf(&buf, "\r\nendstream\r\n")
+	fmt.Fprintf(&buf, "endobj\r\n")
+	return buf.Bytes()
+}
+
+// deflate compresses data for use with the FlateDecode filter
+func deflate(data []byte) []byte {
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	w.Write(data)
+	w.Close()
+	return buf.Bytes()
+}
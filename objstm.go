@@ -0,0 +1,73 @@
+package gopdf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+)
+
+// SetObjectStreams turns compact output on or off. When it is on, objects other than streams are packed together
+// in a compressed object stream and the cross-reference table is written as a compressed stream too, which makes
+// large documents noticeably smaller but needs a PDF 1.5 viewer.
+func (d *PdfDocument) SetObjectStreams(enabled bool) {
+	d.objectStreams = enabled
+}
+
+// writeObjectStreams writes the objects of the document after the header, packing those that aren't streams into
+// an object stream, followed by a cross-reference stream in place of the xref table and trailer. Objects already
+// written by FinishPage are only listed.
+func (d *PdfDocument) writeObjectStreams(cw *countingWriter, written []int64) {
+	objStmID := len(d.objects) + 1
+	xrefID := len(d.objects) + 2
+
+	// each entry is the type, then the offset or object stream number, then the index within the object stream
+	entries := make([][3]int64, xrefID+1)
+	entries[0] = [3]int64{0, 0, 65535}
+
+	var header, body bytes.Buffer
+	packed := 0
+	for i, obj := range d.objects {
+		id := i + 1
+		if cw.cancelled() {
+			return
+		}
+		if i < len(written) && written[i] != 0 {
+			entries[id] = [3]int64{1, written[i], 0}
+		} else if data := obj.bytes(); !bytes.HasSuffix(data, []byte("endstream\r\nendobj\r\n")) && obj != d.encryption {
+			// streams, and the encryption dictionary which is needed to decrypt the object stream, stay outside
+			prefix := strconv.Itoa(id) + " 0 obj\r\n"
+			fmt.Fprintf(&header, "%v %v ", id, body.Len())
+			body.Write(bytes.TrimSuffix(bytes.TrimPrefix(data, []byte(prefix)), []byte("endobj\r\n")))
+			entries[id] = [3]int64{2, int64(objStmID), int64(packed)}
+			packed++
+		} else {
+			entries[id] = [3]int64{1, d.writeObject(cw, id, data), 0}
+		}
+		d.reportProgress(id, len(d.objects))
+	}
+
+	// strings inside an object stream are encrypted along with the rest of the stream
+	objStm := fmt.Sprintf("/Type /ObjStm\r\n/N %v\r\n/First %v\r\n/Filter /FlateDecode\r\n", packed, header.Len())
+	data := streamObject(objStmID, objStm, deflate(append(header.Bytes(), body.Bytes()...)))
+	if d.encryption != nil {
+		data = d.encryption.encryptObject(objStmID, data)
+	}
+	entries[objStmID] = [3]int64{1, cw.n, 0}
+	cw.Write(data)
+
+	// the cross-reference stream is never encrypted
+	entries[xrefID] = [3]int64{1, cw.n, 0}
+	var xref bytes.Buffer
+	for _, e := range entries {
+		xref.WriteByte(byte(e[0]))
+		binary.Write(&xref, binary.BigEndian, uint32(e[1]))
+		binary.Write(&xref, binary.BigEndian, uint16(e[2]))
+	}
+	startxref := cw.n
+	dict := fmt.Sprintf("/Type /XRef\r\n/Size %v\r\n/W [ 1 4 2 ]\r\n%v/Filter /FlateDecode\r\n", len(entries), d.trailerEntries(cw))
+	cw.Write(streamObject(xrefID, dict, deflate(xref.Bytes())))
+	fmt.Fprintf(cw, "startxref\r\n")
+	fmt.Fprintf(cw, "%v\r\n", startxref)
+	fmt.Fprintf(cw, "%%%%EOF\r\n")
+}
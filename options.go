@@ -0,0 +1,56 @@
+package gopdf
+
+// Option configures a document created with NewPdfDocument. Options set defaults for every page, not just the first.
+type Option func(d *PdfDocument)
+
+// WithPageSize sets the default page size
+func WithPageSize(size PageSize) Option {
+	return func(d *PdfDocument) {
+		d.SetPageSize(size)
+	}
+}
+
+// WithMargins sets the default margins, as for SetMargins
+func WithMargins(left, top, right, bottom float64) Option {
+	return func(d *PdfDocument) {
+		d.SetMargins(left, top, right, bottom)
+	}
+}
+
+// WithoutInitialPage creates the document with no pages, so that the first page can be added with AddPageWithSize
+func WithoutInitialPage() Option {
+	return func(d *PdfDocument) {
+		d.noInitialPage = true
+	}
+}
+
+// WithCompression controls whether page content is compressed, as for SetCompression
+func WithCompression(compress bool) Option {
+	return func(d *PdfDocument) {
+		d.SetCompression(compress)
+	}
+}
+
+// WithPDFVersion sets the PDF version written in the header, as for SetPDFVersion. An unsupported version is
+// returned by Err and when the document is written.
+func WithPDFVersion(major, minor int) Option {
+	return func(d *PdfDocument) {
+		if err := d.SetPDFVersion(major, minor); err != nil {
+			d.setErr(err)
+		}
+	}
+}
+
+// WithDefaultFont adds one of the 14 core fonts under the name F1 and makes it the font of every page, at the given
+// size
+func WithDefaultFont(font int, size float64) Option {
+	return func(d *PdfDocument) {
+		f, err := d.AddFont("F1", font)
+		if err != nil {
+			d.setErr(err)
+			return
+		}
+		d.defaultFont = f
+		d.defaultFontSize = size
+	}
+}
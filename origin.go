@@ -0,0 +1,73 @@
+package gopdf
+
+import "fmt"
+
+// Origin selects the corner of the page that positions are measured from
+type Origin int
+
+// Coordinate origins. With BottomLeft y increases up the page, as in PDF itself, and positions are those of the
+// bottom left corner of what is drawn. With TopLeft y increases down the page and positions are those of the top
+// left corner.
+const (
+	BottomLeft Origin = iota
+	TopLeft
+)
+
+// SetCoordinateOrigin sets the corner of the page that positions given to and returned by the page methods are
+// measured from. The default is BottomLeft. Every page of a document uses the same origin, so it can't be changed
+// once a position has been given with the other one, which returns ErrMixedOrigins.
+func (d *PdfDocument) SetCoordinateOrigin(o Origin) error {
+	if o != d.origin && d.positioned.Load() {
+		return fmt.Errorf("%w: positions have already been given from the %v", ErrMixedOrigins, d.origin)
+	}
+	d.origin = o
+	return nil
+}
+
+// WithCoordinateOrigin sets the origin of positions, as for SetCoordinateOrigin
+func WithCoordinateOrigin(o Origin) Option {
+	return func(d *PdfDocument) {
+		if err := d.SetCoordinateOrigin(o); err != nil {
+			d.setErr(err)
+		}
+	}
+}
+
+func (o Origin) String() string {
+	if o == TopLeft {
+		return "top left"
+	}
+	return "bottom left"
+}
+
+// ptY converts a position y in the document's unit and origin to points above the bottom of the page. With a top
+// left origin y is the top of something h points high, so its bottom is h points further down.
+func (p *PdfPage) ptY(y, h float64) float64 {
+	if !p.document.positioned.Load() {
+		p.document.positioned.Store(true)
+	}
+	if p.document.origin == TopLeft {
+		return p.height - p.pt(y) - h
+	}
+	return p.pt(y)
+}
+
+// unitY converts a height in points above the bottom of the page to a position in the document's unit and origin
+func (p *PdfPage) unitY(y float64) float64 {
+	if p.document.origin == TopLeft {
+		y = p.height - y
+	}
+	return p.inUnit(y)
+}
+
+// point converts a position in the document's unit and origin to points from the bottom left corner of the page
+func (p *PdfPage) point(x, y float64) (float64, float64) {
+	return p.pt(x), p.ptY(y, 0)
+}
+
+// rect converts a rectangle with its corner at x, y in the document's unit and origin to points, with its bottom
+// left corner at the position returned
+func (p *PdfPage) rect(x, y, w, h float64) (float64, float64, float64, float64) {
+	w, h = p.pt(w), p.pt(h)
+	return p.pt(x), p.ptY(y, h), w, h
+}
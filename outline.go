@@ -0,0 +1,78 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Bookmark is an entry in the outline shown in the navigation pane of a viewer. Selecting it jumps to a position
+// on a page.
+type Bookmark struct {
+	PdfObject
+	title      string
+	page       *PdfPage
+	y          float64 // in points
+	targetName string  // a named destination, instead of page
+	outlines   *PdfOutlines
+	parent     *Bookmark
+	prev, next *Bookmark
+	children   []*Bookmark
+}
+
+// AddBookmark adds a bookmark that jumps to height y on page. The bookmark is added after any existing bookmarks
+// under parent, or at the top level of the outline when parent is nil.
+func (d *PdfDocument) AddBookmark(title string, page *PdfPage, y float64, parent *Bookmark) *Bookmark {
+	b := &Bookmark{title: title, page: page, y: d.pageY(page, y), outlines: d.catalog.outlines, parent: parent}
+	siblings := &d.catalog.outlines.bookmarks
+	if parent != nil {
+		siblings = &parent.children
+	}
+	if n := len(*siblings); n > 0 {
+		b.prev = (*siblings)[n-1]
+		b.prev.next = b
+	}
+	*siblings = append(*siblings, b)
+	d.addObject(b)
+	return b
+}
+
+// visibleBookmarks counts the bookmarks and all of their descendants. Every bookmark is open so all of them are
+// visible.
+func visibleBookmarks(bookmarks []*Bookmark) int {
+	count := 0
+	for _, b := range bookmarks {
+		count += 1 + visibleBookmarks(b.children)
+	}
+	return count
+}
+
+func (b *Bookmark) bytes() []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%v 0 obj\r\n", b.id)
+	fmt.Fprintf(&buf, "<<\r\n")
+	fmt.Fprintf(&buf, "/Title %v\r\n", formatTextString(b.title))
+	if b.parent != nil {
+		fmt.Fprintf(&buf, "/Parent %v\r\n", b.parent.objectRef())
+	} else {
+		fmt.Fprintf(&buf, "/Parent %v\r\n", b.outlines.objectRef())
+	}
+	if b.prev != nil {
+		fmt.Fprintf(&buf, "/Prev %v\r\n", b.prev.objectRef())
+	}
+	if b.next != nil {
+		fmt.Fprintf(&buf, "/Next %v\r\n", b.next.objectRef())
+	}
+	if len(b.children) > 0 {
+		fmt.Fprintf(&buf, "/First %v\r\n", b.children[0].objectRef())
+		fmt.Fprintf(&buf, "/Last %v\r\n", b.children[len(b.children)-1].objectRef())
+		fmt.Fprintf(&buf, "/Count %v\r\n", visibleBookmarks(b.children))
+	}
+	if b.targetName != "" {
+		fmt.Fprintf(&buf, "/Dest %v\r\n", formatString(b.targetName))
+	} else {
+		fmt.Fprintf(&buf, "/Dest %v\r\n", destination(b.page, b.y))
+	}
+	fmt.Fprintf(&buf, ">>\r\n")
+	fmt.Fprintf(&buf, "endobj\r\n")
+	return buf.Bytes()
+}
@@ -0,0 +1,499 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// PdfPageContent represents the contents of a page. Operators are written in the order they are called so that
+// changes to the graphics state apply to what is drawn after them; text operators are wrapped in BT and ET as needed.
+type PdfPageContent struct {
+	PdfObject
+	stream   bytes.Buffer
+	inText   bool
+	finished bool     // written by FinishPage, after which nothing can be added
+	page     *PdfPage // the page the content belongs to, or nil for a template
+	// pendingGS are the graphics states named while AddPages builds the page that the document doesn't have yet
+	pendingGS []pendingExtGState
+}
+
+// writable reports whether operators can still be added, recording ErrPageFinished if not
+func (c *PdfPageContent) writable() bool {
+	if c.finished {
+		c.document.setErr(ErrPageFinished)
+	}
+	return !c.finished
+}
+
+// startText starts a text object if one isn't open and reports whether text operators can be added
+func (c *PdfPageContent) startText() bool {
+	if !c.writable() {
+		return false
+	}
+	if !c.inText {
+		c.stream.WriteString("BT\r\n")
+		c.inText = true
+	}
+	return true
+}
+
+// endText ends the current text object if one is open and reports whether graphics operators can be added
+func (c *PdfPageContent) endText() bool {
+	if !c.writable() {
+		return false
+	}
+	if c.inText {
+		c.stream.WriteString("ET\r\n")
+		c.inText = false
+	}
+	return true
+}
+
+// addText appends text operators, starting a text object if one isn't open
+func (c *PdfPageContent) addText(ops string) {
+	if c.startText() {
+		c.stream.WriteString(ops)
+		c.annotate()
+	}
+}
+
+// addTextf appends formatted text operators, starting a text object if one isn't open
+func (c *PdfPageContent) addTextf(format string, args ...interface{}) {
+	if c.startText() {
+		fmt.Fprintf(&c.stream, format, args...)
+		c.annotate()
+	}
+}
+
+// addGraphics appends operators that must be outside a text object, ending the current one if necessary
+func (c *PdfPageContent) addGraphics(ops string) {
+	if c.endText() {
+		c.stream.WriteString(ops)
+		c.annotate()
+	}
+}
+
+// addGraphicsf appends formatted operators that must be outside a text object, ending the current one if necessary
+func (c *PdfPageContent) addGraphicsf(format string, args ...interface{}) {
+	if c.endText() {
+		fmt.Fprintf(&c.stream, format, args...)
+		c.annotate()
+	}
+}
+
+// addState appends operators that change the graphics state, which are allowed both inside and outside text objects
+func (c *PdfPageContent) addState(ops string) {
+	if c.writable() {
+		c.stream.WriteString(ops)
+		c.annotate()
+	}
+}
+
+// addStatef appends formatted operators that change the graphics state
+func (c *PdfPageContent) addStatef(format string, args ...interface{}) {
+	if c.writable() {
+		fmt.Fprintf(&c.stream, format, args...)
+		c.annotate()
+	}
+}
+
+// annotate follows the operators just added with a comment saying where they were added from, if SetDebugContent is
+// on
+func (c *PdfPageContent) annotate() {
+	if c.document != nil && c.document.debugContent {
+		c.stream.WriteString(callSite())
+	}
+}
+
+func (c *PdfPageContent) bytes() []byte {
+	if !c.document.compressContent() {
+		return streamObject(c.id, "", c.data())
+	}
+	return streamObject(c.id, "/Filter /FlateDecode\r\n", deflate(c.data()))
+}
+
+// data returns the operators of the content stream, ending any text object and saved states left open and with the
+// watermark added
+func (c *PdfPageContent) data() []byte {
+	stream := append([]byte(nil), c.stream.Bytes()...)
+	if c.inText {
+		stream = append(stream, "ET\r\n"...)
+	}
+	if c.page != nil {
+		// close any states left saved, so that the watermark isn't drawn inside them
+		stream = append(stream, strings.Repeat("Q\r\n", len(c.page.savedStates))...)
+	}
+	if wm := c.document.resources.watermark; wm != nil && c.page != nil {
+		if wm.over {
+			stream = append(stream, wm.placement(c.page)...)
+		} else {
+			stream = append([]byte(wm.placement(c.page)), stream...)
+		}
+	}
+	return stream
+}
+
+// PdfPage represents a single page
+type PdfPage struct {
+	PdfObject
+	parent            *PdfPages
+	content           *PdfPageContent
+	annots            []annotation
+	inPath            bool // a path has been started with MoveTo and not yet painted
+	charSpacing       float64
+	wordSpacing       float64
+	horizontalScaling float64 // percent
+	textRise          float64
+	tabSize           int             // distance between tab stops in spaces
+	lineHeight        float64         // multiple of the font size between lines, or 0 for single spacing
+	leading           float64         // fixed distance between lines in points, or 0 to follow the font size
+	extGState         extGStateParams // alpha and blend mode
+	savedStates       []pageState     // pushed by SaveState and popped by RestoreState
+	columns           *columnLayout   // columns started by BeginColumns, or nil for full width lines
+	columnRelative    bool            // images and boxes are positioned relative to the current column
+	next              *PdfPage        // page the text continues on after the last column is full
+	measure           *measurement    // set while KeepTogether measures content on a scratch copy of the page
+	used              *PdfResources   // the resources the content refers to
+	resources         *PdfResources   // the page's own resource dictionary while it is written, or nil for the document's
+	imported          pdfDict         // the dictionary of a page copied from another file by AppendPDF
+	build             *pageBuild      // set while the page is built by AddPages
+	barcodeText       bool
+	qrQuietZone       int // light border around QR codes in modules
+	underline         bool
+	strikethrough     bool
+	cellFill          Color
+	lastCellHeight    float64
+	cropBox           *[4]float64 // llx lly urx ury
+	rotate            int
+	font              *PdfFont
+	fontSize          float64
+	// the size, cursor and margins are in points
+	height, width           float64
+	x, y                    float64
+	leftMargin, rightMargin float64
+	topMargin, bottomMargin float64
+}
+
+// SetFont selects one of the fonts added to the document by name. Names are case sensitive, and the error for an
+// unknown name lists the fonts that have been added.
+func (p *PdfPage) SetFont(name string) error {
+	font, err := p.document.resources.font(name)
+	if err != nil {
+		return err
+	}
+	p.font = font
+	p.use(font)
+	p.content.addTextf("%v %v Tf\r\n", formatName(p.font.name), formatNumber(p.fontSize))
+	return nil
+}
+
+// font returns the font added under name, or ErrFontNotFound listing the fonts that have been added
+func (r *PdfResources) font(name string) (*PdfFont, error) {
+	var font *PdfFont
+	for _, f := range r.fonts {
+		if f.name == name {
+			font = f
+		}
+	}
+	if font == nil {
+		names := make([]string, len(r.fonts))
+		for i, f := range r.fonts {
+			names[i] = f.name
+		}
+		return nil, fmt.Errorf("%w: %v (%v)", ErrFontNotFound, name, availableNames("fonts", names))
+	}
+	return font, nil
+}
+
+// SetFontSize sets the size of the current font in points, which can be fractional, such as 10.5
+func (p *PdfPage) SetFontSize(size float64) {
+	p.fontSize = size
+	if p.font != nil {
+		p.content.addTextf("%v %v Tf\r\n", formatName(p.font.name), formatNumber(p.fontSize))
+	}
+	if p.leading == 0 {
+		// the line spacing follows the font size
+		p.content.addTextf("%v TL\r\n", formatNumber(p.lineAdvance()))
+	}
+}
+
+// SetXY moves the text cursor to the given position
+func (p *PdfPage) SetXY(x, y float64) {
+	p.x, p.y = p.point(x, y)
+}
+
+// SetX moves the text cursor horizontally to x
+func (p *PdfPage) SetX(x float64) {
+	p.x = p.pt(x)
+}
+
+// SetY moves the text cursor vertically to the baseline y
+func (p *PdfPage) SetY(y float64) {
+	p.y = p.ptY(y, 0)
+}
+
+// GetX returns the horizontal position of the text cursor
+func (p *PdfPage) GetX() float64 {
+	return p.inUnit(p.x)
+}
+
+// GetY returns the baseline of the text cursor
+func (p *PdfPage) GetY() float64 {
+	return p.unitY(p.y)
+}
+
+// SetMargins sets the page margins. Lines start at the left margin, wrapped and aligned text fits between the left
+// and right margins, and tables break onto a new page at the bottom margin.
+func (p *PdfPage) SetMargins(left, top, right, bottom float64) {
+	p.leftMargin, p.topMargin, p.rightMargin, p.bottomMargin = p.pt(left), p.pt(top), p.pt(right), p.pt(bottom)
+}
+
+func (p *PdfPage) outputText(text string) {
+	p.outputTextAt(text, p.x)
+}
+
+// outputTextAt outputs text starting at x on the current line without moving the cursor
+func (p *PdfPage) outputTextAt(text string, x float64) {
+	p.content.addTextf("1 0 0 1 %v %v Tm\r\n%s Tj\r\n", formatNumber(x), formatNumber(p.y), p.textString(text))
+	p.measureBaseline()
+	p.decorateText(x, p.textWidth(text))
+}
+
+// textString converts text to the encoding of the current font and returns it as a string operand for Tj
+func (p *PdfPage) textString(text string) string {
+	if p.font == nil {
+		// text is measured in Helvetica when no font has been set, so it is shown in it too
+		p.font = p.document.helvetica()
+		p.use(p.font)
+		p.content.addTextf("%v %v Tf\r\n", formatName(p.font.name), formatNumber(p.fontSize))
+	}
+	text, unmapped, ok := p.encodeText(text, true)
+	if !ok && p.document.strictEncoding {
+		p.document.setErr(fmt.Errorf("%w: %q", ErrUnmappableRune, unmapped))
+	}
+	if p.font != nil && p.font.unicode != nil {
+		return fmt.Sprintf("<%X>", text)
+	}
+	return formatString(text)
+}
+
+// Print outputs text at the cursor and leaves the cursor at the end of the text. A newline in the text moves the
+// cursor to the start of the next line and a tab moves it to the next tab stop.
+func (p *PdfPage) Print(text string) {
+	p = p.flow()
+	for i, line := range splitLines(text) {
+		if i > 0 {
+			p.newLine()
+			p = p.flow()
+		}
+		segments := strings.Split(line, "\t")
+		for j, segment := range segments {
+			if j > 0 {
+				p.x = p.nextTabStop()
+			}
+			if segment != "" || len(segments) == 1 {
+				p.outputText(segment)
+				p.x += p.textWidth(segment)
+			}
+		}
+	}
+}
+
+// Println outputs text at the cursor as for Print and moves the cursor to the start of the next line
+func (p *PdfPage) Println(text string) {
+	p.Print(text)
+	p.flow().newLine()
+}
+
+// newLine moves the cursor to the start of the next line, which is in the next column when text in columns reaches
+// the bottom margin
+func (p *PdfPage) newLine() {
+	p.x = p.lineLeft()
+	p.y -= p.lineAdvance()
+	if p.columns != nil && p.y < p.bottomMargin {
+		p.nextColumn()
+	}
+}
+
+// continuation adds a page for content that continues from p onto the next page, with the font and size the content
+// is in and the cursor on the first line below the top margin
+func (p *PdfPage) continuation(font string, size float64) *PdfPage {
+	var next *PdfPage
+	if p.measure != nil {
+		// content measured by KeepTogether doesn't fit on a page, and the pages it would continue on are thrown away
+		p.measure.pageBreaks++
+		margins := [4]float64{p.leftMargin, p.topMargin, p.rightMargin, p.bottomMargin}
+		next = p.document.newPage(p.width, p.height, margins)
+		next.content.page, next.measure = next, p.measure
+	} else if p.build != nil {
+		next = p.build.addPage(p.document)
+	} else {
+		next = p.document.AddPage()
+	}
+	next.SetFontSize(size)
+	if font != "" {
+		next.SetFont(font)
+	}
+	next.y = next.height - next.topMargin - size
+	return next
+}
+
+// splitLines splits text at each newline or CRLF
+func splitLines(text string) []string {
+	return strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+}
+
+// SetCropBox sets the visible area of the page, the rectangle with its corner at x, y
+func (p *PdfPage) SetCropBox(x, y, w, h float64) {
+	x, y, w, h = p.rect(x, y, w, h)
+	p.cropBox = &[4]float64{x, y, x + w, y + h}
+}
+
+// SetRotate sets the angle in degrees clockwise that the page is turned when it is displayed or printed. It must be
+// a multiple of 90.
+func (p *PdfPage) SetRotate(deg int) error {
+	if deg%90 != 0 {
+		return fmt.Errorf("%w: %v", ErrInvalidRotation, deg)
+	}
+	p.rotate = (deg%360 + 360) % 360
+	return nil
+}
+
+// PrintRotated outputs text starting at x, y rotated anticlockwise by angleDeg degrees. The cursor is not moved and
+// the text is not underlined or struck through.
+func (p *PdfPage) PrintRotated(text string, x, y float64, angleDeg float64) {
+	sin, cos := sinCos(angleDeg)
+	p.PrintTransformed(text, cos, sin, -sin, cos, x, y)
+}
+
+// PrintTransformed outputs text using the text matrix [a b c d e f], which can scale, skew and rotate it as well as
+// position it at e, f. The cursor is not moved.
+func (p *PdfPage) PrintTransformed(text string, a, b, c, d, e, f float64) {
+	e, f = p.point(e, f)
+	p.content.addTextf("%v %v %v %v %v %v Tm\r\n%s Tj\r\n", formatNumber(a), formatNumber(b),
+		formatNumber(c), formatNumber(d), formatNumber(e), formatNumber(f), p.textString(text))
+}
+
+// sinCos returns the sine and cosine of an angle in degrees, exactly for multiples of 90 degrees
+func sinCos(angleDeg float64) (sin, cos float64) {
+	switch math.Mod(math.Mod(angleDeg, 360)+360, 360) {
+	case 0:
+		return 0, 1
+	case 90:
+		return 1, 0
+	case 180:
+		return 0, -1
+	case 270:
+		return -1, 0
+	}
+	return math.Sincos(angleDeg * math.Pi / 180)
+}
+
+// DrawImage draws a named image with its corner at x, y. Names are case sensitive, and the error for an
+// unknown name lists the images that have been added.
+func (p *PdfPage) DrawImage(name string, x, y float64) error {
+	return p.DrawImageScaled(name, x, y, 0, 0)
+}
+
+// DrawImageScaled draws a named image with its corner at x, y scaled to w by h. When one of w and h is
+// 0 it is chosen to keep the aspect ratio of the image, and when both are 0 the image is drawn one point to a pixel.
+func (p *PdfPage) DrawImageScaled(name string, x, y, w, h float64) error {
+	i, err := p.document.resources.image(name)
+	if err != nil {
+		return err
+	}
+	w, h = i.scaledSize(p.pt(w), p.pt(h))
+	x, y = p.columnX(p.pt(x)), p.ptY(y, h)
+	i.drawnAt(w, h)
+	p.use(i)
+
+	p.content.addGraphicsf("q\r\n%v\r\n%v Do\r\nQ\r\n", i.placement(x, y, w, h), formatName(name))
+	return nil
+}
+
+// DrawImageScaledFloat is the same as DrawImageScaled.
+//
+// Deprecated: DrawImageScaled takes fractional positions and sizes.
+func (p *PdfPage) DrawImageScaledFloat(name string, x, y, w, h float64) error {
+	return p.DrawImageScaled(name, x, y, w, h)
+}
+
+// availableNames describes the names of the resources of a kind for an error message
+func availableNames(kind string, names []string) string {
+	if len(names) == 0 {
+		return "no " + kind + " have been added"
+	}
+	return kind + " are " + strings.Join(names, ", ")
+}
+
+// DrawBox draws a rectangle with its corner at x, y
+func (p *PdfPage) DrawBox(x, y, w, h float64) {
+	p.DrawBoxStyled(x, y, w, h, Stroke)
+}
+
+// DrawLine draws a line from x1, y1 to x2, y2
+func (p *PdfPage) DrawLine(x1, y1, x2, y2 float64) {
+	x1, y1 = p.point(x1, y1)
+	x2, y2 = p.point(x2, y2)
+	p.content.addGraphicsf("%v m\r\n%v l\r\nS\r\n", formatNumbers(x1, y1), formatNumbers(x2, y2))
+}
+
+// SetColour sets the colour used for text and filled shapes, with components from 0 to 255. It is the same as
+// SetFillColor.
+func (p *PdfPage) SetColour(red, green, blue int) {
+	p.SetFillColor(red, green, blue)
+}
+
+// SetFillColor sets the colour used for text and filled shapes, with components from 0 to 255
+func (p *PdfPage) SetFillColor(red, green, blue int) {
+	p.SetFill(RGBColor{red, green, blue})
+}
+
+// SetStrokeColor sets the colour used for lines and the outlines of shapes, with components from 0 to 255
+func (p *PdfPage) SetStrokeColor(red, green, blue int) {
+	p.SetStroke(RGBColor{red, green, blue})
+}
+
+// rgb converts colour components from 0 to 255 to the 0 to 1 range used in content streams
+func rgb(red, green, blue int) string {
+	return fmt.Sprintf("%v %v %v", formatNumber(float64(red)/255), formatNumber(float64(green)/255), formatNumber(float64(blue)/255))
+}
+
+func (p PdfPage) bytes() []byte {
+	if p.imported != nil {
+		return p.importedBytes()
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%v 0 obj\r\n", p.id)
+	fmt.Fprintf(&buf, "<<\r\n")
+	fmt.Fprintf(&buf, "/Type /Page\r\n")
+	fmt.Fprintf(&buf, "/Parent %v\r\n", p.parent.objectRef())
+	if mediaBox := (PageSize{Width: p.width, Height: p.height}).mediaBox(); mediaBox != p.parent.mediaBox() {
+		fmt.Fprintf(&buf, "/MediaBox %v\r\n", mediaBox)
+	}
+	if p.cropBox != nil {
+		fmt.Fprintf(&buf, "/CropBox [ %v %v %v %v ]\r\n", formatNumber(p.cropBox[0]), formatNumber(p.cropBox[1]),
+			formatNumber(p.cropBox[2]), formatNumber(p.cropBox[3]))
+	}
+	if p.rotate != 0 {
+		fmt.Fprintf(&buf, "/Rotate %v\r\n", p.rotate)
+	}
+	resources := p.document.resources
+	if p.resources != nil {
+		resources = p.resources
+	}
+	fmt.Fprintf(&buf, "/Resources %v\r\n", resources.objectRef())
+	fmt.Fprintf(&buf, "/Contents %v\r\n", p.content.objectRef())
+	if len(p.annots) > 0 {
+		fmt.Fprintf(&buf, "/Annots [ ")
+		for _, a := range p.annots {
+			fmt.Fprintf(&buf, "%v ", a.objectRef())
+		}
+		fmt.Fprintf(&buf, "]\r\n")
+	}
+	fmt.Fprintf(&buf, ">>\r\n")
+	fmt.Fprintf(&buf, "endobj\r\n")
+	return buf.Bytes()
+}
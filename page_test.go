@@ -0,0 +1,266 @@
+package gopdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"io"
+	"math"
+	"strings"
+	"testing"
+)
+
+// pageContents returns the content streams of the pages of a PDF file as they are in the file
+func pageContents(t *testing.T, data []byte) []*pdfStream {
+	t.Helper()
+	pr, err := parsePDF(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pages, err := pr.pages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var streams []*pdfStream
+	for i, page := range pages {
+		s, ok := pr.resolve(page.dict.get("Contents")).(*pdfStream)
+		if !ok {
+			t.Fatalf("page %v has no content stream", i+1)
+		}
+		streams = append(streams, s)
+	}
+	return streams
+}
+
+func TestCompressedContent(t *testing.T) {
+	build := func(compress bool) []*pdfStream {
+		d := testDocument(t)
+		d.SetCompression(compress)
+		data, err := d.Bytes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pageContents(t, data)
+	}
+	compressed, plain := build(true), build(false)
+	if len(compressed) != len(plain) {
+		t.Fatalf("%v pages compressed and %v not", len(compressed), len(plain))
+	}
+	for i := range plain {
+		if !bytes.Contains(plain[i].data, []byte(") Tj")) {
+			t.Errorf("page %v has no text in %q", i+1, plain[i].data)
+		}
+		if filter := plain[i].dict.get("Filter"); filter != nil {
+			t.Errorf("page %v uncompressed has filter %v", i+1, filter)
+		}
+		if filter := compressed[i].dict.get("Filter"); filter != pdfName("FlateDecode") {
+			t.Errorf("page %v compressed has filter %v", i+1, filter)
+			continue
+		}
+		r, err := zlib.NewReader(bytes.NewReader(compressed[i].data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		inflated, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(inflated, plain[i].data) {
+			t.Errorf("page %v inflates to\n%q\nwant\n%q", i+1, inflated, plain[i].data)
+		}
+	}
+}
+
+func TestDrawingOrder(t *testing.T) {
+	d := NewPdfDocument()
+	d.SetCompression(false)
+	if _, err := d.AddImage("gopher", "gopher.jpg"); err != nil {
+		t.Fatal(err)
+	}
+	p := d.CurrentPage()
+	p.DrawLine(10, 10, 500, 500)
+	if err := p.DrawImage("gopher", 100, 100); err != nil {
+		t.Fatal(err)
+	}
+	p.DrawBox(50, 50, 200, 100)
+	data, err := d.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(pageContents(t, data)[0].data)
+	// each operator follows the ones drawn before it
+	at := 0
+	for _, op := range []string{"10 10 m\r\n500 500 l\r\nS\r\n", "/gopher Do\r\n", " re\r\nS\r\n"} {
+		i := strings.Index(content[at:], op)
+		if i < 0 {
+			t.Fatalf("%q isn't after %q in %q", op, content[:at], content)
+		}
+		at += i + len(op)
+	}
+}
+
+func TestResourceNotFound(t *testing.T) {
+	empty := NewPdfDocument().CurrentPage()
+	d := NewPdfDocument()
+	for _, name := range []string{"Helvetica", "Times"} {
+		if _, err := d.AddFont(name, Helvetica); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, name := range []string{"Gopher", "Logo"} {
+		if _, err := d.AddImage(name, "gopher.jpg"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	p := d.CurrentPage()
+	before := p.content.stream.String()
+	for _, c := range []struct {
+		err  error
+		want error
+		list string
+	}{
+		{p.SetFont("Helvetca"), ErrFontNotFound, "Helvetca (fonts are Helvetica, Times)"},
+		{p.SetFont("helvetica"), ErrFontNotFound, "helvetica (fonts are Helvetica, Times)"},
+		{p.SetFont(""), ErrFontNotFound, " (fonts are Helvetica, Times)"},
+		{empty.SetFont("Helvetica"), ErrFontNotFound, "Helvetica (no fonts have been added)"},
+		{p.DrawImage("Gofer", 0, 0), ErrImageNotFound, "Gofer (images are Gopher, Logo)"},
+		{p.DrawImage("gopher", 0, 0), ErrImageNotFound, "gopher (images are Gopher, Logo)"},
+		{p.DrawImageScaled("LOGO", 0, 0, 10, 10), ErrImageNotFound, "LOGO (images are Gopher, Logo)"},
+		{empty.DrawImage("Gopher", 0, 0), ErrImageNotFound, "Gopher (no images have been added)"},
+	} {
+		if !errors.Is(c.err, c.want) || !strings.HasSuffix(c.err.Error(), ": "+c.list) {
+			t.Errorf("got %v, want %v: %v", c.err, c.want, c.list)
+		}
+	}
+	if after := p.content.stream.String(); after != before {
+		t.Errorf("failed calls added %q", after[len(before):])
+	}
+}
+
+func TestPrintControlCharacters(t *testing.T) {
+	d := NewPdfDocument()
+	d.SetCompression(false)
+	p := d.CurrentPage()
+	p.SetMargins(50, 50, 50, 50)
+	p.SetFontSize(10)
+	p.SetXY(50, 100)
+	p.Println("a\x01b\r\nc\td\x7fe\x1bf")
+	p.Print("\tg\n\nh")
+	data, err := d.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := pageContents(t, data)[0].data
+
+	// the text shown by each Tj, where it is shown and the raw operand in the content stream
+	type shown struct {
+		x, y float64
+		text string
+	}
+	number := func(v any) float64 {
+		if n, ok := v.(int); ok {
+			return float64(n)
+		}
+		f, _ := v.(float64)
+		return f
+	}
+	var got []shown
+	var x, y float64
+	var operands []any
+	parser := &pdfParser{data: content}
+	for {
+		parser.skipSpace()
+		if parser.pos >= len(content) {
+			break
+		}
+		start := parser.pos
+		if !isPDFDelimiter(content[start]) {
+			switch token := parser.keyword(); {
+			case isOperand(token):
+				parser.pos = start
+			case token == "Tm":
+				x, y = number(operands[4]), number(operands[5])
+				operands = operands[:0]
+				continue
+			case token == "Tj" || token == "TJ":
+				for _, v := range operands {
+					s, _ := v.(pdfString)
+					got = append(got, shown{x, y, string(s)})
+				}
+				operands = operands[:0]
+				continue
+			default:
+				operands = operands[:0]
+				continue
+			}
+		}
+		if content[start] == '(' {
+			_, end := parseLiteralString(content, start)
+			for _, c := range content[start:end] {
+				if c < 0x20 || c == 0x7f {
+					t.Errorf("raw byte %#x in the string literal %q", c, content[start:end])
+				}
+			}
+		}
+		v, err := parser.value()
+		if err != nil {
+			t.Fatal(err)
+		}
+		operands = append(operands, v)
+	}
+
+	// tab stops are four Helvetica spaces of 0.278 em apart, measured from the left margin
+	stop := 4 * 0.278 * 10
+	want := []struct {
+		line int
+		x    float64
+		text string
+	}{
+		{0, 50, "a\x01b"},
+		{1, 50, "c"},
+		{1, 50 + stop, "d\x7fe\x1bf"},
+		{2, 50 + stop, "g"},
+		{3, 50, ""},
+		{4, 50, "h"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("shown %+v, want %+v", got, want)
+	}
+	lines := map[int]float64{}
+	for i, w := range want {
+		if got[i].text != w.text || math.Abs(got[i].x-w.x) > 0.001 {
+			t.Errorf("shown %q at x %v, want %q at %v", got[i].text, got[i].x, w.text, w.x)
+		}
+		if y, ok := lines[w.line]; ok && got[i].y != y {
+			t.Errorf("%q is at y %v, off line %v at %v", got[i].text, got[i].y, w.line, y)
+		}
+		if y, ok := lines[w.line-1]; ok && got[i].y >= y {
+			t.Errorf("%q is at y %v, not below line %v at %v", got[i].text, got[i].y, w.line-1, y)
+		}
+		lines[w.line] = got[i].y
+	}
+}
+
+// BenchmarkTextPage builds and writes a page of 20,000 text, line and colour operations, as a large table would make
+func BenchmarkTextPage(b *testing.B) {
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		d := NewPdfDocument()
+		if _, err := d.AddFont("Helv", Helvetica); err != nil {
+			b.Fatal(err)
+		}
+		p := d.CurrentPage()
+		p.SetFont("Helv")
+		p.SetFontSize(4)
+		for i := 0; i < 5000; i++ {
+			x, y := float64(i%20)*28, float64(i/20)*3
+			p.SetColour(i%256, 0, 0)
+			p.SetXY(x, y)
+			p.Print("cell text")
+			p.DrawLine(x, y, x+28, y)
+		}
+		if _, err := d.Bytes(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,274 @@
+package gopdf
+
+import (
+	"fmt"
+	"slices"
+)
+
+// DeletePage removes the page at index, counting from 0, from the document along with its content and
+// annotations, and renumbers the objects that are left. Links on other pages, bookmarks and named destinations
+// that jump to the page are removed too, with a warning for each returned by Warnings, and headings on the page are
+// left out of the table of contents. Pages can't be deleted from a document being written with StartWriting.
+func (d *PdfDocument) DeletePage(index int) error {
+	if err := d.checkPageIndex(index); err != nil {
+		return err
+	}
+	pages := d.catalog.pdfPages.pages
+	p := pages[index]
+	pages = append(pages[:index:index], pages[index+1:]...)
+	d.catalog.pdfPages.pages = pages
+
+	remove := map[PdfObjectWriter]bool{p: true}
+	if p.imported == nil {
+		remove[p.content] = true
+	}
+	for _, a := range p.annots {
+		removeAnnotation(a, remove)
+	}
+	if form := d.catalog.acroForm; form != nil {
+		form.fields = slices.DeleteFunc(form.fields, func(f *PdfFormField) bool { return f.page == p })
+	}
+	for o := range d.orphanedImports(p) {
+		remove[o] = true
+	}
+
+	for _, other := range pages {
+		other.annots = slices.DeleteFunc(other.annots, func(a annotation) bool {
+			l, ok := a.(*PdfLink)
+			drop := ok && l.target == p
+			if drop {
+				remove[l] = true
+				d.warn("link to deleted page %v removed", index)
+			}
+			return drop
+		})
+		if other.next == p {
+			other.next = nil
+		}
+	}
+	outlines := d.catalog.outlines
+	outlines.bookmarks = d.removeBookmarks(outlines.bookmarks, nil, p, index, remove)
+	d.catalog.dests = slices.DeleteFunc(d.catalog.dests, func(dest *namedDestination) bool {
+		if dest.page == p {
+			d.warn("named destination %q on deleted page %v removed", dest.name, index)
+		}
+		return dest.page == p
+	})
+	if a := d.catalog.openAction; a != nil && a.page == p {
+		d.catalog.openAction = nil
+		d.warn("open action on deleted page %v removed", index)
+	}
+	d.headings = slices.DeleteFunc(d.headings, func(h heading) bool { return h.page == p })
+	if d.currentPage == p {
+		d.currentPage = nil
+		if len(pages) > 0 {
+			d.currentPage = pages[len(pages)-1]
+		}
+	}
+	d.removeObjects(remove)
+	return nil
+}
+
+// DuplicatePage adds a copy of the page at index, counting from 0, after it and returns the copy. The copy's content
+// can be added to without changing the original. Links, notes and free text annotations are copied, but form fields
+// are not, since each field can only be on one page.
+func (d *PdfDocument) DuplicatePage(index int) (*PdfPage, error) {
+	if err := d.checkPageIndex(index); err != nil {
+		return nil, err
+	}
+	p := d.catalog.pdfPages.pages[index]
+	c := *p
+	c.content = &PdfPageContent{inText: p.content.inText, finished: p.content.finished, page: &c}
+	c.content.stream.Write(p.content.stream.Bytes())
+	c.content.document = d
+	c.savedStates = slices.Clone(p.savedStates)
+	if p.columns != nil {
+		columns := *p.columns
+		c.columns = &columns
+	}
+	if p.cropBox != nil {
+		box := *p.cropBox
+		c.cropBox = &box
+	}
+	if p.used != nil {
+		// clip the lists so that resources used by one page aren't added to the other's
+		used := *p.used
+		used.fonts, used.images = slices.Clip(used.fonts), slices.Clip(used.images)
+		used.templates, used.extGStates = slices.Clip(used.templates), slices.Clip(used.extGStates)
+		used.patterns, used.spotColors = slices.Clip(used.patterns), slices.Clip(used.spotColors)
+		c.used = &used
+	}
+	c.next, c.measure, c.resources = nil, nil, nil
+	if p.imported != nil {
+		// an imported page's annotations are objects of the file it came from, which belong to the original
+		c.imported = slices.DeleteFunc(slices.Clone(p.imported), func(e pdfEntry) bool { return e.key == "Annots" })
+	}
+
+	d.addObject(&c)
+	if p.imported == nil {
+		d.addObject(c.content)
+	}
+	c.annots = nil
+	for _, a := range p.annots {
+		var copied annotation
+		switch a := a.(type) {
+		case *PdfLink:
+			l := *a
+			copied = &l
+		case *PdfNote:
+			n := *a
+			copied = &n
+		case *PdfFreeText:
+			t := *a
+			copied = &t
+		}
+		if copied != nil {
+			d.addObject(copied)
+			c.annots = append(c.annots, copied)
+		}
+	}
+	d.catalog.pdfPages.pages = slices.Insert(d.catalog.pdfPages.pages, index+1, &c)
+	return &c, nil
+}
+
+// MovePage moves the page at index from to index to, counting from 0, moving the pages in between up or down
+func (d *PdfDocument) MovePage(from, to int) error {
+	if err := d.checkPageIndex(from); err != nil {
+		return err
+	}
+	if err := d.checkPageIndex(to); err != nil {
+		return err
+	}
+	pages := d.catalog.pdfPages.pages
+	p := pages[from]
+	pages = slices.Delete(pages, from, from+1)
+	d.catalog.pdfPages.pages = slices.Insert(pages, to, p)
+	return nil
+}
+
+// Warnings returns the things that have been removed from the document as a side effect of changing it, such as the
+// links to a page deleted with DeletePage
+func (d *PdfDocument) Warnings() []string {
+	return d.warnings
+}
+
+// warn records a warning returned by Warnings
+func (d *PdfDocument) warn(format string, args ...any) {
+	d.warnings = append(d.warnings, fmt.Sprintf(format, args...))
+}
+
+// checkPageIndex returns ErrPageOutOfRange if index is not the index of a page, or ErrStreaming if the pages are
+// being written with StartWriting
+func (d *PdfDocument) checkPageIndex(index int) error {
+	if d.stream != nil {
+		return ErrStreaming
+	}
+	if pages := len(d.catalog.pdfPages.pages); index < 0 || index >= pages {
+		return fmt.Errorf("%w: page %v of %v", ErrPageOutOfRange, index, pages)
+	}
+	return nil
+}
+
+// removeAnnotation adds an annotation and its appearance streams to the objects to remove
+func removeAnnotation(a annotation, remove map[PdfObjectWriter]bool) {
+	remove[a] = true
+	switch a := a.(type) {
+	case *PdfFreeText:
+		remove[a.appearance] = true
+	case *PdfFormField:
+		for _, ap := range a.appearance {
+			if ap != nil {
+				remove[ap] = true
+			}
+		}
+	}
+}
+
+// removeBookmarks returns bookmarks without those that jump to page p, which is deleted from index, adding them to
+// the objects to remove. The children of a bookmark removed take its place under parent.
+func (d *PdfDocument) removeBookmarks(bookmarks []*Bookmark, parent *Bookmark, p *PdfPage, index int,
+	remove map[PdfObjectWriter]bool) []*Bookmark {
+	var kept []*Bookmark
+	for _, b := range bookmarks {
+		b.children = d.removeBookmarks(b.children, b, p, index, remove)
+		if b.page != p || b.targetName != "" {
+			kept = append(kept, b)
+			continue
+		}
+		remove[b] = true
+		d.warn("bookmark %q to deleted page %v removed", b.title, index)
+		for _, child := range b.children {
+			child.parent = parent
+		}
+		kept = append(kept, b.children...)
+	}
+	for i, b := range kept {
+		b.prev, b.next = nil, nil
+		if i > 0 {
+			b.prev, kept[i-1].next = kept[i-1], b
+		}
+	}
+	return kept
+}
+
+// orphanedImports returns the objects copied by AppendPDF that are only used by page p, so that they can be removed
+// with it
+func (d *PdfDocument) orphanedImports(p *PdfPage) map[*importedObject]bool {
+	if p.imported == nil {
+		return nil
+	}
+	used := map[*importedObject]bool{}
+	for _, other := range d.catalog.pdfPages.pages {
+		if other != p {
+			reachImports(other.imported, used)
+		}
+	}
+	for _, t := range d.resources.templates {
+		if t.imported != nil {
+			reachImports(t.imported.dict, used)
+		}
+	}
+	orphaned := map[*importedObject]bool{}
+	reachImports(p.imported, orphaned)
+	for o := range used {
+		delete(orphaned, o)
+	}
+	return orphaned
+}
+
+// reachImports adds the copied objects that a value refers to, directly or through other copied objects, to reached
+func reachImports(v any, reached map[*importedObject]bool) {
+	switch v := v.(type) {
+	case importedRef:
+		if o := v.imported; o != nil && !reached[o] {
+			reached[o] = true
+			reachImports(o.value, reached)
+		}
+	case []any:
+		for _, item := range v {
+			reachImports(item, reached)
+		}
+	case pdfDict:
+		for _, e := range v {
+			reachImports(e.value, reached)
+		}
+	case *pdfStream:
+		reachImports(v.dict, reached)
+	}
+}
+
+// removeObjects removes objects from the document and numbers the rest again in order. Removed objects are left
+// with id 0, so references to them that remain are written as null.
+func (d *PdfDocument) removeObjects(remove map[PdfObjectWriter]bool) {
+	kept := d.objects[:0]
+	for _, o := range d.objects {
+		if remove[o] {
+			o.setID(0)
+			continue
+		}
+		o.setID(len(kept) + 1)
+		kept = append(kept, o)
+	}
+	clear(d.objects[len(kept):])
+	d.objects = kept
+}
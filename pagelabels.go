@@ -0,0 +1,75 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+)
+
+// PageLabelStyle is the numbering style of page labels
+type PageLabelStyle int
+
+// Page label styles. LabelNone labels pages with the prefix alone.
+const (
+	LabelDecimal PageLabelStyle = iota
+	LabelRomanLower
+	LabelRomanUpper
+	LabelLettersLower
+	LabelLettersUpper
+	LabelNone
+)
+
+// pageLabelRange labels the pages from start up to the start of the next range
+type pageLabelRange struct {
+	start       int
+	style       PageLabelStyle
+	prefix      string
+	startNumber int
+}
+
+// AddPageLabelRange sets the labels that viewers show for pages, starting with the page at startPageIndex, counting
+// from 0, until the next range. Each page is labelled with prefix followed by its number in style, counting from
+// startNumber, or from 1 if startNumber is 0. Pages before the first range are numbered 1, 2, 3 and so on. A range
+// that starts at the same page as an earlier one replaces it.
+func (d *PdfDocument) AddPageLabelRange(startPageIndex int, style PageLabelStyle, prefix string, startNumber int) error {
+	if startPageIndex < 0 || startNumber < 0 || style < LabelDecimal || style > LabelNone {
+		return fmt.Errorf("%w: page %v, style %v, start %v", ErrInvalidPageLabel, startPageIndex, style, startNumber)
+	}
+	r := &pageLabelRange{start: startPageIndex, style: style, prefix: prefix, startNumber: startNumber}
+	for i, existing := range d.catalog.pageLabels {
+		if existing.start == startPageIndex {
+			d.catalog.pageLabels[i] = r
+			return nil
+		}
+	}
+	d.catalog.pageLabels = append(d.catalog.pageLabels, r)
+	return nil
+}
+
+// pageLabelsTree returns the number tree of the page label ranges in ascending order, starting with the first page
+func pageLabelsTree(ranges []*pageLabelRange) string {
+	sorted := append([]*pageLabelRange(nil), ranges...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].start < sorted[j].start
+	})
+	if sorted[0].start != 0 {
+		sorted = append([]*pageLabelRange{{}}, sorted...)
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "<< /Nums [ ")
+	for _, r := range sorted {
+		fmt.Fprintf(&buf, "%v << ", r.start)
+		if r.style != LabelNone {
+			fmt.Fprintf(&buf, "/S /%c ", "DrRaA"[r.style])
+		}
+		if r.prefix != "" {
+			fmt.Fprintf(&buf, "/P %v ", formatTextString(r.prefix))
+		}
+		if r.startNumber > 1 {
+			fmt.Fprintf(&buf, "/St %v ", r.startNumber)
+		}
+		fmt.Fprintf(&buf, ">> ")
+	}
+	fmt.Fprintf(&buf, "] >>")
+	return buf.String()
+}
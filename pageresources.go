@@ -0,0 +1,72 @@
+package gopdf
+
+// use records that the page's content refers to a font, image, template, graphics state, pattern or the spot colour
+// of a colour, so that it goes in the page's resource dictionary
+func (p *PdfPage) use(resource any) {
+	if p.used == nil {
+		p.used = &PdfResources{}
+	}
+	r := p.used
+	switch resource := resource.(type) {
+	case *PdfFont:
+		r.fonts = appendNew(r.fonts, resource)
+	case *PdfImage:
+		r.images = appendNew(r.images, resource)
+	case *PdfTemplate:
+		r.templates = appendNew(r.templates, resource)
+	case *PdfExtGState:
+		r.extGStates = appendNew(r.extGStates, resource)
+	case *PdfPattern:
+		r.patterns = appendNew(r.patterns, resource)
+	case spotTint:
+		r.spotColors = appendNew(r.spotColors, resource.spot)
+	}
+}
+
+// appendNew appends v to s unless it is already in it
+func appendNew[T comparable](s []T, v T) []T {
+	for _, existing := range s {
+		if existing == v {
+			return s
+		}
+	}
+	return append(s, v)
+}
+
+// addPageResources gives each page a resource dictionary listing only the resources its content uses, shared by
+// pages that use the same ones, and returns a function that removes them again. Pages that don't use any resources
+// keep the document's dictionary of all of them.
+func (d *PdfDocument) addPageResources() (remove func()) {
+	objects := len(d.objects)
+	pages := d.catalog.pdfPages.pages
+	shared := make(map[string]*PdfResources)
+	for _, p := range pages {
+		if p.imported != nil {
+			continue
+		}
+		used := PdfResources{}
+		if p.used != nil {
+			used = *p.used
+		}
+		if len(used.fonts)+len(used.images)+len(used.templates)+len(used.extGStates)+len(used.patterns)+
+			len(used.spotColors) == 0 {
+			continue
+		}
+		used.watermark, used.iccColors = d.resources.watermark, d.resources.iccColors
+		used.setDocument(d)
+		dictionary := used.dictionary()
+		r := shared[dictionary]
+		if r == nil {
+			r = &used
+			d.addObject(r)
+			shared[dictionary] = r
+		}
+		p.resources = r
+	}
+	return func() {
+		for _, p := range pages {
+			p.resources = nil
+		}
+		d.objects = d.objects[:objects]
+	}
+}
@@ -0,0 +1,51 @@
+package gopdf
+
+import "fmt"
+
+// PageSize is the width and height of a page.
+// Any size can be used by supplying the dimensions directly in the document's unit, e.g. PageSize{Width: 400,
+// Height: 600} in points or PageSize{Width: 210, Height: 99} after SetUnit(UnitMM).
+type PageSize struct {
+	Width, Height float64
+	points        bool // the size is in points whatever the document's unit, as the standard sizes are
+}
+
+// Standard page sizes, in points, which are the same whatever the document's unit
+var (
+	A3      = PageSize{Width: 842, Height: 1191, points: true}
+	A4      = PageSize{Width: 595, Height: 842, points: true}
+	A5      = PageSize{Width: 420, Height: 595, points: true}
+	Letter  = PageSize{Width: 612, Height: 792, points: true}
+	Legal   = PageSize{Width: 612, Height: 1008, points: true}
+	Tabloid = PageSize{Width: 792, Height: 1224, points: true}
+)
+
+// inPoints returns the size in points, converting it from the unit u unless it is already in points
+func (s PageSize) inPoints(u Unit) PageSize {
+	if !s.points {
+		s.Width, s.Height, s.points = s.Width*float64(u), s.Height*float64(u), true
+	}
+	return s
+}
+
+func (s PageSize) mediaBox() string {
+	return fmt.Sprintf("[ 0 0 %v %v ]", formatNumber(s.Width), formatNumber(s.Height))
+}
+
+// Orientation selects whether a page is printed upright or on its side
+type Orientation int
+
+// Page orientations
+const (
+	Portrait Orientation = iota
+	Landscape
+)
+
+// oriented returns the page size with its dimensions swapped if needed to match the orientation.
+// Portrait sizes are used as given.
+func (s PageSize) oriented(o Orientation) PageSize {
+	if o == Landscape && s.Height > s.Width {
+		s.Width, s.Height = s.Height, s.Width
+	}
+	return s
+}
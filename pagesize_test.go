@@ -0,0 +1,56 @@
+package gopdf
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPageSizeUnit(t *testing.T) {
+	mm := NewPdfDocument(WithoutInitialPage())
+	mm.SetUnit(UnitMM)
+	mm.SetPageSize(PageSize{Width: 210, Height: 297})
+	fromOption := NewPdfDocument(WithUnit(UnitMM), WithPageSize(PageSize{Width: 210, Height: 297}))
+	fromSize := NewPdfDocumentWithPageSize(PageSize{Width: 210, Height: 297}, WithUnit(UnitMM))
+	inches := NewPdfDocument(WithUnit(UnitInch), WithoutInitialPage())
+	for _, c := range []struct {
+		page *PdfPage
+		w, h float64
+	}{
+		{mm.AddPage(), 595.276, 841.89},
+		{mm.AddPageWithSize(PageSize{Width: 100, Height: 50}, Portrait), 283.465, 141.732},
+		{mm.AddPageWithSize(PageSize{Width: 210, Height: 297}, Landscape), 841.89, 595.276},
+		{mm.AddPageWithSize(A4, Portrait), 595, 842},
+		{mm.AddPageWithSize(Letter, Landscape), 792, 612},
+		{fromOption.CurrentPage(), 595.276, 841.89},
+		{fromSize.CurrentPage(), 595.276, 841.89},
+		{inches.AddPageWithSize(PageSize{Width: 8.5, Height: 11}, Portrait), 612, 792},
+		{NewPdfDocumentWithPageSize(A5, WithUnit(UnitCM)).CurrentPage(), 420, 595},
+		{NewPdfDocumentWithPageSize(PageSize{Width: 400, Height: 600}).CurrentPage(), 400, 600},
+	} {
+		if math.Abs(c.page.width-c.w) > 0.001 || math.Abs(c.page.height-c.h) > 0.001 {
+			t.Errorf("page is %v by %v points, want %v by %v", c.page.width, c.page.height, c.w, c.h)
+		}
+	}
+
+	// the default size of the document, which pages of that size inherit
+	data, err := fromSize.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pr, err := parsePDF(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pages, err := pr.pages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	box, _ := pages[0].dict.get("MediaBox").([]any)
+	if box == nil {
+		parent, _ := pr.resolve(pages[0].dict.get("Parent")).(pdfDict)
+		box, _ = parent.get("MediaBox").([]any)
+	}
+	if len(box) != 4 || math.Abs(box[2].(float64)-595.276) > 0.001 || math.Abs(box[3].(float64)-841.89) > 0.001 {
+		t.Errorf("MediaBox %v, want about [ 0 0 595.276 841.89 ]", box)
+	}
+}
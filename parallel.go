@@ -0,0 +1,163 @@
+package gopdf
+
+import (
+	"bytes"
+	"runtime"
+	"sync"
+)
+
+// pageBuild holds what a page built by AddPages adds to the document, so that it can be added once all of the pages
+// have been built
+type pageBuild struct {
+	objects []PdfObjectWriter
+	pages   []*PdfPage // the pages the page's content continues on
+	fields  []*PdfFormField
+}
+
+// AddPages adds n pages to the end of the document and calls build for each of them with its index, from 0, to add
+// its content. The pages are built at the same time on as many goroutines as there are CPUs, so build must not
+// change anything the pages share: fonts, images, templates and the other resources must be added before calling
+// AddPages, and neither the document's current page nor the pages before the new ones can be used. Text that
+// continues onto new pages, in columns for instance, goes on pages added after the page it started on.
+//
+// The objects the pages add, including the graphics states for values of SetAlpha and SetBlendMode that haven't been
+// used before, are numbered in page order once they have all been built, so the document is written the same way
+// whichever order they are built in. The last page becomes the current page.
+func (d *PdfDocument) AddPages(n int, build func(i int, p *PdfPage)) {
+	if n <= 0 {
+		return
+	}
+	pages := make([]*PdfPage, n)
+	for i := range pages {
+		pages[i] = (&pageBuild{}).addPage(d)
+	}
+
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := min(runtime.GOMAXPROCS(0), n); w > 0; w-- {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				build(i, pages[i])
+			}
+		}()
+	}
+	for i := range pages {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	for _, p := range pages {
+		// the build's pages start with the page itself
+		b := p.build
+		for _, page := range b.pages {
+			page.build = nil
+			d.catalog.pdfPages.pages = append(d.catalog.pdfPages.pages, page)
+			d.addObject(page)
+			d.addObject(page.content)
+			page.addPendingExtGStates()
+			d.currentPage = page
+		}
+		for _, o := range b.objects {
+			d.addObject(o)
+		}
+		if len(b.fields) > 0 {
+			form := d.acroForm()
+			form.fields = append(form.fields, b.fields...)
+		}
+	}
+}
+
+// addPage returns a new page of the default size for the build, which is added to the document with the page being
+// built
+func (b *pageBuild) addPage(d *PdfDocument) *PdfPage {
+	size := d.pageSize.oriented(d.orientation)
+	p := d.newPage(size.Width, size.Height, d.margins)
+	p.parent = d.catalog.pdfPages
+	p.content.page = p
+	p.build = b
+	b.pages = append(b.pages, p)
+	return p
+}
+
+// pendingExtGState is a graphics state named in the content of a page being built by AddPages, which is replaced by
+// the document's state for the same parameters once the pages have been built
+type pendingExtGState struct {
+	at int // the offset of its name in the content stream
+	gs *PdfExtGState
+}
+
+// setPendingExtGState selects the graphics state for the current alpha and blend mode of a page being built by
+// AddPages. A state the document doesn't have yet is named with a placeholder, since naming and numbering it now
+// would depend on the order the pages are built in.
+func (p *PdfPage) setPendingExtGState() {
+	d := p.document
+	d.mu.Lock()
+	gs := d.findExtGState(p.extGState)
+	d.mu.Unlock()
+	if gs != nil {
+		p.use(gs)
+		p.content.addStatef("%v gs\r\n", formatName(gs.name))
+		return
+	}
+	c := p.content
+	for _, pending := range c.pendingGS {
+		if pending.gs.extGStateParams == p.extGState {
+			gs = pending.gs
+		}
+	}
+	if gs == nil {
+		gs = &PdfExtGState{name: "GS", extGStateParams: p.extGState}
+	}
+	p.use(gs)
+	start := c.stream.Len()
+	c.addStatef("%v gs\r\n", formatName(gs.name))
+	if at := bytes.Index(c.stream.Bytes()[start:], []byte(formatName(gs.name)+" gs")); at >= 0 {
+		c.pendingGS = append(c.pendingGS, pendingExtGState{start + at, gs})
+	}
+}
+
+// addPendingExtGStates adds the graphics states named by placeholders in the content of a page built by AddPages to
+// the document, in the order the page uses them, and puts their names in place of the placeholders
+func (p *PdfPage) addPendingExtGStates() {
+	c := p.content
+	if len(c.pendingGS) == 0 {
+		return
+	}
+	states := map[*PdfExtGState]*PdfExtGState{}
+	data := c.stream.Bytes()
+	var stream bytes.Buffer
+	last := 0
+	for _, pending := range c.pendingGS {
+		gs := p.document.extGState(pending.gs.extGStateParams)
+		states[pending.gs] = gs
+		stream.Write(data[last:pending.at])
+		stream.WriteString(formatName(gs.name))
+		last = pending.at + len(formatName(pending.gs.name))
+	}
+	stream.Write(data[last:])
+	c.stream = stream
+	c.pendingGS = nil
+
+	used := p.used.extGStates
+	p.used.extGStates = nil
+	for _, gs := range used {
+		if named, ok := states[gs]; ok {
+			gs = named
+		}
+		p.used.extGStates = appendNew(p.used.extGStates, gs)
+	}
+}
+
+// addObject adds an object that belongs to the page, such as an annotation, to the document. While the page is being
+// built by AddPages the object is held by the build instead, to be added after the page.
+func (p *PdfPage) addObject(o PdfObjectWriter) {
+	if p.build == nil {
+		p.document.addObject(o)
+		return
+	}
+	o.setDocument(p.document)
+	p.build.objects = append(p.build.objects, o)
+}
@@ -0,0 +1,60 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// parallelDocument builds 100 pages with AddPages on 8 goroutines, using graphics states that are first used on
+// different pages depending on the order the pages are built in
+func parallelDocument(t *testing.T) []byte {
+	t.Helper()
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(8))
+	d := NewPdfDocument()
+	d.SetDeterministic(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	if _, err := d.AddFont("Helv", Helvetica); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.AddImage("gopher", "gopher.jpg"); err != nil {
+		t.Fatal(err)
+	}
+	d.AddPages(100, func(i int, p *PdfPage) {
+		p.SetFont("Helv")
+		p.Println(fmt.Sprintf("Page %v", i+1))
+		p.WriteWrapped(strings.Repeat("Text that wraps over a few lines of the page. ", 10))
+		p.SetAlpha(float64(i%7+1)/10, 1)
+		p.DrawLine(72, 400, 500, 400)
+		p.SetBlendMode(BlendMode(i % 3))
+		p.SetAlpha(1, 1)
+		if i%10 == 0 {
+			p.DrawImage("gopher", 100, 100)
+		}
+		p.AddLink(72, 72, 100, 20, fmt.Sprintf("https://example.com/%v", i))
+	})
+	data, err := d.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestAddPagesDeterministic(t *testing.T) {
+	first := parallelDocument(t)
+	if problems := validatePDF(first); len(problems) > 0 {
+		t.Fatal(problems)
+	}
+	checkPageResources(t, first)
+	for run := 0; run < 3; run++ {
+		if again := parallelDocument(t); !bytes.Equal(first, again) {
+			at := 0
+			for at < len(first) && at < len(again) && first[at] == again[at] {
+				at++
+			}
+			t.Fatalf("run %v differs at byte %v:\n got %q\nwant %q", run+2, at, excerpt(again, at), excerpt(first, at))
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package gopdf
+
+// MoveTo starts a new path, or a new subpath of the current one, at x, y. The path is built up with LineTo,
+// CurveTo and ClosePath and then drawn with StrokePath, FillPath or PaintPath.
+func (p *PdfPage) MoveTo(x, y float64) {
+	p.content.addGraphicsf("%v m\r\n", formatNumbers(p.point(x, y)))
+	p.inPath = true
+}
+
+// LineTo adds a straight line from the current point to x, y
+func (p *PdfPage) LineTo(x, y float64) error {
+	if !p.inPath {
+		return ErrNoCurrentPoint
+	}
+	p.content.addGraphicsf("%v l\r\n", formatNumbers(p.point(x, y)))
+	return nil
+}
+
+// CurveTo adds a cubic Bézier curve from the current point to x, y using the control points cx1, cy1 and cx2, cy2
+func (p *PdfPage) CurveTo(cx1, cy1, cx2, cy2, x, y float64) error {
+	if !p.inPath {
+		return ErrNoCurrentPoint
+	}
+	cx1, cy1 = p.point(cx1, cy1)
+	cx2, cy2 = p.point(cx2, cy2)
+	x, y = p.point(x, y)
+	p.content.addGraphicsf("%v c\r\n", formatNumbers(cx1, cy1, cx2, cy2, x, y))
+	return nil
+}
+
+// ClosePath joins the current point to the start of the current subpath
+func (p *PdfPage) ClosePath() {
+	if p.inPath {
+		p.content.addGraphics("h\r\n")
+	}
+}
+
+// StrokePath draws the outline of the current path with the stroke colour and line width
+func (p *PdfPage) StrokePath() {
+	p.PaintPath(Stroke)
+}
+
+// FillPath fills the current path with the fill colour, closing any open subpaths
+func (p *PdfPage) FillPath() {
+	p.PaintPath(Fill)
+}
+
+// PaintPath paints the current path according to style and ends it
+func (p *PdfPage) PaintPath(style DrawStyle) {
+	if p.inPath {
+		p.content.addGraphics(style.operator() + "\r\n")
+		p.inPath = false
+	}
+}
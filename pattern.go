@@ -0,0 +1,51 @@
+package gopdf
+
+import "fmt"
+
+// PdfPattern is a tiling pattern, a small tile of drawing repeated to fill shapes
+type PdfPattern struct {
+	PdfObject
+	name          string
+	width, height float64
+	content       *PdfPageContent
+}
+
+// PatternBuilder is what the tile of a pattern is drawn on. It has the same drawing methods as a page, with the
+// origin at the corner of the tile given by the document's coordinate origin.
+type PatternBuilder struct {
+	*PdfPage
+}
+
+// NewTilingPattern adds a pattern to the document made of a tile w by h drawn by calling draw. Tiles are
+// placed side by side from the bottom left corner of the page.
+func (d *PdfDocument) NewTilingPattern(w, h float64, draw func(p *PatternBuilder)) *PdfPattern {
+	w, h = w*float64(d.unit), h*float64(d.unit)
+	p := d.newPage(w, h, [4]float64{})
+	draw(&PatternBuilder{p})
+	p.closeSavedStates()
+	pattern := &PdfPattern{name: fmt.Sprintf("P%v", len(d.resources.patterns)+1), width: w, height: h, content: p.content}
+	d.addObject(pattern)
+	d.resources.patterns = append(d.resources.patterns, pattern)
+	return pattern
+}
+
+// SetFillPattern fills text and shapes drawn afterwards with a pattern instead of a colour, until the fill colour is
+// set again
+func (p *PdfPage) SetFillPattern(pattern *PdfPattern) {
+	p.use(pattern)
+	p.content.addStatef("/Pattern cs\r\n%v scn\r\n", formatName(pattern.name))
+}
+
+func (pt PdfPattern) bytes() []byte {
+	stream := append([]byte(nil), pt.content.stream.Bytes()...)
+	if pt.content.inText {
+		stream = append(stream, "ET\r\n"...)
+	}
+	w, h := formatNumber(pt.width), formatNumber(pt.height)
+	entries := "/Type /Pattern\r\n/PatternType 1\r\n/PaintType 1\r\n/TilingType 1\r\n"
+	entries += fmt.Sprintf("/BBox [ 0 0 %v %v ]\r\n/XStep %v\r\n/YStep %v\r\n/Resources %v\r\n", w, h, w, h, pt.document.resources.objectRef())
+	if !pt.document.compressContent() {
+		return streamObject(pt.id, entries, stream)
+	}
+	return streamObject(pt.id, entries+"/Filter /FlateDecode\r\n", deflate(stream))
+}
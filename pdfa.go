@@ -0,0 +1,111 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// PdfOutputIntent describes the colour space that the colours of the document are intended for, as required by
+// PDF/A, with an embedded ICC profile
+type PdfOutputIntent struct {
+	PdfObject
+	identifier string
+	profile    *PdfICCProfile
+	automatic  bool // added by SetPDFA1b rather than SetOutputIntent
+}
+
+// PdfICCProfile is an embedded ICC colour profile
+type PdfICCProfile struct {
+	PdfObject
+	components int
+	data       []byte
+}
+
+// SetPDFA1b turns PDF/A-1b conformance on or off. A conforming document has XMP metadata and an sRGB output intent,
+// and fonts must be embedded with AddUnicodeFont. Writing the document fails with ErrNotPDFA, listing the problems,
+// if it uses encryption, core fonts, attachments or anything else PDF/A-1b forbids.
+func (d *PdfDocument) SetPDFA1b(enabled bool) {
+	d.pdfA = enabled
+	if !enabled {
+		if intent := d.catalog.outputIntent; intent != nil && intent.automatic {
+			d.catalog.outputIntent = nil
+		}
+		return
+	}
+	if d.catalog.outputIntent == nil {
+		d.setOutputIntent(srgbProfile(), 3, "sRGB IEC61966-2.1")
+		d.catalog.outputIntent.automatic = true
+	}
+	d.SetXMPMetadata(true)
+}
+
+// pdfAViolations returns a description of every way the document fails to conform to PDF/A-1b
+func (d *PdfDocument) pdfAViolations() []string {
+	var violations []string
+	if d.encryption != nil {
+		violations = append(violations, "encryption is not allowed")
+	}
+	for _, font := range d.resources.fonts {
+		if font.unicode == nil {
+			violations = append(violations, fmt.Sprintf("font %v (%v) is not embedded", font.name, font.baseFont))
+		}
+	}
+	if d.formFont != nil {
+		violations = append(violations,
+			"text shown with no font set, form fields or annotations use Helvetica, which isn't embedded")
+	}
+	if len(d.catalog.attachments) > 0 {
+		violations = append(violations, "embedded files are not allowed")
+	}
+	for _, gs := range d.resources.extGStates {
+		if gs.fillAlpha < 1 || gs.strokeAlpha < 1 || gs.blendMode != BlendNormal {
+			violations = append(violations, "transparency is not allowed")
+			break
+		}
+	}
+	if wm := d.resources.watermark; wm != nil && wm.opacity < 1 {
+		violations = append(violations, "transparent watermarks are not allowed")
+	}
+	if d.objectStreams {
+		violations = append(violations, "object streams are not allowed")
+	}
+	if version := d.version(); version > "1.4" {
+		violations = append(violations, fmt.Sprintf("PDF version %v is later than 1.4", version))
+	}
+	return violations
+}
+
+// checkPDFA returns ErrNotPDFA listing the violations when the document should conform to PDF/A-1b but doesn't
+func (d *PdfDocument) checkPDFA() error {
+	if !d.pdfA {
+		return nil
+	}
+	if violations := d.pdfAViolations(); len(violations) > 0 {
+		return fmt.Errorf("%w: %v", ErrNotPDFA, strings.Join(violations, "; "))
+	}
+	return nil
+}
+
+func (o PdfOutputIntent) bytes() []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%v 0 obj\r\n", o.id)
+	fmt.Fprintf(&buf, "<<\r\n")
+	fmt.Fprintf(&buf, "/Type /OutputIntent\r\n")
+	if o.document.pdfA {
+		fmt.Fprintf(&buf, "/S /GTS_PDFA1\r\n")
+	} else {
+		fmt.Fprintf(&buf, "/S /GTS_PDFX\r\n")
+	}
+	fmt.Fprintf(&buf, "/OutputConditionIdentifier %v\r\n", formatTextString(o.identifier))
+	fmt.Fprintf(&buf, "/RegistryName (http://www.color.org)\r\n")
+	fmt.Fprintf(&buf, "/Info %v\r\n", formatTextString(o.identifier))
+	fmt.Fprintf(&buf, "/DestOutputProfile %v\r\n", o.profile.objectRef())
+	fmt.Fprintf(&buf, ">>\r\n")
+	fmt.Fprintf(&buf, "endobj\r\n")
+	return buf.Bytes()
+}
+
+func (p PdfICCProfile) bytes() []byte {
+	return streamObject(p.id, fmt.Sprintf("/N %v\r\n/Filter /FlateDecode\r\n", p.components), deflate(p.data))
+}
@@ -0,0 +1,623 @@
+package gopdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Values read from a PDF file are nil, bool, int, float64, pdfName, pdfString, pdfRef, []any, pdfDict or *pdfStream
+
+// pdfName is a name read from a PDF file, without the slash
+type pdfName string
+
+// pdfString is the bytes of a string read from a PDF file
+type pdfString string
+
+// pdfRef is a reference to an indirect object
+type pdfRef struct {
+	num, gen int
+}
+
+// pdfDict is a dictionary read from a PDF file, keeping the order of its entries
+type pdfDict []pdfEntry
+
+type pdfEntry struct {
+	key   pdfName
+	value any
+}
+
+// get returns the value of key, or nil if the dictionary doesn't have it
+func (d pdfDict) get(key pdfName) any {
+	for _, e := range d {
+		if e.key == key {
+			return e.value
+		}
+	}
+	return nil
+}
+
+// pdfStream is a stream read from a PDF file, with its data still encoded by its filters
+type pdfStream struct {
+	dict pdfDict
+	data []byte
+}
+
+// xrefEntry is where an object is in a PDF file, at an offset or in an object stream
+type xrefEntry struct {
+	offset int  // offset in the file, or the index of the object in its object stream
+	stream int  // number of the object stream holding the object, or 0
+	free   bool // the object has been deleted
+}
+
+// objectStream is the decoded data of an object stream and the offsets in it of the objects it holds
+type objectStream struct {
+	data    []byte
+	offsets map[int]int
+}
+
+// pdfReader reads the objects of a PDF file held in memory
+type pdfReader struct {
+	data      []byte
+	xref      map[int]xrefEntry
+	trailer   pdfDict
+	objects   map[int]any
+	streams   map[int]*objectStream
+	resolving map[int]bool // objects being read, to stop references in a loop
+}
+
+// newPDFReader reads the cross-reference tables and trailer of a PDF file. Encrypted files are rejected.
+func newPDFReader(r io.ReaderAt, size int64) (*pdfReader, error) {
+	data := make([]byte, size)
+	if n, err := r.ReadAt(data, 0); n < len(data) {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPDF, err)
+	}
+	pr, err := parsePDF(data)
+	if err != nil {
+		return nil, err
+	}
+	if pr.trailer.get("Encrypt") != nil {
+		return nil, ErrEncryptedPDF
+	}
+	return pr, nil
+}
+
+// parsePDF reads the cross-reference tables and trailer of a PDF file held in data, encrypted or not
+func parsePDF(data []byte) (*pdfReader, error) {
+	if !bytes.Contains(data[:min(len(data), 1024)], []byte("%PDF-")) {
+		return nil, fmt.Errorf("%w: no PDF header", ErrInvalidPDF)
+	}
+	pr := &pdfReader{data: data, xref: map[int]xrefEntry{}, objects: map[int]any{}, streams: map[int]*objectStream{},
+		resolving: map[int]bool{}}
+	at := bytes.LastIndex(data, []byte("startxref"))
+	if at < 0 {
+		return nil, fmt.Errorf("%w: no startxref", ErrInvalidPDF)
+	}
+	p := &pdfParser{data: data, pos: at + len("startxref")}
+	offset, ok := p.integer()
+	// follow the chain of cross-reference sections from the newest, whose entries replace those of older ones
+	for seen := map[int]bool{}; ok && !seen[offset]; {
+		seen[offset] = true
+		trailer, err := pr.readXref(offset)
+		if err != nil {
+			return nil, err
+		}
+		if pr.trailer == nil {
+			pr.trailer = trailer
+		}
+		offset, ok = trailer.get("Prev").(int)
+	}
+	if pr.trailer == nil {
+		return nil, fmt.Errorf("%w: no cross-reference table", ErrInvalidPDF)
+	}
+	return pr, nil
+}
+
+// readXref reads the cross-reference table or stream at offset and returns its trailer
+func (pr *pdfReader) readXref(offset int) (pdfDict, error) {
+	if offset < 0 || offset >= len(pr.data) {
+		return nil, fmt.Errorf("%w: cross-reference table at %v is outside the file", ErrInvalidPDF, offset)
+	}
+	p := &pdfParser{data: pr.data, pos: offset}
+	if p.keyword() != "xref" {
+		return pr.readXrefStream(offset)
+	}
+	for {
+		p.skipSpace()
+		save := p.pos
+		if p.keyword() == "trailer" {
+			break
+		}
+		p.pos = save
+		start, ok1 := p.integer()
+		count, ok2 := p.integer()
+		if !ok1 || !ok2 {
+			return nil, fmt.Errorf("%w: bad cross-reference table at %v", ErrInvalidPDF, offset)
+		}
+		for i := 0; i < count; i++ {
+			at, _ := p.integer()
+			p.integer()
+			kind := p.keyword()
+			if _, ok := pr.xref[start+i]; !ok {
+				pr.xref[start+i] = xrefEntry{offset: at, free: kind != "n"}
+			}
+		}
+	}
+	v, err := p.value()
+	trailer, ok := v.(pdfDict)
+	if err != nil || !ok {
+		return nil, fmt.Errorf("%w: bad trailer at %v", ErrInvalidPDF, offset)
+	}
+	// a file saved for both old and new readers also has a cross-reference stream with the objects in object streams
+	if stm, ok := trailer.get("XRefStm").(int); ok {
+		if _, err := pr.readXrefStream(stm); err != nil {
+			return nil, err
+		}
+	}
+	return trailer, nil
+}
+
+// readXrefStream reads the cross-reference stream at offset and returns its dictionary, which is the trailer
+func (pr *pdfReader) readXrefStream(offset int) (pdfDict, error) {
+	v, err := pr.objectAt(offset)
+	if err != nil {
+		return nil, err
+	}
+	s, ok := v.(*pdfStream)
+	if !ok || s.dict.get("Type") != pdfName("XRef") {
+		return nil, fmt.Errorf("%w: no cross-reference table at %v", ErrInvalidPDF, offset)
+	}
+	data, err := pr.decode(s)
+	if err != nil {
+		return nil, err
+	}
+	var w [3]int
+	widths, _ := s.dict.get("W").([]any)
+	for i := 0; i < 3 && i < len(widths); i++ {
+		w[i], _ = widths[i].(int)
+	}
+	size, _ := s.dict.get("Size").(int)
+	index := []any{0, size}
+	if i, ok := s.dict.get("Index").([]any); ok {
+		index = i
+	}
+	field := func(n int) int {
+		v := 0
+		for ; n > 0 && len(data) > 0; n-- {
+			v = v<<8 | int(data[0])
+			data = data[1:]
+		}
+		return v
+	}
+	for i := 0; i+1 < len(index); i += 2 {
+		start, _ := index[i].(int)
+		count, _ := index[i+1].(int)
+		for j := 0; j < count && len(data) > 0; j++ {
+			kind := 1
+			if w[0] > 0 {
+				kind = field(w[0])
+			}
+			f2, f3 := field(w[1]), field(w[2])
+			if _, ok := pr.xref[start+j]; ok {
+				continue
+			}
+			switch kind {
+			case 0:
+				pr.xref[start+j] = xrefEntry{free: true}
+			case 1:
+				pr.xref[start+j] = xrefEntry{offset: f2}
+			case 2:
+				pr.xref[start+j] = xrefEntry{offset: f3, stream: f2}
+			}
+		}
+	}
+	return s.dict, nil
+}
+
+// resolve returns the object a reference refers to, or v itself when it isn't a reference. Objects that are
+// missing or can't be read are null.
+func (pr *pdfReader) resolve(v any) any {
+	if ref, ok := v.(pdfRef); ok {
+		v, _ = pr.object(ref.num)
+	}
+	return v
+}
+
+// object returns the indirect object with the given number
+func (pr *pdfReader) object(num int) (any, error) {
+	if v, ok := pr.objects[num]; ok {
+		return v, nil
+	}
+	e, ok := pr.xref[num]
+	if !ok || e.free || pr.resolving[num] {
+		return nil, nil
+	}
+	pr.resolving[num] = true
+	defer delete(pr.resolving, num)
+	var v any
+	var err error
+	if e.stream != 0 {
+		v, err = pr.objectInStream(e.stream, num)
+	} else {
+		v, err = pr.objectAt(e.offset)
+	}
+	if err != nil {
+		return nil, err
+	}
+	pr.objects[num] = v
+	return v, nil
+}
+
+// objectAt reads the indirect object at offset
+func (pr *pdfReader) objectAt(offset int) (any, error) {
+	if offset < 0 || offset >= len(pr.data) {
+		return nil, fmt.Errorf("%w: object at %v is outside the file", ErrInvalidPDF, offset)
+	}
+	p := &pdfParser{data: pr.data, pos: offset}
+	_, ok1 := p.integer()
+	_, ok2 := p.integer()
+	if !ok1 || !ok2 || p.keyword() != "obj" {
+		return nil, fmt.Errorf("%w: no object at %v", ErrInvalidPDF, offset)
+	}
+	v, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+	dict, ok := v.(pdfDict)
+	save := p.pos
+	if !ok || p.keyword() != "stream" {
+		p.pos = save
+		return v, nil
+	}
+	// the data starts after the end of line following the keyword
+	if p.pos < len(p.data) && p.data[p.pos] == '\r' {
+		p.pos++
+	}
+	if p.pos < len(p.data) && p.data[p.pos] == '\n' {
+		p.pos++
+	}
+	start := p.pos
+	length, ok := pr.resolve(dict.get("Length")).(int)
+	end := start + length
+	if !ok || length < 0 || end > len(p.data) ||
+		!bytes.HasPrefix(bytes.TrimLeft(p.data[end:], "\r\n \t"), []byte("endstream")) {
+		// the length is wrong, so the data runs up to the end of line before endstream
+		at := bytes.Index(p.data[start:], []byte("endstream"))
+		if at < 0 {
+			return nil, fmt.Errorf("%w: stream at %v has no end", ErrInvalidPDF, offset)
+		}
+		end = start + at
+		if end > start && p.data[end-1] == '\n' {
+			end--
+		}
+		if end > start && p.data[end-1] == '\r' {
+			end--
+		}
+	}
+	return &pdfStream{dict: dict, data: p.data[start:end]}, nil
+}
+
+// objectInStream reads an object from the object stream with the number stream
+func (pr *pdfReader) objectInStream(stream, num int) (any, error) {
+	stm, ok := pr.streams[stream]
+	if !ok {
+		v, err := pr.object(stream)
+		if err != nil {
+			return nil, err
+		}
+		s, ok := v.(*pdfStream)
+		if !ok {
+			return nil, fmt.Errorf("%w: object stream %v is missing", ErrInvalidPDF, stream)
+		}
+		data, err := pr.decode(s)
+		if err != nil {
+			return nil, err
+		}
+		n, _ := s.dict.get("N").(int)
+		first, _ := s.dict.get("First").(int)
+		stm = &objectStream{data: data, offsets: map[int]int{}}
+		p := &pdfParser{data: data}
+		for i := 0; i < n; i++ {
+			objNum, ok1 := p.integer()
+			at, ok2 := p.integer()
+			if !ok1 || !ok2 {
+				break
+			}
+			stm.offsets[objNum] = first + at
+		}
+		pr.streams[stream] = stm
+	}
+	at, ok := stm.offsets[num]
+	if !ok || at >= len(stm.data) {
+		return nil, nil
+	}
+	return (&pdfParser{data: stm.data, pos: at}).value()
+}
+
+// decode returns the data of a stream with its filters undone. Only the Flate filter is supported, with or without
+// PNG predictors.
+func (pr *pdfReader) decode(s *pdfStream) ([]byte, error) {
+	filters := pr.resolve(s.dict.get("Filter"))
+	params := pr.resolve(s.dict.get("DecodeParms"))
+	if f, ok := filters.(pdfName); ok {
+		filters, params = []any{f}, []any{params}
+	}
+	list, _ := filters.([]any)
+	paramList, _ := params.([]any)
+	data := s.data
+	for i, f := range list {
+		if pr.resolve(f) != pdfName("FlateDecode") {
+			return nil, fmt.Errorf("%w: unsupported filter %v", ErrInvalidPDF, f)
+		}
+		zr, err := zlib.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPDF, err)
+		}
+		decoded, err := io.ReadAll(zr)
+		if err != nil && len(decoded) == 0 {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPDF, err)
+		}
+		var dp pdfDict
+		if i < len(paramList) {
+			dp, _ = pr.resolve(paramList[i]).(pdfDict)
+		}
+		if data, err = unpredict(decoded, dp); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// unpredict undoes the PNG predictors of Flate encoded data, given the stream's decode parameters
+func unpredict(data []byte, params pdfDict) ([]byte, error) {
+	predictor, _ := params.get("Predictor").(int)
+	if predictor <= 1 {
+		return data, nil
+	}
+	if predictor < 10 {
+		return nil, fmt.Errorf("%w: unsupported predictor %v", ErrInvalidPDF, predictor)
+	}
+	param := func(key pdfName, def int) int {
+		if v, ok := params.get(key).(int); ok && v > 0 {
+			return v
+		}
+		return def
+	}
+	bpp := max(param("Colors", 1)*param("BitsPerComponent", 8)/8, 1)
+	rowLen := (param("Columns", 1)*param("Colors", 1)*param("BitsPerComponent", 8) + 7) / 8
+	var out []byte
+	prev := make([]byte, rowLen)
+	for len(data) > rowLen {
+		filter, row := data[0], data[1:rowLen+1]
+		data = data[rowLen+1:]
+		for i := range row {
+			var left, upLeft byte
+			if i >= bpp {
+				left, upLeft = row[i-bpp], prev[i-bpp]
+			}
+			up := prev[i]
+			switch filter {
+			case 1:
+				row[i] += left
+			case 2:
+				row[i] += up
+			case 3:
+				row[i] += byte((int(left) + int(up)) / 2)
+			case 4:
+				row[i] += paeth(left, up, upLeft)
+			}
+		}
+		out = append(out, row...)
+		prev = row
+	}
+	return out, nil
+}
+
+// paeth returns whichever of a, b and c is closest to a + b - c
+func paeth(a, b, c byte) byte {
+	p := int(a) + int(b) - int(c)
+	pa, pb, pc := abs(p-int(a)), abs(p-int(b)), abs(p-int(c))
+	switch {
+	case pa <= pb && pa <= pc:
+		return a
+	case pb <= pc:
+		return b
+	}
+	return c
+}
+
+// pdfParser reads values from PDF syntax
+type pdfParser struct {
+	data []byte
+	pos  int
+}
+
+// isPDFSpace reports whether c is white space in PDF syntax
+func isPDFSpace(c byte) bool {
+	return c == 0 || c == '\t' || c == '\n' || c == '\f' || c == '\r' || c == ' '
+}
+
+// isPDFDelimiter reports whether c ends a token in PDF syntax
+func isPDFDelimiter(c byte) bool {
+	return isPDFSpace(c) || bytes.IndexByte([]byte("()<>[]{}/%"), c) >= 0
+}
+
+// skipSpace skips white space and comments
+func (p *pdfParser) skipSpace() {
+	for p.pos < len(p.data) {
+		switch c := p.data[p.pos]; {
+		case c == '%':
+			for p.pos < len(p.data) && p.data[p.pos] != '\r' && p.data[p.pos] != '\n' {
+				p.pos++
+			}
+		case isPDFSpace(c):
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+// keyword reads a token made of regular characters, such as a number or a keyword
+func (p *pdfParser) keyword() string {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.data) && !isPDFDelimiter(p.data[p.pos]) {
+		p.pos++
+	}
+	return string(p.data[start:p.pos])
+}
+
+// integer reads an integer, reporting false if the next token isn't one
+func (p *pdfParser) integer() (int, bool) {
+	v, err := strconv.Atoi(p.keyword())
+	return v, err == nil
+}
+
+// value reads the next value
+func (p *pdfParser) value() (any, error) {
+	p.skipSpace()
+	if p.pos >= len(p.data) {
+		return nil, fmt.Errorf("%w: unexpected end of data", ErrInvalidPDF)
+	}
+	switch p.data[p.pos] {
+	case '/':
+		p.pos++
+		return p.name(), nil
+	case '(':
+		s, end := parseLiteralString(p.data, p.pos)
+		p.pos = end
+		return pdfString(s), nil
+	case '<':
+		if p.pos+1 < len(p.data) && p.data[p.pos+1] == '<' {
+			return p.dict()
+		}
+		s, end := parseHexString(p.data, p.pos)
+		p.pos = end
+		return pdfString(s), nil
+	case '[':
+		p.pos++
+		var array []any
+		for {
+			p.skipSpace()
+			if p.pos < len(p.data) && p.data[p.pos] == ']' {
+				p.pos++
+				return array, nil
+			}
+			v, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, v)
+		}
+	}
+	start := p.pos
+	token := p.keyword()
+	switch token {
+	case "true", "false":
+		return token == "true", nil
+	case "null":
+		return nil, nil
+	}
+	if n, err := strconv.Atoi(token); err == nil {
+		// an integer followed by another and R is a reference
+		save := p.pos
+		if gen, ok := p.integer(); ok && p.keyword() == "R" {
+			return pdfRef{n, gen}, nil
+		}
+		p.pos = save
+		return n, nil
+	}
+	if f, err := strconv.ParseFloat(token, 64); err == nil {
+		return f, nil
+	}
+	return nil, fmt.Errorf("%w: unexpected %q at %v", ErrInvalidPDF, token, start)
+}
+
+// name reads a name after its slash, decoding #xx escapes
+func (p *pdfParser) name() pdfName {
+	var name []byte
+	for p.pos < len(p.data) && !isPDFDelimiter(p.data[p.pos]) {
+		c := p.data[p.pos]
+		if c == '#' && p.pos+2 < len(p.data) {
+			if v, err := strconv.ParseUint(string(p.data[p.pos+1:p.pos+3]), 16, 8); err == nil {
+				name = append(name, byte(v))
+				p.pos += 3
+				continue
+			}
+		}
+		name = append(name, c)
+		p.pos++
+	}
+	return pdfName(name)
+}
+
+// dict reads a dictionary
+func (p *pdfParser) dict() (pdfDict, error) {
+	p.pos += 2
+	dict := pdfDict{}
+	for {
+		p.skipSpace()
+		if bytes.HasPrefix(p.data[p.pos:], []byte(">>")) {
+			p.pos += 2
+			return dict, nil
+		}
+		key, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		name, ok := key.(pdfName)
+		if !ok {
+			return nil, fmt.Errorf("%w: dictionary key %v is not a name", ErrInvalidPDF, key)
+		}
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		dict = append(dict, pdfEntry{name, v})
+	}
+}
+
+// formatValue formats a value read from a PDF file, with strings written in hex
+func formatValue(v any) string {
+	switch v := v.(type) {
+	case bool:
+		return strconv.FormatBool(v)
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case pdfName:
+		return formatName(string(v))
+	case pdfString:
+		return fmt.Sprintf("<%X>", string(v))
+	case pdfRef:
+		return fmt.Sprintf("%v %v R", v.num, v.gen)
+	case importedRef:
+		if v.o.id == 0 {
+			return "null"
+		}
+		return v.o.objectRef()
+	case Ref:
+		return v.String()
+	case []any:
+		var sb bytes.Buffer
+		sb.WriteString("[ ")
+		for _, item := range v {
+			sb.WriteString(formatValue(item) + " ")
+		}
+		sb.WriteString("]")
+		return sb.String()
+	case pdfDict:
+		var sb bytes.Buffer
+		sb.WriteString("<< ")
+		for _, e := range v {
+			sb.WriteString(formatName(string(e.key)) + " " + formatValue(e.value) + " ")
+		}
+		sb.WriteString(">>")
+		return sb.String()
+	}
+	return "null"
+}
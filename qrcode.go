@@ -0,0 +1,467 @@
+package gopdf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ECLevel is the error correction level of a QR code, the proportion of it that can be damaged and still be read
+type ECLevel int
+
+// Error correction levels, recovering about 7%, 15%, 25% and 30% of the code
+const (
+	ECLevelL ECLevel = iota
+	ECLevelM
+	ECLevelQ
+	ECLevelH
+)
+
+// qrECCodewords is the number of error correction codewords in each block, by level and version
+var qrECCodewords = [4][41]int{
+	{0, 7, 10, 15, 20, 26, 18, 20, 24, 30, 18, 20, 24, 26, 30, 22, 24, 28, 30, 28, 28, 28, 28, 30, 30, 26, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{0, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26, 30, 22, 22, 24, 24, 28, 28, 26, 26, 26, 26, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28},
+	{0, 13, 22, 18, 26, 18, 24, 18, 22, 20, 24, 28, 26, 24, 20, 30, 24, 28, 28, 26, 30, 28, 30, 30, 30, 30, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{0, 17, 28, 22, 16, 22, 28, 26, 26, 24, 28, 24, 28, 22, 24, 24, 30, 28, 28, 26, 28, 30, 24, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+}
+
+// qrBlocks is the number of error correction blocks, by level and version
+var qrBlocks = [4][41]int{
+	{0, 1, 1, 1, 1, 1, 2, 2, 2, 2, 4, 4, 4, 4, 4, 6, 6, 6, 6, 7, 8, 8, 9, 9, 10, 12, 12, 12, 13, 14, 15, 16, 17, 18, 19, 19, 20, 21, 22, 24, 25},
+	{0, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5, 5, 8, 9, 9, 10, 10, 11, 13, 14, 16, 17, 17, 18, 20, 21, 23, 25, 26, 28, 29, 31, 33, 35, 37, 38, 40, 43, 45, 47, 49},
+	{0, 1, 1, 2, 2, 4, 4, 6, 6, 8, 8, 8, 10, 12, 16, 12, 17, 16, 18, 21, 20, 23, 23, 25, 27, 29, 34, 34, 35, 38, 40, 43, 45, 48, 51, 53, 56, 59, 62, 65, 68},
+	{0, 1, 1, 2, 4, 4, 4, 5, 6, 8, 8, 11, 11, 16, 16, 18, 16, 19, 21, 25, 25, 25, 34, 30, 32, 35, 37, 40, 42, 45, 48, 51, 54, 57, 60, 63, 66, 70, 74, 77, 81},
+}
+
+// qrCode is the grid of modules of a QR code, true for dark, along with which modules are part of the fixed
+// patterns rather than data
+type qrCode struct {
+	size     int
+	modules  [][]bool
+	function [][]bool
+}
+
+// SetQRQuietZone sets the width of the light border around QR codes in modules. Scanners need at least 4, which is
+// the default.
+func (p *PdfPage) SetQRQuietZone(modules int) {
+	p.qrQuietZone = modules
+}
+
+// DrawQRCode draws data as a square QR code with sides of length size, including the quiet zone, with its corner at
+// x, y. The smallest version that holds the data at the error correction level is used. Data too long for a version
+// 40 code returns ErrInvalidBarcode.
+func (p *PdfPage) DrawQRCode(data string, x, y, size float64, ecLevel ECLevel) error {
+	if ecLevel < ECLevelL || ecLevel > ECLevelH {
+		return fmt.Errorf("%w: unknown error correction level %v", ErrInvalidBarcode, ecLevel)
+	}
+	qr, err := encodeQR([]byte(data), ecLevel)
+	if err != nil {
+		return err
+	}
+	x, y, size, _ = p.rect(x, y, size, size)
+	module := size / float64(qr.size+2*p.qrQuietZone)
+	left := x + float64(p.qrQuietZone)*module
+	top := y + size - float64(p.qrQuietZone)*module
+	var sb strings.Builder
+	for row := 0; row < qr.size; row++ {
+		// each run of dark modules in a row is one rectangle, so there are no seams between them
+		for col := 0; col < qr.size; {
+			if !qr.modules[row][col] {
+				col++
+				continue
+			}
+			start := col
+			for col < qr.size && qr.modules[row][col] {
+				col++
+			}
+			fmt.Fprintf(&sb, "%v %v %v %v re\r\n", formatNumber(left+float64(start)*module),
+				formatNumber(top-float64(row+1)*module), formatNumber(float64(col-start)*module), formatNumber(module))
+		}
+	}
+	p.content.addGraphics(sb.String() + "f\r\n")
+	return nil
+}
+
+// encodeQR encodes data in byte mode as a QR code of the smallest version that holds it
+func encodeQR(data []byte, level ECLevel) (*qrCode, error) {
+	version := 1
+	for ; version <= 40; version++ {
+		countBits := 8
+		if version > 9 {
+			countBits = 16
+		}
+		if len(data) < 1<<countBits && 4+countBits+8*len(data) <= 8*qrDataCodewords(version, level) {
+			break
+		}
+	}
+	if version > 40 {
+		return nil, fmt.Errorf("%w: %v bytes is too long for a QR code", ErrInvalidBarcode, len(data))
+	}
+
+	// the mode indicator, character count and data, then a terminator and padding to fill the capacity
+	var bits []bool
+	appendBits := func(v, n int) {
+		for i := n - 1; i >= 0; i-- {
+			bits = append(bits, v>>i&1 == 1)
+		}
+	}
+	appendBits(4, 4)
+	if version > 9 {
+		appendBits(len(data), 16)
+	} else {
+		appendBits(len(data), 8)
+	}
+	for _, b := range data {
+		appendBits(int(b), 8)
+	}
+	capacity := 8 * qrDataCodewords(version, level)
+	appendBits(0, min(4, capacity-len(bits)))
+	appendBits(0, (8-len(bits)%8)%8)
+	codewords := make([]byte, 0, capacity/8)
+	for i := 0; i < len(bits); i += 8 {
+		var b byte
+		for _, bit := range bits[i : i+8] {
+			b <<= 1
+			if bit {
+				b |= 1
+			}
+		}
+		codewords = append(codewords, b)
+	}
+	for pad := byte(0xEC); len(codewords) < capacity/8; pad ^= 0xEC ^ 0x11 {
+		codewords = append(codewords, pad)
+	}
+
+	qr := newQRCode(version)
+	qr.drawCodewords(qrInterleave(codewords, version, level))
+	// use the mask with the lowest penalty
+	best, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		qr.applyMask(mask)
+		qr.drawFormat(level, mask)
+		if penalty := qr.penalty(); bestPenalty < 0 || penalty < bestPenalty {
+			best, bestPenalty = mask, penalty
+		}
+		qr.applyMask(mask)
+	}
+	qr.applyMask(best)
+	qr.drawFormat(level, best)
+	return qr, nil
+}
+
+// qrDataCodewords returns the number of data codewords in a QR code of the given version and level
+func qrDataCodewords(version int, level ECLevel) int {
+	return qrRawModules(version)/8 - qrECCodewords[level][version]*qrBlocks[level][version]
+}
+
+// qrRawModules returns the number of modules available for data and error correction in a QR code of the given
+// version, leaving out the function patterns and format and version information
+func qrRawModules(version int) int {
+	modules := (16*version+128)*version + 64
+	if version >= 2 {
+		align := version/7 + 2
+		modules -= (25*align-10)*align - 55
+		if version >= 7 {
+			modules -= 36
+		}
+	}
+	return modules
+}
+
+// qrAlignmentPositions returns the rows and columns of the centres of the alignment patterns of a version
+func qrAlignmentPositions(version int) []int {
+	if version == 1 {
+		return nil
+	}
+	align := version/7 + 2
+	step := (version*8 + align*3 + 5) / (align*4 - 4) * 2
+	positions := make([]int, align)
+	positions[0] = 6
+	for i, pos := align-1, version*4+10; i >= 1; i, pos = i-1, pos-step {
+		positions[i] = pos
+	}
+	return positions
+}
+
+// qrInterleave splits the data codewords into blocks, adds the error correction codewords to each and interleaves
+// the blocks
+func qrInterleave(data []byte, version int, level ECLevel) []byte {
+	blocks := qrBlocks[level][version]
+	ecLen := qrECCodewords[level][version]
+	raw := qrRawModules(version) / 8
+	short := blocks - raw%blocks
+	shortLen := raw / blocks
+	divisor := rsDivisor(ecLen)
+	var all [][]byte
+	for i, at := 0, 0; i < blocks; i++ {
+		n := shortLen - ecLen
+		if i >= short {
+			n++
+		}
+		block := append([]byte(nil), data[at:at+n]...)
+		at += n
+		ec := rsRemainder(block, divisor)
+		if i < short {
+			// a placeholder so that all the blocks are the same length
+			block = append(block, 0)
+		}
+		all = append(all, append(block, ec...))
+	}
+	result := make([]byte, 0, raw)
+	for i := range all[0] {
+		for j, block := range all {
+			if i != shortLen-ecLen || j >= short {
+				result = append(result, block[i])
+			}
+		}
+	}
+	return result
+}
+
+// rsDivisor returns the generator polynomial for Reed-Solomon error correction with the given number of codewords,
+// without its leading coefficient of 1
+func rsDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := range result {
+			result[j] = gfMultiply(result[j], root)
+			if j+1 < degree {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMultiply(root, 2)
+	}
+	return result
+}
+
+// rsRemainder returns the Reed-Solomon error correction codewords for data
+func rsRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i, d := range divisor {
+			result[i] ^= gfMultiply(d, factor)
+		}
+	}
+	return result
+}
+
+// gfMultiply multiplies in the Galois field GF(2^8) with the QR code polynomial 0x11D
+func gfMultiply(x, y byte) byte {
+	z := 0
+	for i := 7; i >= 0; i-- {
+		z = z<<1 ^ (z>>7)*0x11D
+		z ^= int(y>>i&1) * int(x)
+	}
+	return byte(z)
+}
+
+// newQRCode returns a QR code of the given version with its function patterns drawn
+func newQRCode(version int) *qrCode {
+	size := version*4 + 17
+	qr := &qrCode{size: size, modules: make([][]bool, size), function: make([][]bool, size)}
+	for i := range qr.modules {
+		qr.modules[i] = make([]bool, size)
+		qr.function[i] = make([]bool, size)
+	}
+	for i := 0; i < size; i++ {
+		qr.set(6, i, i%2 == 0)
+		qr.set(i, 6, i%2 == 0)
+	}
+	for _, c := range [][2]int{{3, 3}, {size - 4, 3}, {3, size - 4}} {
+		// the finder pattern and the light separator around it
+		for dy := -4; dy <= 4; dy++ {
+			for dx := -4; dx <= 4; dx++ {
+				x, y := c[0]+dx, c[1]+dy
+				if x >= 0 && x < size && y >= 0 && y < size {
+					dist := max(abs(dx), abs(dy))
+					qr.set(x, y, dist != 2 && dist != 4)
+				}
+			}
+		}
+	}
+	positions := qrAlignmentPositions(version)
+	for i, cx := range positions {
+		for j, cy := range positions {
+			// the corners with finder patterns
+			if i == 0 && j == 0 || i == 0 && j == len(positions)-1 || i == len(positions)-1 && j == 0 {
+				continue
+			}
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					qr.set(cx+dx, cy+dy, max(abs(dx), abs(dy)) != 1)
+				}
+			}
+		}
+	}
+	// reserve the format information, which depends on the mask
+	qr.drawFormat(ECLevelL, 0)
+	if version >= 7 {
+		rem := version
+		for i := 0; i < 12; i++ {
+			rem = rem<<1 ^ (rem>>11)*0x1F25
+		}
+		bits := version<<12 | rem
+		for i := 0; i < 18; i++ {
+			a, b := size-11+i%3, i/3
+			qr.set(a, b, bits>>i&1 == 1)
+			qr.set(b, a, bits>>i&1 == 1)
+		}
+	}
+	return qr
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// set sets the module at column x and row y as part of a function pattern
+func (qr *qrCode) set(x, y int, dark bool) {
+	qr.modules[y][x] = dark
+	qr.function[y][x] = true
+}
+
+// drawFormat draws both copies of the format information for the level and mask
+func (qr *qrCode) drawFormat(level ECLevel, mask int) {
+	data := [4]int{1, 0, 3, 2}[level]<<3 | mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+	bit := func(i int) bool { return bits>>i&1 == 1 }
+	for i := 0; i <= 5; i++ {
+		qr.set(8, i, bit(i))
+	}
+	qr.set(8, 7, bit(6))
+	qr.set(8, 8, bit(7))
+	qr.set(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		qr.set(14-i, 8, bit(i))
+	}
+	for i := 0; i < 8; i++ {
+		qr.set(qr.size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		qr.set(8, qr.size-15+i, bit(i))
+	}
+	// always dark
+	qr.set(8, qr.size-8, true)
+}
+
+// drawCodewords places the codewords in the modules that aren't function patterns, in two module wide columns
+// zigzagging up and down from the bottom right corner
+func (qr *qrCode) drawCodewords(codewords []byte) {
+	i := 0
+	for right := qr.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			// skip the vertical timing pattern
+			right = 5
+		}
+		for vert := 0; vert < qr.size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = qr.size - 1 - vert
+				}
+				if !qr.function[y][x] && i < len(codewords)*8 {
+					qr.modules[y][x] = codewords[i>>3]>>(7-i&7)&1 == 1
+					i++
+				}
+			}
+		}
+	}
+}
+
+// applyMask inverts the data modules selected by mask, so applying it twice undoes it
+func (qr *qrCode) applyMask(mask int) {
+	for y := 0; y < qr.size; y++ {
+		for x := 0; x < qr.size; x++ {
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if invert && !qr.function[y][x] {
+				qr.modules[y][x] = !qr.modules[y][x]
+			}
+		}
+	}
+}
+
+// penalty scores how hard the code is to scan, for choosing the mask: long runs of one colour, 2 by 2 blocks,
+// patterns that look like finder patterns and an imbalance of dark and light modules all add to it
+func (qr *qrCode) penalty() int {
+	penalty := 0
+	at := func(line, i int, vertical bool) bool {
+		if vertical {
+			return qr.modules[i][line]
+		}
+		return qr.modules[line][i]
+	}
+	finder := [2][11]bool{
+		{true, false, true, true, true, false, true, false, false, false, false},
+		{false, false, false, false, true, false, true, true, true, false, true},
+	}
+	for _, vertical := range []bool{false, true} {
+		for line := 0; line < qr.size; line++ {
+			run := 1
+			for i := 1; i <= qr.size; i++ {
+				if i < qr.size && at(line, i, vertical) == at(line, i-1, vertical) {
+					run++
+					continue
+				}
+				if run >= 5 {
+					penalty += 3 + run - 5
+				}
+				run = 1
+			}
+			for i := 0; i+11 <= qr.size; i++ {
+				for _, pattern := range finder {
+					match := true
+					for k, dark := range pattern {
+						match = match && at(line, i+k, vertical) == dark
+					}
+					if match {
+						penalty += 40
+					}
+				}
+			}
+		}
+	}
+	dark := 0
+	for y := 0; y < qr.size; y++ {
+		for x := 0; x < qr.size; x++ {
+			c := qr.modules[y][x]
+			if c {
+				dark++
+			}
+			if x+1 < qr.size && y+1 < qr.size && c == qr.modules[y][x+1] && c == qr.modules[y+1][x] && c == qr.modules[y+1][x+1] {
+				penalty += 3
+			}
+		}
+	}
+	total := qr.size * qr.size
+	return penalty + (abs(dark*20-total*10)+total-1)/total*10 - 10
+}
@@ -0,0 +1,270 @@
+package gopdf
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// qrTestAlignment is the centres of the alignment patterns of the versions used in the tests, from the QR code
+// specification
+var qrTestAlignment = map[int][]int{
+	2: {6, 18}, 3: {6, 22}, 4: {6, 26}, 5: {6, 30}, 6: {6, 34}, 7: {6, 22, 38}, 8: {6, 24, 42},
+	9: {6, 26, 46}, 10: {6, 28, 50},
+}
+
+// qrTestVersionInfo is the version information of the versions of 7 and above used in the tests, from the QR code
+// specification
+var qrTestVersionInfo = map[int]int{7: 0x07C94, 8: 0x085BC, 10: 0x0A4D3}
+
+// readQR decodes a QR code as a scanner would, checking the format and version information, the finder patterns
+// and the Reed-Solomon codewords of each block, and returns its level, version and data
+func readQR(t *testing.T, modules [][]bool) (ECLevel, int, []byte) {
+	t.Helper()
+	size := len(modules)
+	version := (size - 17) / 4
+	at := func(x, y int) int {
+		if modules[y][x] {
+			return 1
+		}
+		return 0
+	}
+	for _, c := range [][2]int{{0, 0}, {size - 7, 0}, {0, size - 7}} {
+		for y := 0; y < 7; y++ {
+			for x := 0; x < 7; x++ {
+				d := max(abs(x-3), abs(y-3))
+				if want := d != 2; modules[c[1]+y][c[0]+x] != want {
+					t.Fatalf("finder pattern at %v is wrong at %v, %v", c, x, y)
+				}
+			}
+		}
+	}
+
+	// both copies of the format information, which must be the same valid BCH codeword
+	var format, copy2 int
+	for i := 0; i <= 5; i++ {
+		format |= at(8, i) << i
+	}
+	format |= at(8, 7)<<6 | at(8, 8)<<7 | at(7, 8)<<8
+	for i := 9; i < 15; i++ {
+		format |= at(14-i, 8) << i
+	}
+	for i := 0; i < 8; i++ {
+		copy2 |= at(size-1-i, 8) << i
+	}
+	for i := 8; i < 15; i++ {
+		copy2 |= at(8, size-15+i) << i
+	}
+	if format != copy2 {
+		t.Fatalf("format information copies differ: %015b, %015b", format, copy2)
+	}
+	format ^= 0x5412
+	rem := format
+	for i := 14; i >= 10; i-- {
+		if rem>>i&1 == 1 {
+			rem ^= 0x537 << (i - 10)
+		}
+	}
+	if rem != 0 {
+		t.Fatalf("format information %015b isn't a BCH codeword", format)
+	}
+	level := [4]ECLevel{ECLevelM, ECLevelL, ECLevelH, ECLevelQ}[format>>13]
+	mask := format >> 10 & 7
+	if !modules[size-8][8] {
+		t.Fatal("the dark module is light")
+	}
+
+	if want, ok := qrTestVersionInfo[version]; ok {
+		for i := 0; i < 18; i++ {
+			a, b := size-11+i%3, i/3
+			if at(a, b) != want>>i&1 || at(b, a) != want>>i&1 {
+				t.Fatalf("version information bit %v is wrong", i)
+			}
+		}
+	}
+
+	function := func(x, y int) bool {
+		switch {
+		case x < 9 && y < 9, x >= size-8 && y < 9, x < 9 && y >= size-8, x == 6, y == 6:
+			return true
+		case version >= 7 && (x >= size-11 && x < size-8 && y < 6 || y >= size-11 && y < size-8 && x < 6):
+			return true
+		}
+		positions := qrTestAlignment[version]
+		for i, cx := range positions {
+			for j, cy := range positions {
+				corner := i == 0 && (j == 0 || j == len(positions)-1) || j == 0 && i == len(positions)-1
+				if !corner && abs(x-cx) <= 2 && abs(y-cy) <= 2 {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	masked := func(x, y int) bool {
+		return [8]bool{
+			(y+x)%2 == 0, y%2 == 0, x%3 == 0, (y+x)%3 == 0, (y/2+x/3)%2 == 0, y*x%2+y*x%3 == 0,
+			(y*x%2+y*x%3)%2 == 0, ((y+x)%2+y*x%3)%2 == 0,
+		}[mask]
+	}
+
+	// the codewords zigzag up and down two module wide columns from the bottom right corner
+	var raw []byte
+	bits := 0
+	upward := true
+	for right := size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for i := 0; i < size; i++ {
+			y := i
+			if upward {
+				y = size - 1 - i
+			}
+			for x := right; x >= right-1; x-- {
+				if function(x, y) {
+					continue
+				}
+				if bits%8 == 0 {
+					raw = append(raw, 0)
+				}
+				if modules[y][x] != masked(x, y) {
+					raw[bits/8] |= 0x80 >> (bits % 8)
+				}
+				bits++
+			}
+		}
+		upward = !upward
+	}
+	raw = raw[:bits/8]
+
+	// de-interleave the blocks, the shorter ones first, and check each has no errors
+	blocks := qrBlocks[level][version]
+	ecLen := qrECCodewords[level][version]
+	long := len(raw) % blocks
+	shortData := len(raw)/blocks - ecLen
+	data := make([][]byte, blocks)
+	ec := make([][]byte, blocks)
+	next := 0
+	for i := 0; i < shortData+1; i++ {
+		for b := range data {
+			if i < shortData || b >= blocks-long {
+				data[b] = append(data[b], raw[next])
+				next++
+			}
+		}
+	}
+	for i := 0; i < ecLen; i++ {
+		for b := range ec {
+			ec[b] = append(ec[b], raw[next])
+			next++
+		}
+	}
+	var exp [255]byte
+	var log [256]int
+	for i, x := 0, 1; i < 255; i, x = i+1, x<<1^(x>>7)*0x11D {
+		exp[i] = byte(x)
+		log[x] = i
+	}
+	mul := func(a, b byte) byte {
+		if a == 0 || b == 0 {
+			return 0
+		}
+		return exp[(log[a]+log[b])%255]
+	}
+	var codewords []byte
+	for b := range data {
+		block := append(append([]byte(nil), data[b]...), ec[b]...)
+		for i := 0; i < ecLen; i++ {
+			// the block as a polynomial evaluated at the roots of the generator is zero
+			var s byte
+			for _, c := range block {
+				s = mul(s, exp[i]) ^ c
+			}
+			if s != 0 {
+				t.Fatalf("block %v has a Reed-Solomon syndrome %v of %v", b, i, s)
+			}
+		}
+		codewords = append(codewords, data[b]...)
+	}
+
+	// a byte mode segment, the terminator and the pad codewords
+	pos := 0
+	read := func(n int) int {
+		v := 0
+		for ; n > 0; n-- {
+			v = v<<1 | int(codewords[pos/8]>>(7-pos%8)&1)
+			pos++
+		}
+		return v
+	}
+	if mode := read(4); mode != 4 {
+		t.Fatalf("mode %04b isn't byte mode", mode)
+	}
+	count := read(8)
+	if version > 9 {
+		count = count<<8 | read(8)
+	}
+	payload := make([]byte, count)
+	for i := range payload {
+		payload[i] = byte(read(8))
+	}
+	if rest := min(4, 8*len(codewords)-pos); read(rest) != 0 {
+		t.Fatal("no terminator")
+	}
+	pos = (pos + 7) / 8
+	for i, pad := range codewords[pos:] {
+		if want := [2]byte{0xEC, 0x11}[i%2]; pad != want {
+			t.Fatalf("pad codeword %v is %X", i, pad)
+		}
+	}
+	return level, version, payload
+}
+
+func TestEncodeQR(t *testing.T) {
+	for _, c := range []struct {
+		data    string
+		level   ECLevel
+		version int
+		hash    string
+	}{
+		{"", ECLevelL, 1, "c850e0c425255588"},
+		{"HELLO WORLD", ECLevelM, 1, "3b8cac155a16d187"},
+		{"https://example.com/", ECLevelH, 3, "503efb9c5976a458"},
+		{"https://github.com/rickymclaren/gopdf", ECLevelQ, 4, "36f725efeb6c4227"},
+		{strings.Repeat("0123456789", 10), ECLevelQ, 8, "534f8a465143fb21"},
+		{string(bytes.Repeat([]byte{0, 0xFF, 0x80, '\n'}, 30)), ECLevelL, 6, "92b9ed7b6b45ae76"},
+		{strings.Repeat("a", 250), ECLevelL, 10, "465aa150585324f7"},
+	} {
+		qr, err := encodeQR([]byte(c.data), c.level)
+		if err != nil {
+			t.Fatal(err)
+		}
+		level, version, data := readQR(t, qr.modules)
+		if level != c.level || version != c.version || string(data) != c.data {
+			t.Errorf("%q at level %v read as version %v level %v %q, want version %v", c.data, c.level, version,
+				level, data, c.version)
+		}
+		var rows strings.Builder
+		for _, row := range qr.modules {
+			for _, dark := range row {
+				rows.WriteByte(map[bool]byte{false: '0', true: '1'}[dark])
+			}
+			rows.WriteByte('\n')
+		}
+		if hash := fmt.Sprintf("%x", sha256.Sum256([]byte(rows.String())))[:16]; hash != c.hash {
+			t.Errorf("%q at level %v has modules with hash %v, want %v", c.data, c.level, hash, c.hash)
+		}
+	}
+}
+
+func TestEncodeQRTooLong(t *testing.T) {
+	if _, err := encodeQR(make([]byte, 2954), ECLevelL); err == nil {
+		t.Error("2954 bytes encoded")
+	}
+	if _, err := encodeQR(make([]byte, 2953), ECLevelL); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,209 @@
+package gopdf
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Ref is a reference to an object of a document, returned by AddRawObject and PdfPage.Ref, for use in the values
+// of raw objects and catalog entries. It is written with the number the object has when the document is written.
+// The zero Ref, and a Ref to an object that has since been removed, such as an annotation of a deleted page, are
+// written as references to object 0, which Validate reports.
+type Ref struct {
+	o *PdfObject
+}
+
+// String returns the reference as it would be written now
+func (r Ref) String() string {
+	if r.o == nil {
+		return "0 0 R"
+	}
+	return r.o.objectRef()
+}
+
+// Name is a PDF name, written with a slash as in /Widget, for values of raw objects. Strings are written as text
+// strings instead.
+type Name string
+
+// rawObject is an object added with AddRawObject, holding its value in the form read from a PDF file
+type rawObject struct {
+	PdfObject
+	value any
+}
+
+func (o rawObject) bytes() []byte {
+	return valueObject(o.id, o.value)
+}
+
+// Ref returns a reference to the page for the values of raw objects, such as the /P entry of an annotation
+func (p *PdfPage) Ref() Ref {
+	return Ref{&p.PdfObject}
+}
+
+// AddRawObject adds an object the package doesn't otherwise support to the document and returns a reference to it.
+// The object is the dictionary dict, followed by stream as its data when stream isn't nil. The Length of a stream is
+// written for stream as it is, so stream must already be encoded by any Filter in dict.
+//
+// Keys are names, given with or without the slash. Values can be nil, bools, integers, floats, strings, which are
+// written as text strings, byte slices, which are written as strings of those bytes, Names, Refs, and slices and
+// maps with string keys of any of these. Dictionary entries are written in the order of their keys.
+// ErrInvalidRawObject is returned for other values, and for Refs to objects of another document.
+func (d *PdfDocument) AddRawObject(dict map[string]interface{}, stream []byte) (Ref, error) {
+	v, err := d.rawDict(dict)
+	if err != nil {
+		return Ref{}, err
+	}
+	o := &rawObject{value: v}
+	if stream != nil {
+		o.value = &pdfStream{dict: v, data: stream}
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.addObject(o)
+	return Ref{&o.PdfObject}, nil
+}
+
+// AddRawAnnotation adds an annotation of a kind the package doesn't otherwise support to the page, with the entries
+// of dict converted as they are by AddRawObject, and returns a reference to it. /Type /Annot is added when dict
+// doesn't have a Type, and dict must have the Subtype and Rect, which is in points from the bottom left corner of
+// the page.
+func (p *PdfPage) AddRawAnnotation(dict map[string]interface{}) (Ref, error) {
+	v, err := p.document.rawDict(dict)
+	if err != nil {
+		return Ref{}, err
+	}
+	for _, key := range []pdfName{"Subtype", "Rect"} {
+		if !v.has(key) {
+			return Ref{}, fmt.Errorf("%w: annotation has no %v", ErrInvalidRawObject, key)
+		}
+	}
+	if !v.has("Type") {
+		v = append(pdfDict{{"Type", pdfName("Annot")}}, v...)
+	}
+	a := &rawObject{value: v}
+	if p.measure != nil {
+		return Ref{&a.PdfObject}, nil
+	}
+	p.addObject(a)
+	p.annots = append(p.annots, a)
+	return Ref{&a.PdfObject}, nil
+}
+
+// AddCatalogEntry adds an entry to the document catalog, the root of the document's objects, with value converted as
+// it is by AddRawObject. It replaces an entry of the same key, including one the package writes itself, such as
+// /Metadata, except for Type and Pages, for which ErrInvalidRawObject is returned.
+func (d *PdfDocument) AddCatalogEntry(key string, value interface{}) error {
+	name := pdfName(strings.TrimPrefix(key, "/"))
+	if name == "Type" || name == "Pages" || name == "" {
+		return fmt.Errorf("%w: catalog entry %v can't be set", ErrInvalidRawObject, formatName(string(name)))
+	}
+	v, err := d.rawValue(value)
+	if err != nil {
+		return err
+	}
+	d.catalog.entries = d.catalog.entries.set(name, v)
+	return nil
+}
+
+// rawDict converts a dictionary given to AddRawObject to the form of one read from a PDF file
+func (d *PdfDocument) rawDict(dict map[string]interface{}) (pdfDict, error) {
+	v, err := d.rawValue(dict)
+	if err != nil {
+		return nil, err
+	}
+	return v.(pdfDict), nil
+}
+
+// rawValue converts a value given to AddRawObject to the form of one read from a PDF file
+func (d *PdfDocument) rawValue(v any) (any, error) {
+	switch v := v.(type) {
+	case nil, bool:
+		return v, nil
+	case string:
+		return pdfString(encodeTextString(v)), nil
+	case []byte:
+		return pdfString(v), nil
+	case Name:
+		return pdfName(v), nil
+	case Ref:
+		if v.o != nil && v.o.document != nil && v.o.document != d {
+			return nil, fmt.Errorf("%w: reference to an object of another document", ErrInvalidRawObject)
+		}
+		return v, nil
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.String:
+		return pdfString(encodeTextString(rv.String())), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if rv.Uint() > math.MaxInt64 {
+			return nil, fmt.Errorf("%w: integer %v is too large", ErrInvalidRawObject, v)
+		}
+		return int(rv.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, fmt.Errorf("%w: number %v", ErrInvalidRawObject, f)
+		}
+		return f, nil
+	case reflect.Slice, reflect.Array:
+		array := make([]any, rv.Len())
+		for i := range array {
+			item, err := d.rawValue(rv.Index(i).Interface())
+			if err != nil {
+				return nil, err
+			}
+			array[i] = item
+		}
+		return array, nil
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("%w: dictionary with %v keys", ErrInvalidRawObject, rv.Type().Key())
+		}
+		keys := make([]string, 0, rv.Len())
+		for _, k := range rv.MapKeys() {
+			keys = append(keys, k.String())
+		}
+		sort.Strings(keys)
+		dict := make(pdfDict, 0, len(keys))
+		for _, k := range keys {
+			key := pdfName(strings.TrimPrefix(k, "/"))
+			if key == "" || dict.has(key) {
+				return nil, fmt.Errorf("%w: empty or repeated dictionary key %q", ErrInvalidRawObject, k)
+			}
+			value, err := d.rawValue(rv.MapIndex(reflect.ValueOf(k).Convert(rv.Type().Key())).Interface())
+			if err != nil {
+				return nil, err
+			}
+			dict = append(dict, pdfEntry{key, value})
+		}
+		return dict, nil
+	}
+	return nil, fmt.Errorf("%w: value %v of type %T", ErrInvalidRawObject, v, v)
+}
+
+// has reports whether the dictionary has key, even with a null value
+func (d pdfDict) has(key pdfName) bool {
+	for _, e := range d {
+		if e.key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// set returns the dictionary with the value of key replaced, or added at the end if it doesn't have key
+func (d pdfDict) set(key pdfName, value any) pdfDict {
+	for i, e := range d {
+		if e.key == key {
+			d[i].value = value
+			return d
+		}
+	}
+	return append(d, pdfEntry{key, value})
+}
@@ -0,0 +1,94 @@
+package gopdf
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRawObject(t *testing.T) {
+	d := NewPdfDocument()
+	p := d.CurrentPage()
+	meta, err := d.AddRawObject(map[string]interface{}{"Type": Name("Metadata"), "/Subtype": Name("XML")},
+		[]byte("<x:xmpmeta/>"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref, err := d.AddRawObject(map[string]interface{}{
+		"Values": []any{1, -2.5, true, nil, "café", []byte(`a(b)\`), Name("A Name")},
+		"Nested": map[string]any{"Page": p.Ref(), "Meta": meta},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = p.AddRawAnnotation(map[string]interface{}{"Subtype": Name("Square"), "Rect": []int{0, 0, 9, 9}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.AddCatalogEntry("Metadata", meta); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := d.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj := func(r Ref) string {
+		return strings.TrimSuffix(r.String(), "R") + "obj\r\n"
+	}
+	for _, want := range []string{
+		obj(meta) + "<<\r\n/Subtype /XML\r\n/Type /Metadata\r\n/Length 12\r\n>>\r\nstream\r\n<x:xmpmeta/>\r\n",
+		obj(ref) + "<< /Nested << /Meta " + meta.String() + " /Page " + p.Ref().String() +
+			" >> /Values [ 1 -2.5 true null <636166E9> <612862295C> /A#20Name ] >>\r\n",
+		"<< /Type /Annot /Rect [ 0 0 9 9 ] /Subtype /Square >>",
+		"/Pages 2 0 R\r\n/Metadata " + meta.String() + "\r\n>>",
+	} {
+		if !bytes.Contains(data, []byte(want)) {
+			t.Errorf("output doesn't contain %q", want)
+		}
+	}
+}
+
+func TestRawObjectErrors(t *testing.T) {
+	d := NewPdfDocument()
+	for _, dict := range []map[string]interface{}{
+		{"Channel": make(chan int)},
+		{"Other": NewPdfDocument().CurrentPage().Ref()},
+		{"A": 1, "/A": 2},
+	} {
+		if _, err := d.AddRawObject(dict, nil); !errors.Is(err, ErrInvalidRawObject) {
+			t.Errorf("AddRawObject(%v) returned %v", dict, err)
+		}
+	}
+	if _, err := d.CurrentPage().AddRawAnnotation(map[string]interface{}{"Subtype": Name("Square")}); err == nil {
+		t.Error("annotation without a Rect added")
+	}
+	if err := d.AddCatalogEntry("Pages", 1); !errors.Is(err, ErrInvalidRawObject) {
+		t.Errorf("AddCatalogEntry(Pages) returned %v", err)
+	}
+}
+
+func TestDanglingRef(t *testing.T) {
+	d := NewPdfDocument()
+	annot, err := d.CurrentPage().AddRawAnnotation(map[string]interface{}{"Subtype": Name("Square"),
+		"Rect": []int{0, 0, 9, 9}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.AddPage()
+	if _, err := d.AddRawObject(map[string]interface{}{"Annot": annot}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.DeletePage(0); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Validate(); !errors.Is(err, ErrInvalidDocument) {
+		t.Errorf("reference to a deleted annotation not reported: %v", err)
+	}
+}
@@ -0,0 +1,164 @@
+package gopdf
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"math"
+)
+
+// SetImageMaxDPI sets the highest resolution images are written at, in pixels per inch at the largest size each is
+// drawn. Images with more pixels than that are resampled down when the document is written, and smaller ones are
+// left as they are. Set it before adding images, since only images added afterwards keep what is needed to resample
+// them. 0, the default, writes images at their full resolution. When writing with StartWriting, images are resampled
+// for the largest size they were drawn at before the page that first uses them was finished.
+func (d *PdfDocument) SetImageMaxDPI(dpi float64) {
+	d.imageMaxDPI = dpi
+}
+
+// setSource keeps what is needed to resample an image, if images are to be resampled
+func (d *PdfDocument) setSource(pi *PdfImage, source func() (image.Image, error)) {
+	if d.imageMaxDPI > 0 {
+		pi.source = source
+	}
+}
+
+// drawnAt records that an image was drawn w by h points, so that it can be resampled for the largest size it is
+// drawn at
+func (pi *PdfImage) drawnAt(w, h float64) {
+	if pi.orientation >= 5 {
+		w, h = h, w
+	}
+	pi.document.mu.Lock()
+	defer pi.document.mu.Unlock()
+	pi.drawnWidth = math.Max(pi.drawnWidth, math.Abs(w))
+	pi.drawnHeight = math.Max(pi.drawnHeight, math.Abs(h))
+}
+
+// downsampleImages resamples the images that have more pixels than the maximum resolution needs
+func (d *PdfDocument) downsampleImages() {
+	if d.imageMaxDPI <= 0 {
+		return
+	}
+	for _, pi := range d.resources.images {
+		pi.downsample(d.imageMaxDPI)
+	}
+}
+
+// downsample resamples the image to dpi pixels per inch at the largest size it has been drawn, if that is fewer
+// pixels than it has. JPEG images stay JPEG and others are compressed losslessly. An image that can't be decoded,
+// and a stencil or an image with a colour key mask, whose edges resampling would blur, is left as it is.
+func (pi *PdfImage) downsample(dpi float64) {
+	if pi.source == nil || pi.drawnWidth == 0 || pi.drawnHeight == 0 || pi.mask != "" || pi.stencil {
+		return
+	}
+	w := min(pi.width, int(math.Ceil(pi.drawnWidth/72*dpi)))
+	h := min(pi.height, int(math.Ceil(pi.drawnHeight/72*dpi)))
+	if w == pi.width && h == pi.height {
+		return
+	}
+	src, err := pi.source()
+	if err != nil {
+		return
+	}
+	pi.source = nil
+	sw, sh := src.Bounds().Dx(), src.Bounds().Dy()
+	var img image.Image
+	if cmyk, ok := src.(*image.CMYK); ok {
+		pixels := make([]byte, 0, sw*sh*4)
+		for y := 0; y < sh; y++ {
+			at := cmyk.PixOffset(cmyk.Rect.Min.X, cmyk.Rect.Min.Y+y)
+			pixels = append(pixels, cmyk.Pix[at:at+4*sw]...)
+		}
+		img = &image.CMYK{Pix: resample(pixels, 4, sw, sh, w, h), Stride: 4 * w, Rect: image.Rect(0, 0, w, h)}
+	} else if pixels, gray := pixelData(src); gray {
+		pixels = resample(pixels, 1, sw, sh, w, h)
+		if pi.bits == 1 {
+			// keep black and white images black and white
+			for i, v := range pixels {
+				pixels[i] = 0
+				if v >= 128 {
+					pixels[i] = 255
+				}
+			}
+		}
+		img = &image.Gray{Pix: pixels, Stride: w, Rect: image.Rect(0, 0, w, h)}
+	} else {
+		rgb := resample(pixels, 3, sw, sh, w, h)
+		rgba := image.NewNRGBA(image.Rect(0, 0, w, h))
+		for i := 0; i < w*h; i++ {
+			copy(rgba.Pix[4*i:], rgb[3*i:3*i+3])
+			rgba.Pix[4*i+3] = 255
+		}
+		img = rgba
+	}
+
+	jpegSource := pi.filter == "/DCTDecode"
+	pi.decode = ""
+	if _, cmyk := img.(*image.CMYK); jpegSource && !cmyk {
+		var buf bytes.Buffer
+		// the new JPEG has no EXIF segment, so the orientation is kept as it is
+		if jpeg.Encode(&buf, img, &jpeg.Options{Quality: 90}) == nil && pi.loadJPEG(buf.Bytes()) {
+			return
+		}
+	}
+	pi.loadPixels(img)
+}
+
+// resample scales pixels with the given number of channels from sw by sh to dw by dh using a Catmull-Rom filter,
+// first horizontally and then vertically
+func resample(pixels []byte, channels, sw, sh, dw, dh int) []byte {
+	rows := resampleAxis(pixels, channels, sw, sh, dw, channels, channels*sw, channels, channels*dw)
+	return resampleAxis(rows, channels, sh, dw, dh, channels*dw, channels, channels*dw, channels)
+}
+
+// resampleAxis scales lines of n pixels to m pixels, for count lines. Pixel i of line l starts at l*lineStep+i*step
+// in src, and at l*outLineStep+i*outStep in the result.
+func resampleAxis(src []byte, channels, n, count, m, step, lineStep, outStep, outLineStep int) []byte {
+	out := make([]byte, count*m*channels)
+	scale := float64(n) / float64(m)
+	support := 2 * math.Max(scale, 1)
+	weights := make([]float64, 0, int(2*support)+2)
+	sums := make([]float64, channels)
+	for i := 0; i < m; i++ {
+		centre := (float64(i)+0.5)*scale - 0.5
+		first := int(math.Floor(centre-support)) + 1
+		weights = weights[:0]
+		total := 0.0
+		for j := first; float64(j) < centre+support; j++ {
+			wt := catmullRom((float64(j) - centre) / math.Max(scale, 1))
+			weights = append(weights, wt)
+			total += wt
+		}
+		for l := 0; l < count; l++ {
+			for c := range sums {
+				sums[c] = 0
+			}
+			for k, wt := range weights {
+				// pixels past the edges repeat the edge pixels
+				j := min(max(first+k, 0), n-1)
+				at := l*lineStep + j*step
+				for c := range sums {
+					sums[c] += wt * float64(src[at+c])
+				}
+			}
+			at := l*outLineStep + i*outStep
+			for c, sum := range sums {
+				out[at+c] = byte(math.Max(0, math.Min(255, math.Round(sum/total))))
+			}
+		}
+	}
+	return out
+}
+
+// catmullRom is the Catmull-Rom cubic filter kernel, which is 0 from a distance of 2
+func catmullRom(x float64) float64 {
+	x = math.Abs(x)
+	switch {
+	case x < 1:
+		return (1.5*x-2.5)*x*x + 1
+	case x < 2:
+		return ((-0.5*x+2.5)*x-4)*x + 2
+	}
+	return 0
+}
@@ -0,0 +1,71 @@
+package gopdf
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+)
+
+func TestDownsampleImages(t *testing.T) {
+	// a 600 by 400 pixel image drawn 150 by 100 points, which at 72 DPI needs only 150 by 100 pixels
+	img := image.NewNRGBA(image.Rect(0, 0, 600, 400))
+	v := uint32(1)
+	for y := 0; y < 400; y++ {
+		for x := 0; x < 600; x++ {
+			v = v*1664525 + 1013904223
+			img.Set(x, y, color.NRGBA{uint8(x), uint8(y), uint8(v >> 28), 255})
+		}
+	}
+	var jpg bytes.Buffer
+	if err := jpeg.Encode(&jpg, img, &jpeg.Options{Quality: 90}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range []struct {
+		source string
+		add    func(d *PdfDocument) error
+		filter pdfName
+	}{
+		{"pixels", func(d *PdfDocument) error {
+			_, err := d.AddImageFromImage("img", img)
+			return err
+		}, "FlateDecode"},
+		{"JPEG", func(d *PdfDocument) error {
+			_, err := d.AddImageFromReader("img", bytes.NewReader(jpg.Bytes()))
+			return err
+		}, "DCTDecode"},
+	} {
+		build := func(dpi float64) ([]byte, *pdfStream) {
+			d := NewPdfDocument()
+			d.SetImageMaxDPI(dpi)
+			if err := c.add(d); err != nil {
+				t.Fatal(err)
+			}
+			if err := d.CurrentPage().DrawImageScaled("img", 100, 100, 150, 0); err != nil {
+				t.Fatal(err)
+			}
+			data, err := d.Bytes()
+			if err != nil {
+				t.Fatal(err)
+			}
+			return data, imageXObject(t, data, "img")
+		}
+		full, fullImage := build(0)
+		small, smallImage := build(72)
+		if w, h := fullImage.dict.get("Width"), fullImage.dict.get("Height"); w != 600 || h != 400 {
+			t.Errorf("%v image written at full resolution as %v by %v", c.source, w, h)
+		}
+		if w, h := smallImage.dict.get("Width"), smallImage.dict.get("Height"); w != 150 || h != 100 {
+			t.Errorf("%v image written at 72 DPI as %v by %v, want 150 by 100", c.source, w, h)
+		}
+		if filter := smallImage.dict.get("Filter"); filter != c.filter {
+			t.Errorf("%v image resampled with filter %v, want %v", c.source, filter, c.filter)
+		}
+		if len(small)*4 > len(full) {
+			t.Errorf("%v image resampled to 72 DPI makes a file of %v bytes, against %v at full resolution",
+				c.source, len(small), len(full))
+		}
+	}
+}
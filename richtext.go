@@ -0,0 +1,225 @@
+package gopdf
+
+import "unicode"
+
+// TextStyle is the style of a run of rich text. Fields left at their zero value keep the page's current font, size,
+// colour, underlining and baseline.
+type TextStyle struct {
+	Font      string  // name of a font added to the document, or empty for the current font
+	Size      float64 // size in points, or 0 for the current size
+	Colour    Color   // colour of the text, or nil for the current fill colour
+	Underline bool    // underline the run even if underlining is off
+	Rise      float64 // distance in points to raise the baseline, or lower it when negative
+}
+
+// RichText is a paragraph made of runs of text in different styles, which WriteRichText wraps as one flow of words
+type RichText struct {
+	runs []richRun
+}
+
+type richRun struct {
+	text  string
+	style TextStyle
+}
+
+// NewRichText returns an empty paragraph of rich text
+func NewRichText() *RichText {
+	return &RichText{}
+}
+
+// Add adds a run of text in a style to the end of the paragraph. A word can be made of several runs, such as a word
+// with a bold first letter, and lines are only broken at spaces and newlines.
+func (rt *RichText) Add(text string, style TextStyle) *RichText {
+	rt.runs = append(rt.runs, richRun{text, style})
+	return rt
+}
+
+// richPiece is the part of a run of rich text in a word or on a line
+type richPiece struct {
+	run  int
+	text string
+}
+
+// richWord is a word of rich text, made of pieces of one or more runs
+type richWord struct {
+	pieces   []richPiece
+	space    int // run of the space before the word, or -1 when there isn't one
+	breaks   int // number of newlines before the word
+	width    float64
+	spaceLen float64 // width of the space before the word
+	maxSize  float64 // largest font size in the word
+}
+
+// WriteRichText outputs a paragraph of rich text starting at the cursor, breaking it into lines no wider than width
+// as for WriteWrapped, or ending at the right margin when width is 0. Each line starts at the cursor's horizontal
+// position, and the runs on a line share a baseline far enough below the line above for the largest text on it. A
+// newline in the text starts a new line. The cursor moves to the start of the line below the paragraph.
+func (p *PdfPage) WriteRichText(rt *RichText, width float64) error {
+	fonts := make([]*PdfFont, len(rt.runs))
+	sizes := make([]float64, len(rt.runs))
+	for i, run := range rt.runs {
+		fonts[i], sizes[i] = p.font, p.fontSize
+		if run.style.Font != "" {
+			font, err := p.document.resources.font(run.style.Font)
+			if err != nil {
+				return err
+			}
+			fonts[i] = font
+		}
+		if run.style.Size > 0 {
+			sizes[i] = run.style.Size
+		}
+	}
+	// measure returns the width in points of text in the font and size of a run
+	measure := func(run int, text string) float64 {
+		font, size := p.font, p.fontSize
+		p.font, p.fontSize = fonts[run], sizes[run]
+		width := p.textWidth(text)
+		p.font, p.fontSize = font, size
+		return width
+	}
+
+	words := splitRichText(rt)
+	for i := range words {
+		w := &words[i]
+		for _, piece := range w.pieces {
+			w.width += measure(piece.run, piece.text)
+			w.maxSize = max(w.maxSize, sizes[piece.run])
+		}
+		if w.space >= 0 {
+			w.spaceLen = measure(w.space, " ")
+		}
+	}
+
+	// fill the lines with as many words as fit, starting a line with a word even if it's too wide
+	left, available := p.x, p.lineRight()-p.x
+	if width > 0 {
+		available = p.pt(width)
+	}
+	var lines [][]richWord
+	lineWidth := 0.0
+	for i, w := range words {
+		if i == 0 || w.breaks > 0 || lineWidth+w.spaceLen+w.width > available {
+			for j := 1; j < w.breaks; j++ {
+				lines = append(lines, nil)
+			}
+			w.space, w.spaceLen = -1, 0
+			lines = append(lines, nil)
+			lineWidth = 0
+		}
+		lines[len(lines)-1] = append(lines[len(lines)-1], w)
+		lineWidth += w.spaceLen + w.width
+	}
+
+	for i, line := range lines {
+		if i > 0 {
+			largest := p.fontSize
+			if len(line) > 0 {
+				largest = 0
+			}
+			for _, w := range line {
+				largest = max(largest, w.maxSize)
+			}
+			p.y -= p.advanceFor(largest)
+		}
+		x := left
+		for _, piece := range joinRichLine(line) {
+			p.outputRichPiece(rt.runs[piece.run].style, fonts[piece.run], sizes[piece.run], piece.text, x)
+			x += measure(piece.run, piece.text)
+		}
+	}
+	p.x = left
+	p.newLine()
+	return nil
+}
+
+// splitRichText splits the runs of a paragraph into words at spaces and newlines, collapsing runs of spaces
+func splitRichText(rt *RichText) []richWord {
+	var words []richWord
+	word := richWord{space: -1}
+	for i, run := range rt.runs {
+		start := 0
+		for at, r := range run.text + " " {
+			if at < len(run.text) && !unicode.IsSpace(r) {
+				continue
+			}
+			if at > start {
+				word.pieces = append(word.pieces, richPiece{i, run.text[start:at]})
+			}
+			start = at + len(string(r))
+			if at == len(run.text) {
+				break
+			}
+			if len(word.pieces) > 0 {
+				words = append(words, word)
+				word = richWord{space: -1}
+			}
+			if r == '\n' {
+				word.breaks++
+				word.space = -1
+			} else if word.space < 0 && word.breaks == 0 {
+				word.space = i
+			}
+		}
+	}
+	if len(word.pieces) > 0 {
+		words = append(words, word)
+	}
+	return words
+}
+
+// joinRichLine returns the text of a line of words as pieces, joining the words and spaces of the same run
+func joinRichLine(line []richWord) []richPiece {
+	var pieces []richPiece
+	add := func(piece richPiece) {
+		if n := len(pieces); n > 0 && pieces[n-1].run == piece.run {
+			pieces[n-1].text += piece.text
+		} else {
+			pieces = append(pieces, piece)
+		}
+	}
+	for _, w := range line {
+		if w.space >= 0 {
+			add(richPiece{w.space, " "})
+		}
+		for _, piece := range w.pieces {
+			add(piece)
+		}
+	}
+	return pieces
+}
+
+// outputRichPiece outputs text in a style at x on the current baseline. Styled text is drawn in a saved graphics
+// state so that the page's font, colour and baseline are put back afterwards.
+func (p *PdfPage) outputRichPiece(style TextStyle, font *PdfFont, size float64, text string, x float64) {
+	if style.Font == "" && style.Size == 0 && style.Colour == nil && !style.Underline && style.Rise == 0 {
+		p.outputTextAt(text, x)
+		return
+	}
+	p.SaveState()
+	if font != nil && (font != p.font || size != p.fontSize) {
+		p.font, p.fontSize = font, size
+		p.use(font)
+		p.content.addTextf("%v %v Tf\r\n", formatName(p.font.name), formatNumber(p.fontSize))
+	}
+	if style.Colour != nil {
+		p.SetFill(style.Colour)
+	}
+	if style.Rise != 0 {
+		p.SetTextRise(p.textRise + style.Rise)
+	}
+	underline := p.underline
+	p.underline = underline || style.Underline
+	p.outputTextAt(text, x)
+	p.underline = underline
+	p.RestoreState()
+}
+
+// advanceFor returns the distance between baselines for a line whose largest text is size points
+func (p *PdfPage) advanceFor(size float64) float64 {
+	fontSize := p.fontSize
+	p.fontSize = size
+	advance := p.lineAdvance()
+	p.fontSize = fontSize
+	return advance
+}
@@ -0,0 +1,104 @@
+package gopdf
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// DrawCircle draws a circle of radius r centred on cx, cy
+func (p *PdfPage) DrawCircle(cx, cy, r float64, style DrawStyle) {
+	p.DrawEllipse(cx, cy, r, r, style)
+}
+
+// DrawEllipse draws an ellipse centred on cx, cy with horizontal radius rx and vertical radius ry
+func (p *PdfPage) DrawEllipse(cx, cy, rx, ry float64, style DrawStyle) {
+	cx, cy = p.point(cx, cy)
+	path := arcPath(cx, cy, p.pt(rx), p.pt(ry), 0, 360)
+	p.content.addGraphics(path + "h\r\n" + style.operator() + "\r\n")
+}
+
+// DrawArc strokes the part of an ellipse centred on cx, cy from startDeg to endDeg, measured in degrees
+// anticlockwise from the positive x axis
+func (p *PdfPage) DrawArc(cx, cy, rx, ry, startDeg, endDeg float64) {
+	cx, cy = p.point(cx, cy)
+	p.content.addGraphics(arcPath(cx, cy, p.pt(rx), p.pt(ry), startDeg, endDeg) + "S\r\n")
+}
+
+// arcPath returns the path operators for an elliptical arc, made of cubic Bézier curves of at most 90 degrees each
+func arcPath(cx, cy, rx, ry, startDeg, endDeg float64) string {
+	var sb strings.Builder
+	start := startDeg * math.Pi / 180
+	sweep := (endDeg - startDeg) * math.Pi / 180
+	segments := int(math.Ceil(math.Abs(sweep) / (math.Pi / 2)))
+	if segments == 0 {
+		segments = 1
+	}
+	step := sweep / float64(segments)
+	// distance of the control points along the tangents, 0.5523 for a quarter circle
+	k := 4.0 / 3.0 * math.Tan(step/4)
+	point := func(a float64) (float64, float64) {
+		return cx + rx*math.Cos(a), cy + ry*math.Sin(a)
+	}
+	x0, y0 := point(start)
+	fmt.Fprintf(&sb, "%v %v m\r\n", formatNumber(x0), formatNumber(y0))
+	for i := 0; i < segments; i++ {
+		a1 := start + float64(i)*step
+		a2 := a1 + step
+		x1, y1 := point(a1)
+		x2, y2 := point(a2)
+		c1x, c1y := x1-k*rx*math.Sin(a1), y1+k*ry*math.Cos(a1)
+		c2x, c2y := x2+k*rx*math.Sin(a2), y2-k*ry*math.Cos(a2)
+		fmt.Fprintf(&sb, "%v %v %v %v %v %v c\r\n", formatNumber(c1x), formatNumber(c1y), formatNumber(c2x),
+			formatNumber(c2y), formatNumber(x2), formatNumber(y2))
+	}
+	return sb.String()
+}
+
+// Point is a position on a page in the document's unit
+type Point struct {
+	X, Y float64
+}
+
+// DrawPolygon draws the closed shape with corners at points. Use FillEvenOdd or FillStrokeEvenOdd to leave holes
+// where a self intersecting shape such as a star overlaps itself.
+func (p *PdfPage) DrawPolygon(points []Point, style DrawStyle) error {
+	path, err := polylinePath(p.ptPoints(points))
+	if err != nil {
+		return err
+	}
+	p.content.addGraphics(path + "h\r\n" + style.operator() + "\r\n")
+	return nil
+}
+
+// DrawPolyline strokes lines joining points in turn without closing the shape
+func (p *PdfPage) DrawPolyline(points []Point) error {
+	path, err := polylinePath(p.ptPoints(points))
+	if err != nil {
+		return err
+	}
+	p.content.addGraphics(path + "S\r\n")
+	return nil
+}
+
+// ptPoints converts points in the document's unit to points
+func (p *PdfPage) ptPoints(points []Point) []Point {
+	converted := make([]Point, len(points))
+	for i, pt := range points {
+		converted[i].X, converted[i].Y = p.point(pt.X, pt.Y)
+	}
+	return converted
+}
+
+// polylinePath returns the path operators for straight lines joining points, whose coordinates are in points
+func polylinePath(points []Point) (string, error) {
+	if len(points) < 2 {
+		return "", fmt.Errorf("%w: %v", ErrTooFewPoints, len(points))
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%v %v m\r\n", formatNumber(points[0].X), formatNumber(points[0].Y))
+	for _, pt := range points[1:] {
+		fmt.Fprintf(&sb, "%v %v l\r\n", formatNumber(pt.X), formatNumber(pt.Y))
+	}
+	return sb.String(), nil
+}
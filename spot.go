@@ -0,0 +1,94 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// PdfSpotColor is a Separation colour space for a named ink, such as a Pantone colour, with the CMYK colour viewers
+// and printers without the ink show instead
+type PdfSpotColor struct {
+	PdfObject
+	name      string
+	resource  string
+	alternate [4]float64
+}
+
+// spotTint is a tint of a spot colour
+type spotTint struct {
+	spot *PdfSpotColor
+	tint float64
+}
+
+func (t spotTint) operator(stroke bool) string {
+	if stroke {
+		return fmt.Sprintf("%v CS %v SCN", formatName(t.spot.resource), formatNumber(t.tint))
+	}
+	return fmt.Sprintf("%v cs %v scn", formatName(t.spot.resource), formatNumber(t.tint))
+}
+
+// AddSpotColor adds a spot colour for the named ink, shown as the CMYK colour altCMYK at full tint where the ink
+// isn't available
+func (d *PdfDocument) AddSpotColor(name string, altCMYK [4]float64) (*PdfSpotColor, error) {
+	for _, s := range d.resources.spotColors {
+		if s.name == name {
+			return nil, fmt.Errorf("%w: spot colour %v", ErrDuplicateName, name)
+		}
+	}
+	s := &PdfSpotColor{name: name, resource: fmt.Sprintf("CS%v", len(d.resources.spotColors)+1), alternate: altCMYK}
+	d.addObject(s)
+	d.resources.spotColors = append(d.resources.spotColors, s)
+	return s, nil
+}
+
+// Tint returns the colour of the ink at tint, from 0 for none to 1 for full strength
+func (s *PdfSpotColor) Tint(tint float64) Color {
+	return spotTint{s, tint}
+}
+
+// SetFillSpot sets the colour used for text and filled shapes to a tint of a spot colour added with AddSpotColor,
+// from 0 for none to 1 for full strength
+func (p *PdfPage) SetFillSpot(name string, tint float64) error {
+	s, err := p.document.spotColor(name)
+	if err != nil {
+		return err
+	}
+	p.SetFill(s.Tint(tint))
+	return nil
+}
+
+// SetStrokeSpot sets the colour used for lines and the outlines of shapes to a tint of a spot colour, as for
+// SetFillSpot
+func (p *PdfPage) SetStrokeSpot(name string, tint float64) error {
+	s, err := p.document.spotColor(name)
+	if err != nil {
+		return err
+	}
+	p.SetStroke(s.Tint(tint))
+	return nil
+}
+
+// spotColor returns the spot colour added as name
+func (d *PdfDocument) spotColor(name string) (*PdfSpotColor, error) {
+	names := make([]string, len(d.resources.spotColors))
+	for i, s := range d.resources.spotColors {
+		if s.name == name {
+			return s, nil
+		}
+		names[i] = s.name
+	}
+	return nil, fmt.Errorf("%w: %v (%v)", ErrSpotColorNotFound, name, availableNames("spot colours", names))
+}
+
+func (s PdfSpotColor) bytes() []byte {
+	a := s.alternate
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%v 0 obj\r\n", s.id)
+	fmt.Fprintf(&buf, "[ /Separation %v /DeviceCMYK\r\n", formatName(s.name))
+	// the tint transform interpolates linearly from no ink to the alternate colour
+	fmt.Fprintf(&buf, "<< /FunctionType 2 /Domain [ 0 1 ] /Range [ 0 1 0 1 0 1 0 1 ] /C0 [ 0 0 0 0 ] /C1 [ %v %v %v %v ] /N 1 >>\r\n",
+		formatNumber(a[0]), formatNumber(a[1]), formatNumber(a[2]), formatNumber(a[3]))
+	fmt.Fprintf(&buf, "]\r\n")
+	fmt.Fprintf(&buf, "endobj\r\n")
+	return buf.Bytes()
+}
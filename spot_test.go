@@ -0,0 +1,94 @@
+package gopdf
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSpotColors(t *testing.T) {
+	d := NewPdfDocument()
+	inks := []struct {
+		name string
+		cmyk [4]float64
+	}{
+		{"PANTONE 185 C", [4]float64{0, 0.91, 0.76, 0}},
+		{"PANTONE 286 C", [4]float64{1, 0.66, 0, 0.02}},
+		{"Varnish", [4]float64{0, 0, 0, 0.1}},
+	}
+	p := d.CurrentPage()
+	for i, ink := range inks {
+		if _, err := d.AddSpotColor(ink.name, ink.cmyk); err != nil {
+			t.Fatal(err)
+		}
+		if err := p.SetFillSpot(ink.name, 0.5); err != nil {
+			t.Fatal(err)
+		}
+		p.DrawBoxStyled(100, 100+float64(i)*50, 50, 20, Fill)
+	}
+	if err := p.SetStrokeSpot("Varnish", 1); err != nil {
+		t.Fatal(err)
+	}
+	p.DrawLine(100, 400, 200, 400)
+	if _, err := d.AddSpotColor("Varnish", [4]float64{}); !errors.Is(err, ErrDuplicateName) {
+		t.Errorf("second Varnish added: %v", err)
+	}
+	data, err := d.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pr, err := parsePDF(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pages, err := pr.pages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := pr.pageContent(pages[0].dict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resources, _ := pr.resolve(pages[0].dict.get("Resources")).(pdfDict)
+	spaces, _ := pr.resolve(resources.get("ColorSpace")).(pdfDict)
+	if len(spaces) != len(inks) {
+		t.Fatalf("%v colour spaces, want %v", len(spaces), len(inks))
+	}
+	objects := map[int]bool{}
+	for i, ink := range inks {
+		name := fmt.Sprintf("CS%v", i+1)
+		ref, ok := spaces.get(pdfName(name)).(pdfRef)
+		if !ok || objects[ref.num] {
+			t.Errorf("%v isn't a colour space object of its own: %v", name, spaces.get(pdfName(name)))
+			continue
+		}
+		objects[ref.num] = true
+		space, _ := pr.resolve(ref).([]any)
+		if len(space) != 4 || space[0] != pdfName("Separation") || space[1] != pdfName(ink.name) ||
+			space[2] != pdfName("DeviceCMYK") {
+			t.Errorf("%v is %v", name, space)
+			continue
+		}
+		transform, _ := pr.resolve(space[3]).(pdfDict)
+		for key, want := range map[pdfName]any{
+			"FunctionType": 2,
+			"Domain":       []any{0, 1},
+			"Range":        []any{0, 1, 0, 1, 0, 1, 0, 1},
+			"C0":           []any{0, 0, 0, 0},
+			"C1":           ink.cmyk,
+			"N":            1,
+		} {
+			if got := transform.get(key); fmt.Sprint(got) != fmt.Sprint(want) {
+				t.Errorf("%v tint transform %v is %v, want %v", ink.name, key, got, want)
+			}
+		}
+		if op := "/" + name + " cs 0.5 scn"; !strings.Contains(string(content), op) {
+			t.Errorf("%v isn't used with %v", ink.name, op)
+		}
+	}
+	if !strings.Contains(string(content), "/CS3 CS 1 SCN") {
+		t.Error("Varnish isn't used to stroke")
+	}
+}
@@ -0,0 +1,91 @@
+package gopdf
+
+import (
+	"bytes"
+	"crypto/md5"
+	"io"
+)
+
+// StartWriting starts writing the document to w a page at a time, so that long documents don't have to be held in
+// memory. Call FinishPage when each page is complete and Close at the end instead of WriteTo. Options that affect
+// the whole file, such as encryption and compression, must be set before StartWriting.
+func (d *PdfDocument) StartWriting(w io.Writer) error {
+	if d.stream != nil {
+		return ErrStreaming
+	}
+	if d.err != nil {
+		return d.err
+	}
+	d.stream = &countingWriter{w: w, hash: md5.New()}
+	d.writeHeader(d.stream)
+	return d.stream.err
+}
+
+// FinishPage writes the content of the current page and any images added so far, then frees them. The header and
+// footer are drawn first, with a total page count of 0 since it isn't known yet. The page can't be changed
+// afterwards: drawing on it records ErrPageFinished, which Close returns.
+func (d *PdfDocument) FinishPage() error {
+	if d.stream == nil {
+		return ErrNotStreaming
+	}
+	p := d.currentPage
+	if p == nil || p.content.finished {
+		return nil
+	}
+	for i, page := range d.catalog.pdfPages.pages {
+		if page == p {
+			d.drawHeaderAndFooter(p, i+1, 0)
+		}
+	}
+	if d.err != nil {
+		return d.err
+	}
+	d.writeEarly(p.content.id, p.content)
+	p.content.stream = bytes.Buffer{}
+	p.content.finished = true
+	d.downsampleImages()
+	for _, image := range d.resources.images {
+		if image.data != nil {
+			d.writeEarly(image.id, image)
+			image.data = nil
+		}
+	}
+	return d.stream.err
+}
+
+// writeEarly writes an object before the rest of the document and records its offset
+func (d *PdfDocument) writeEarly(id int, obj PdfObjectWriter) {
+	for len(d.written) < id {
+		d.written = append(d.written, 0)
+	}
+	d.written[id-1] = d.writeObject(d.stream, id, obj.bytes())
+}
+
+// Close draws the headers and footers of the pages that haven't been finished and writes them, followed by the
+// rest of the document. The document can't be written again afterwards.
+func (d *PdfDocument) Close() error {
+	if d.stream == nil {
+		return ErrNotStreaming
+	}
+	if d.err != nil {
+		return d.err
+	}
+	if _, err := d.insertTOC(); err != nil {
+		return err
+	}
+	d.addHeadersAndFooters()
+	if d.err != nil {
+		return d.err
+	}
+	d.addPageResources()
+	if err := d.checkPDFA(); err != nil {
+		return err
+	}
+	d.downsampleImages()
+	if err := d.checkDestinations(); err != nil {
+		return err
+	}
+	d.writeBody(d.stream, d.written)
+	d.setErr(ErrStreaming)
+	return d.stream.err
+}
@@ -0,0 +1,293 @@
+package gopdf
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"sort"
+)
+
+// Tables copied unchanged into a subset font. The hinting tables are kept so that the glyphs render as designed.
+var subsetKeptTables = []string{"head", "hhea", "OS/2", "name", "cvt ", "fpgm", "prep", "gasp"}
+
+// subsetGlyphs returns the glyphs needed to draw the used glyphs: .notdef, the used glyphs themselves and the
+// components of any composite glyphs among them
+func (f *trueTypeFont) subsetGlyphs(used []uint16) map[uint16]bool {
+	glyphs := make(map[uint16]bool)
+	queue := append([]uint16{0}, used...)
+	for len(queue) > 0 {
+		gid := queue[0]
+		queue = queue[1:]
+		if glyphs[gid] || int(gid) >= f.numGlyphs {
+			continue
+		}
+		glyphs[gid] = true
+		for _, component := range f.glyphComponents(gid) {
+			if !glyphs[component] {
+				queue = append(queue, component)
+			}
+		}
+	}
+	return glyphs
+}
+
+// glyph returns the glyf table data of a glyph
+func (f *trueTypeFont) glyph(gid uint16) []byte {
+	loca, glyf := f.tables["loca"], f.tables["glyf"]
+	var start, end int
+	if f.longLoca {
+		if 4*int(gid)+8 > len(loca) {
+			return nil
+		}
+		start = int(binary.BigEndian.Uint32(loca[4*int(gid):]))
+		end = int(binary.BigEndian.Uint32(loca[4*int(gid)+4:]))
+	} else {
+		if 2*int(gid)+4 > len(loca) {
+			return nil
+		}
+		start = 2 * int(binary.BigEndian.Uint16(loca[2*int(gid):]))
+		end = 2 * int(binary.BigEndian.Uint16(loca[2*int(gid)+2:]))
+	}
+	if start >= end || end > len(glyf) {
+		return nil
+	}
+	return glyf[start:end]
+}
+
+// glyphComponents returns the glyphs a composite glyph is built from
+func (f *trueTypeFont) glyphComponents(gid uint16) []uint16 {
+	const (
+		argsAreWords  = 0x0001
+		haveScale     = 0x0008
+		moreComponent = 0x0020
+		haveXYScale   = 0x0040
+		haveTwoByTwo  = 0x0080
+	)
+	g := f.glyph(gid)
+	if len(g) < 10 || int16(binary.BigEndian.Uint16(g)) >= 0 {
+		return nil
+	}
+	var components []uint16
+	for at := 10; at+4 <= len(g); {
+		flags := binary.BigEndian.Uint16(g[at:])
+		components = append(components, binary.BigEndian.Uint16(g[at+2:]))
+		at += 4
+		if flags&argsAreWords != 0 {
+			at += 4
+		} else {
+			at += 2
+		}
+		switch {
+		case flags&haveScale != 0:
+			at += 2
+		case flags&haveXYScale != 0:
+			at += 4
+		case flags&haveTwoByTwo != 0:
+			at += 8
+		}
+		if flags&moreComponent == 0 {
+			break
+		}
+	}
+	return components
+}
+
+// subset builds a font file containing only the glyphs in used. Glyph ids are preserved so that the text already
+// written with them stays valid; the glyphs that are not needed are left empty.
+func (f *trueTypeFont) subset(used map[uint16]rune) []byte {
+	gids := make([]uint16, 0, len(used))
+	for gid := range used {
+		gids = append(gids, gid)
+	}
+	glyphs := f.subsetGlyphs(gids)
+	numGlyphs := 1
+	for gid := range glyphs {
+		if int(gid)+1 > numGlyphs {
+			numGlyphs = int(gid) + 1
+		}
+	}
+
+	var glyf []byte
+	loca := make([]byte, 4*(numGlyphs+1))
+	hmtx := make([]byte, 4*numGlyphs)
+	for gid := 0; gid < numGlyphs; gid++ {
+		binary.BigEndian.PutUint32(loca[4*gid:], uint32(len(glyf)))
+		binary.BigEndian.PutUint16(hmtx[4*gid:], uint16(f.advances[gid]))
+		if glyphs[uint16(gid)] {
+			binary.BigEndian.PutUint16(hmtx[4*gid+2:], f.leftSideBearing(uint16(gid)))
+			glyf = append(glyf, f.glyph(uint16(gid))...)
+			// glyphs are aligned to four bytes
+			for len(glyf)%4 != 0 {
+				glyf = append(glyf, 0)
+			}
+		}
+	}
+	binary.BigEndian.PutUint32(loca[4*numGlyphs:], uint32(len(glyf)))
+
+	tables := map[string][]byte{
+		"glyf": glyf,
+		"loca": loca,
+		"hmtx": hmtx,
+		"cmap": subsetCmap(used),
+		"post": subsetPost(f.tables["post"]),
+	}
+	for _, tag := range subsetKeptTables {
+		if t, ok := f.tables[tag]; ok {
+			tables[tag] = append([]byte(nil), t...)
+		}
+	}
+	// long loca offsets, no checksum adjustment until the whole file is known
+	head := tables["head"]
+	binary.BigEndian.PutUint32(head[8:], 0)
+	binary.BigEndian.PutUint16(head[50:], 1)
+	binary.BigEndian.PutUint16(tables["hhea"][34:], uint16(numGlyphs))
+	maxp := append([]byte(nil), f.tables["maxp"]...)
+	binary.BigEndian.PutUint16(maxp[4:], uint16(numGlyphs))
+	tables["maxp"] = maxp
+
+	data := buildTrueType(tables)
+	headOffset := tableOffset(data, "head")
+	binary.BigEndian.PutUint32(data[headOffset+8:], 0xB1B0AFBA-tableChecksum(data))
+	return data
+}
+
+// leftSideBearing returns the left side bearing of a glyph from the hmtx table
+func (f *trueTypeFont) leftSideBearing(gid uint16) uint16 {
+	hmtx := f.tables["hmtx"]
+	numHMetrics := int(binary.BigEndian.Uint16(f.tables["hhea"][34:]))
+	at := 4*int(gid) + 2
+	if int(gid) >= numHMetrics {
+		at = 4*numHMetrics + 2*(int(gid)-numHMetrics)
+	}
+	if at+2 > len(hmtx) {
+		return 0
+	}
+	return binary.BigEndian.Uint16(hmtx[at:])
+}
+
+// subsetCmap builds a Windows Unicode cmap covering the characters in used that are in the Basic Multilingual
+// Plane, with a segment for each character
+func subsetCmap(used map[uint16]rune) []byte {
+	chars := make(map[uint16]uint16)
+	for gid, r := range used {
+		if r > 0 && r < 0xFFFF {
+			chars[uint16(r)] = gid
+		}
+	}
+	codes := make([]int, 0, len(chars))
+	for c := range chars {
+		codes = append(codes, int(c))
+	}
+	sort.Ints(codes)
+	// the final segment maps 0xFFFF to .notdef as the format requires
+	segCount := len(codes) + 1
+	sub := make([]byte, 16+8*segCount)
+	binary.BigEndian.PutUint16(sub, 4)
+	binary.BigEndian.PutUint16(sub[2:], uint16(len(sub)))
+	binary.BigEndian.PutUint16(sub[6:], uint16(2*segCount))
+	searchRange, entrySelector := 1, 0
+	for searchRange*2 <= segCount {
+		searchRange *= 2
+		entrySelector++
+	}
+	binary.BigEndian.PutUint16(sub[8:], uint16(2*searchRange))
+	binary.BigEndian.PutUint16(sub[10:], uint16(entrySelector))
+	binary.BigEndian.PutUint16(sub[12:], uint16(2*segCount-2*searchRange))
+	ends, starts, deltas := 14, 16+2*segCount, 16+4*segCount
+	for i, c := range append(codes, 0xFFFF) {
+		binary.BigEndian.PutUint16(sub[ends+2*i:], uint16(c))
+		binary.BigEndian.PutUint16(sub[starts+2*i:], uint16(c))
+		delta := 1
+		if c != 0xFFFF {
+			delta = int(chars[uint16(c)]) - c
+		}
+		binary.BigEndian.PutUint16(sub[deltas+2*i:], uint16(delta))
+	}
+
+	cmap := make([]byte, 12, 12+len(sub))
+	binary.BigEndian.PutUint16(cmap[2:], 1)
+	binary.BigEndian.PutUint16(cmap[4:], 3)
+	binary.BigEndian.PutUint16(cmap[6:], 1)
+	binary.BigEndian.PutUint32(cmap[8:], 12)
+	return append(cmap, sub...)
+}
+
+// subsetPost keeps the metrics of a post table but drops the glyph names, which no longer match the glyphs
+func subsetPost(post []byte) []byte {
+	p := make([]byte, 32)
+	copy(p, post)
+	binary.BigEndian.PutUint32(p, 0x00030000)
+	return p
+}
+
+// buildTrueType writes a font file with the given tables in tag order
+func buildTrueType(tables map[string][]byte) []byte {
+	tags := make([]string, 0, len(tables))
+	for tag := range tables {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	numTables := len(tags)
+	searchRange, entrySelector := 1, 0
+	for searchRange*2 <= numTables {
+		searchRange *= 2
+		entrySelector++
+	}
+	data := make([]byte, 12+16*numTables)
+	binary.BigEndian.PutUint32(data, 0x00010000)
+	binary.BigEndian.PutUint16(data[4:], uint16(numTables))
+	binary.BigEndian.PutUint16(data[6:], uint16(16*searchRange))
+	binary.BigEndian.PutUint16(data[8:], uint16(entrySelector))
+	binary.BigEndian.PutUint16(data[10:], uint16(16*numTables-16*searchRange))
+	for i, tag := range tags {
+		t := tables[tag]
+		rec := data[12+16*i:]
+		copy(rec, tag)
+		binary.BigEndian.PutUint32(rec[4:], tableChecksum(t))
+		binary.BigEndian.PutUint32(rec[8:], uint32(len(data)))
+		binary.BigEndian.PutUint32(rec[12:], uint32(len(t)))
+		data = append(data, t...)
+		for len(data)%4 != 0 {
+			data = append(data, 0)
+		}
+	}
+	return data
+}
+
+// tableOffset returns the offset of a table in a font file written by buildTrueType
+func tableOffset(data []byte, tag string) int {
+	numTables := int(binary.BigEndian.Uint16(data[4:]))
+	for i := 0; i < numTables; i++ {
+		rec := data[12+16*i:]
+		if string(rec[:4]) == tag {
+			return int(binary.BigEndian.Uint32(rec[8:]))
+		}
+	}
+	return -1
+}
+
+// tableChecksum is the sum of the data as big endian 32 bit words, padded with zeros
+func tableChecksum(data []byte) uint32 {
+	var sum uint32
+	for i := 0; i < len(data); i += 4 {
+		var word [4]byte
+		copy(word[:], data[i:])
+		sum += binary.BigEndian.Uint32(word[:])
+	}
+	return sum
+}
+
+// subsetTag returns the six capital letters that prefix the name of a subset font. It is derived from the glyphs
+// in the subset so that the same document always produces the same name.
+func subsetTag(used []uint16) string {
+	h := fnv.New32a()
+	for _, gid := range used {
+		h.Write([]byte{byte(gid >> 8), byte(gid)})
+	}
+	sum := h.Sum32()
+	tag := make([]byte, 6)
+	for i := range tag {
+		tag[i] = 'A' + byte(sum%26)
+		sum /= 26
+	}
+	return string(tag)
+}
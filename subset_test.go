@@ -0,0 +1,80 @@
+package gopdf
+
+import (
+	"os"
+	"testing"
+)
+
+// embeddedFontFile returns the FontFile2 stream of the embedded font F on the first page of a PDF file, and the font
+// file it holds
+func embeddedFontFile(t *testing.T, data []byte) (*pdfStream, []byte) {
+	t.Helper()
+	pr, err := parsePDF(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pages, err := pr.pages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	resources, _ := pr.resolve(pages[0].dict.get("Resources")).(pdfDict)
+	fonts, _ := pr.resolve(resources.get("Font")).(pdfDict)
+	font, _ := pr.resolve(fonts.get("F")).(pdfDict)
+	descendants, _ := pr.resolve(font.get("DescendantFonts")).([]any)
+	if len(descendants) != 1 {
+		t.Fatalf("font F has descendants %v", descendants)
+	}
+	cidFont, _ := pr.resolve(descendants[0]).(pdfDict)
+	descriptor, _ := pr.resolve(cidFont.get("FontDescriptor")).(pdfDict)
+	stream, ok := pr.resolve(descriptor.get("FontFile2")).(*pdfStream)
+	if !ok {
+		t.Fatal("font F has no FontFile2")
+	}
+	file, err := pr.decode(stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return stream, file
+}
+
+func TestSubsetSize(t *testing.T) {
+	const path = "testdata/DejaVuSansMono.ttf"
+	full, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	embed := func(subset bool) (*pdfStream, []byte) {
+		d := NewPdfDocument()
+		d.SetFontSubsetting(subset)
+		if _, err := d.AddUnicodeFont("F", path); err != nil {
+			t.Fatal(err)
+		}
+		p := d.CurrentPage()
+		p.SetFont("F")
+		p.Print("Subset: 20 glyphs ÅΩ")
+		data, err := d.Bytes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return embeddedFontFile(t, data)
+	}
+
+	whole, wholeFile := embed(false)
+	subset, subsetFile := embed(true)
+	if string(wholeFile) != string(full) {
+		t.Errorf("the whole font is embedded as %v bytes, not the %v of the file", len(wholeFile), len(full))
+	}
+	if len(subset.data)*10 > len(whole.data) {
+		t.Errorf("the subset stream is %v bytes, not an order of magnitude smaller than the whole font's %v",
+			len(subset.data), len(whole.data))
+	}
+	ttf, err := parseTrueType(subsetFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range "Subset: 20 glyphs ÅΩ" {
+		if gid, ok := ttf.cmap[r]; !ok || len(ttf.glyph(gid)) == 0 && r != ' ' {
+			t.Errorf("the subset has no glyph for %q", r)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package gopdf
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Table lays out rows of text in columns with borders, wrapping the text of each cell to fit its column and
+// continuing on new pages as needed
+type Table struct {
+	widths  []float64
+	header  *TableRow
+	rows    []*TableRow
+	padding float64
+}
+
+// TableRow is a row of a table
+type TableRow struct {
+	cells []*TableCell
+}
+
+// TableCell is a cell of a table. By default its text is left aligned in the font, size and colour that were
+// current when the table was drawn.
+type TableCell struct {
+	text     string
+	align    Alignment
+	font     string
+	fontSize float64
+	colour   *[3]int
+}
+
+// NewTable creates a table with columns of the given widths, in the unit of the document it is drawn in
+func NewTable(columnWidths []float64) *Table {
+	return &Table{widths: columnWidths, padding: 3}
+}
+
+// SetPadding sets the space between the borders of the cells and their text
+func (t *Table) SetPadding(padding float64) {
+	t.padding = padding
+}
+
+// SetHeader sets a header row, which is drawn at the top of the table and again at the top of every page the table
+// continues onto
+func (t *Table) SetHeader(cells []string) *TableRow {
+	t.header = newTableRow(cells)
+	return t.header
+}
+
+// AddRow adds a row to the end of the table. Missing cells are left empty and extra cells are ignored.
+func (t *Table) AddRow(cells []string) *TableRow {
+	row := newTableRow(cells)
+	t.rows = append(t.rows, row)
+	return row
+}
+
+func newTableRow(cells []string) *TableRow {
+	row := &TableRow{}
+	for _, text := range cells {
+		row.cells = append(row.cells, &TableCell{text: text})
+	}
+	return row
+}
+
+// Cell returns the cell in column i of the row, or nil if the row has no such cell
+func (r *TableRow) Cell(i int) *TableCell {
+	if i < 0 || i >= len(r.cells) {
+		return nil
+	}
+	return r.cells[i]
+}
+
+// SetAlign sets the alignment of the text in the cell
+func (c *TableCell) SetAlign(align Alignment) {
+	c.align = align
+}
+
+// SetFont sets the font and size of the text in the cell
+func (c *TableCell) SetFont(name string, size float64) {
+	c.font = name
+	c.fontSize = size
+}
+
+// SetColour sets the colour of the text in the cell, with components from 0 to 255
+func (c *TableCell) SetColour(red, green, blue int) {
+	c.colour = &[3]int{red, green, blue}
+}
+
+// Draw draws the table on page with its top left corner at x, y. When a row doesn't fit above the bottom margin a
+// new page is added and the table continues below its top margin, starting with the header row. Draw returns the
+// page the table ends on, with the cursor at the left margin below the table.
+func (t *Table) Draw(page *PdfPage, x, y float64) (*PdfPage, error) {
+	// measure the table in points
+	converted := *t
+	converted.widths = make([]float64, len(t.widths))
+	for i, w := range t.widths {
+		converted.widths[i] = page.pt(w)
+	}
+	converted.padding = page.pt(t.padding)
+	t = &converted
+	x, y = page.point(x, y)
+
+	font, size := "", page.fontSize
+	if page.font != nil {
+		font = page.font.name
+	}
+	p := page
+	if t.header != nil {
+		var err error
+		if y, err = t.drawRow(p, t.header, x, y, font, size); err != nil {
+			return p, err
+		}
+	}
+	for _, row := range t.rows {
+		height, err := t.rowHeight(p, row, font, size)
+		if err != nil {
+			return p, err
+		}
+		if y-height < p.bottomMargin && y < p.height-p.topMargin {
+			p = p.continuation(font, size)
+			y = p.height - p.topMargin
+			if t.header != nil {
+				if y, err = t.drawRow(p, t.header, x, y, font, size); err != nil {
+					return p, err
+				}
+			}
+		}
+		if y, err = t.drawRow(p, row, x, y, font, size); err != nil {
+			return p, err
+		}
+	}
+	p.fontSize = size
+	if font != "" {
+		p.SetFont(font)
+	}
+	p.x = p.leftMargin
+	p.y = y - p.fontSize
+	return p, nil
+}
+
+// useCellFont makes the font of a cell current, falling back to the font and size the table is drawn in
+func (c *TableCell) useCellFont(p *PdfPage, font string, size float64) error {
+	if c.fontSize != 0 {
+		size = c.fontSize
+	}
+	if c.font != "" {
+		font = c.font
+	}
+	p.fontSize = size
+	if font == "" {
+		p.font = nil
+		return nil
+	}
+	return p.SetFont(font)
+}
+
+// cellLines returns the text of the cell wrapped to fit its column
+func (t *Table) cellLines(p *PdfPage, c *TableCell, column int) []string {
+	width := t.widths[column] - 2*t.padding
+	var lines []string
+	for _, paragraph := range strings.Split(c.text, "\n") {
+		wrapped := p.wrapText(paragraph, width, width)
+		if len(wrapped) == 0 {
+			wrapped = []string{""}
+		}
+		lines = append(lines, wrapped...)
+	}
+	return lines
+}
+
+// rowHeight returns the height of the row, enough for its tallest cell
+func (t *Table) rowHeight(p *PdfPage, row *TableRow, font string, size float64) (float64, error) {
+	height := float64(size) + 2*t.padding
+	for i := range t.widths {
+		c := row.Cell(i)
+		if c == nil {
+			continue
+		}
+		if err := c.useCellFont(p, font, size); err != nil {
+			return 0, err
+		}
+		cellHeight := float64(len(t.cellLines(p, c, i)))*p.fontSize + 2*t.padding
+		height = math.Max(height, cellHeight)
+	}
+	return height, nil
+}
+
+// drawRow draws a row with its top at y and returns the y position of its bottom
+func (t *Table) drawRow(p *PdfPage, row *TableRow, x, y float64, font string, size float64) (float64, error) {
+	height, err := t.rowHeight(p, row, font, size)
+	if err != nil {
+		return y, err
+	}
+	var borders strings.Builder
+	cellX := x
+	for i, width := range t.widths {
+		fmt.Fprintf(&borders, "%v %v %v %v re\r\n", formatNumber(cellX), formatNumber(y-height), formatNumber(width), formatNumber(height))
+		if c := row.Cell(i); c != nil && c.text != "" {
+			if err := c.useCellFont(p, font, size); err != nil {
+				return y, err
+			}
+			p.content.addGraphics("q\r\n")
+			if c.colour != nil {
+				p.SetFillColor(c.colour[0], c.colour[1], c.colour[2])
+			}
+			// the first baseline leaves room for the ascenders, about 80% of the font size
+			baseline := y - t.padding - 0.8*p.fontSize
+			lines := t.cellLines(p, c, i)
+			for j, line := range lines {
+				p.y = baseline
+				align := c.align
+				if align == AlignJustify && j == len(lines)-1 {
+					align = AlignLeft
+				}
+				p.outputAligned(line, cellX+t.padding, width-2*t.padding, align)
+				baseline -= p.fontSize
+			}
+			p.content.addGraphics("Q\r\n")
+		}
+		cellX += width
+	}
+	p.content.addGraphics(borders.String() + "S\r\n")
+	return y - height, nil
+}
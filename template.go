@@ -0,0 +1,88 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// PdfTemplate is a form XObject holding drawing that is recorded once and can be placed on any number of pages,
+// such as a letterhead
+type PdfTemplate struct {
+	PdfObject
+	name          string
+	width, height float64
+	content       *PdfPageContent
+	imported      *pdfStream // the content and form dictionary entries of a page imported from a PDF file
+}
+
+// Template is what a template is drawn on. It has the same text and drawing methods as a page, with the origin at
+// the corner of the template given by the document's coordinate origin and no margins. Links and other annotations can't be added to a template.
+type Template struct {
+	*PdfPage
+}
+
+// NewTemplate adds a template w by h to the document under the given name, drawn by calling draw. Templates
+// share their names with images.
+func (d *PdfDocument) NewTemplate(name string, w, h float64, draw func(t *Template)) (*PdfTemplate, error) {
+	if err := d.checkImageName(name); err != nil {
+		return nil, err
+	}
+	w, h = w*float64(d.unit), h*float64(d.unit)
+	p := d.newPage(w, h, [4]float64{})
+	draw(&Template{p})
+	p.closeSavedStates()
+	t := &PdfTemplate{name: name, width: w, height: h, content: p.content}
+	d.addObject(t)
+	d.resources.templates = append(d.resources.templates, t)
+	return t, nil
+}
+
+// UseTemplate draws a named template with its corner at x, y. Names are case sensitive, and the error
+// for an unknown name lists the templates that have been added.
+func (p *PdfPage) UseTemplate(name string, x, y float64) error {
+	var t *PdfTemplate
+	for _, template := range p.document.resources.templates {
+		if template.name == name {
+			t = template
+		}
+	}
+	if t == nil {
+		names := make([]string, len(p.document.resources.templates))
+		for i, template := range p.document.resources.templates {
+			names[i] = template.name
+		}
+		return fmt.Errorf("%w: %v (%v)", ErrTemplateNotFound, name, availableNames("templates", names))
+	}
+	p.use(t)
+	p.content.addGraphicsf("q\r\n1 0 0 1 %v %v cm\r\n%v Do\r\nQ\r\n", formatNumber(p.pt(x)), formatNumber(p.ptY(y, t.height)),
+		formatName(name))
+	return nil
+}
+
+func (t PdfTemplate) bytes() []byte {
+	if t.imported != nil {
+		return t.importedBytes()
+	}
+	stream := append([]byte(nil), t.content.stream.Bytes()...)
+	if t.content.inText {
+		stream = append(stream, "ET\r\n"...)
+	}
+	entries := fmt.Sprintf("/Type /XObject\r\n/Subtype /Form\r\n/BBox [ 0 0 %v %v ]\r\n/Resources %v\r\n", formatNumber(t.width), formatNumber(t.height), t.document.resources.objectRef())
+	if !t.document.compressContent() {
+		return streamObject(t.id, entries, stream)
+	}
+	return streamObject(t.id, entries+"/Filter /FlateDecode\r\n", deflate(stream))
+}
+
+// importedBytes returns the form XObject of a page imported by ImportPageAsTemplate
+func (t PdfTemplate) importedBytes() []byte {
+	var entries bytes.Buffer
+	fmt.Fprintf(&entries, "/Type /XObject\r\n/Subtype /Form\r\n")
+	for _, e := range t.imported.dict {
+		fmt.Fprintf(&entries, "%v %v\r\n", formatName(string(e.key)), formatValue(e.value))
+	}
+	if t.document.noCompression {
+		return streamObject(t.id, entries.String(), t.imported.data)
+	}
+	return streamObject(t.id, entries.String()+"/Filter /FlateDecode\r\n", deflate(t.imported.data))
+}
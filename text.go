@@ -0,0 +1,272 @@
+package gopdf
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+// fontWidths returns the glyph widths for the current font, falling back to Helvetica before a font has been set
+func (p *PdfPage) fontWidths() *[256]int {
+	if p.font != nil {
+		if widths, ok := coreFontWidths[p.font.baseFont]; ok {
+			return widths
+		}
+	}
+	return coreFontWidths["Helvetica"]
+}
+
+// TextWidth returns the width of text in the document's unit when printed in the current font and size.
+// Text is measured after conversion to the font's encoding, so characters that will be replaced are measured as
+// the replacement character.
+// Character spacing, word spacing and horizontal scaling are included.
+func (p *PdfPage) TextWidth(text string) float64 {
+	return p.inUnit(p.textWidth(text))
+}
+
+// textWidth returns the width of text in points as for TextWidth
+func (p *PdfPage) textWidth(text string) float64 {
+	encoded, _, _ := p.encodeText(text, false)
+	width := float64(p.encodedWidth(encoded)) * p.fontSize / 1000
+	if p.font != nil && p.font.unicode != nil {
+		width += float64(len(encoded)/2) * p.charSpacing
+	} else {
+		// word spacing only applies to the single byte code 32 in simple fonts
+		width += float64(len(encoded))*p.charSpacing + float64(strings.Count(encoded, " "))*p.wordSpacing
+	}
+	return width * p.horizontalScaling / 100
+}
+
+// encodedWidth returns the width of text already in the encoding of the current font, in thousandths of a point at
+// a font size of 1
+func (p *PdfPage) encodedWidth(encoded string) int {
+	if p.font != nil && p.font.unicode != nil {
+		return p.font.unicode.width(encoded)
+	}
+	widths := p.fontWidths()
+	total := 0
+	for i := 0; i < len(encoded); i++ {
+		total += widths[encoded[i]]
+	}
+	return total
+}
+
+// WriteWrapped outputs text starting at the cursor, breaking it into lines at word boundaries so that it fits
+// between the margins, or in the current column after BeginColumns. Each line is output as if by Println. Runs of
+// spaces between words are collapsed and a word too long to fit on a line by itself is broken where it reaches the
+// right margin.
+func (p *PdfPage) WriteWrapped(text string) {
+	p = p.flow()
+	first := p.lineRight() - p.x
+	rest := p.lineRight() - p.lineLeft()
+	for _, line := range p.wrapText(text, first, rest) {
+		p.Println(line)
+	}
+}
+
+// wrapText splits text into lines, the first no wider than first and the others no wider than rest
+func (p *PdfPage) wrapText(text string, first, rest float64) []string {
+	var lines []string
+	available := first
+	line := ""
+	for _, word := range strings.Fields(text) {
+		candidate := word
+		if line != "" {
+			candidate = line + " " + word
+		}
+		if p.textWidth(candidate) <= available {
+			line = candidate
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+			available = rest
+		}
+		for p.textWidth(word) > available {
+			n := p.fittingPrefix(word, available)
+			lines = append(lines, word[:n])
+			available = rest
+			word = word[n:]
+		}
+		line = word
+	}
+	if line != "" || len(lines) == 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// fittingPrefix returns the length in bytes of the longest prefix of word that fits in width, which is always at
+// least one character so that progress is made even when a single character is wider than the line
+func (p *PdfPage) fittingPrefix(word string, width float64) int {
+	_, n := utf8.DecodeRuneInString(word)
+	for n < len(word) {
+		_, size := utf8.DecodeRuneInString(word[n:])
+		if p.textWidth(word[:n+size]) > width {
+			break
+		}
+		n += size
+	}
+	return n
+}
+
+// TextRenderMode selects how the glyphs of text are painted
+type TextRenderMode int
+
+// Text render modes. The clip modes add the glyph outlines to the clipping path as well, and TextInvisible draws
+// nothing, which is useful for a searchable text layer over a scanned image.
+const (
+	TextFill TextRenderMode = iota
+	TextStroke
+	TextFillStroke
+	TextInvisible
+	TextFillClip
+	TextStrokeClip
+	TextFillStrokeClip
+	TextClip
+)
+
+// SetTextRenderMode sets how text printed after this call is painted. The stroke modes use the stroke colour and
+// line width.
+func (p *PdfPage) SetTextRenderMode(mode TextRenderMode) {
+	p.content.addTextf("%v Tr\r\n", int(mode))
+}
+
+// SetCharSpacing adds pts points of space after every character of text printed after this call
+func (p *PdfPage) SetCharSpacing(pts float64) {
+	p.charSpacing = pts
+	p.content.addTextf("%v Tc\r\n", formatNumber(pts))
+}
+
+// SetWordSpacing adds pts points of space after every space character of text printed after this call. It has no
+// effect on text in fonts added with AddUnicodeFont.
+func (p *PdfPage) SetWordSpacing(pts float64) {
+	p.wordSpacing = pts
+	p.content.addTextf("%v Tw\r\n", formatNumber(pts))
+}
+
+// SetHorizontalScaling stretches or squeezes text printed after this call to percent of its normal width
+func (p *PdfPage) SetHorizontalScaling(percent float64) {
+	p.horizontalScaling = percent
+	p.content.addTextf("%v Tz\r\n", formatNumber(percent))
+}
+
+// ResetTextSpacing restores the default character spacing, word spacing and horizontal scaling
+func (p *PdfPage) ResetTextSpacing() {
+	p.SetCharSpacing(0)
+	p.SetWordSpacing(0)
+	p.SetHorizontalScaling(100)
+}
+
+// SetLineHeight sets the distance between lines printed with Println to multiplier times the font size, so 1.5
+// gives one and a half line spacing. The distance changes with the font size.
+func (p *PdfPage) SetLineHeight(multiplier float64) {
+	p.lineHeight = multiplier
+	p.leading = 0
+	p.content.addTextf("%v TL\r\n", formatNumber(p.lineAdvance()))
+}
+
+// SetLeading sets the distance between lines printed with Println to a fixed number of points, whatever the font
+// size
+func (p *PdfPage) SetLeading(points float64) {
+	p.leading = points
+	p.lineHeight = 0
+	p.content.addTextf("%v TL\r\n", formatNumber(p.lineAdvance()))
+}
+
+// SetTabSize sets the distance between the tab stops used by Print and Println to the width of the given number of
+// spaces in the current font. Tab stops are measured from the left margin, or the left edge of the current column.
+func (p *PdfPage) SetTabSize(spaces int) {
+	p.tabSize = spaces
+}
+
+// nextTabStop returns the position of the first tab stop after the cursor
+func (p *PdfPage) nextTabStop() float64 {
+	stop := float64(p.tabSize) * p.textWidth(" ")
+	if stop <= 0 {
+		return p.x
+	}
+	n := math.Floor((p.x-p.lineLeft())/stop) + 1
+	return p.lineLeft() + n*stop
+}
+
+// lineAdvance returns the distance in points between the baselines of lines
+func (p *PdfPage) lineAdvance() float64 {
+	switch {
+	case p.leading > 0:
+		return p.leading
+	case p.lineHeight > 0:
+		return p.lineHeight * p.fontSize
+	}
+	return p.fontSize
+}
+
+// SetUnderline turns underlining of printed text on or off
+func (p *PdfPage) SetUnderline(underline bool) {
+	p.underline = underline
+}
+
+// SetStrikethrough turns striking through printed text on or off
+func (p *PdfPage) SetStrikethrough(strikethrough bool) {
+	p.strikethrough = strikethrough
+}
+
+// decorationMetrics returns the position of the centre of the underline and strikeout lines above the baseline,
+// and their thickness, in thousandths of the font size
+func (p *PdfPage) decorationMetrics() (underlinePos, underlineThick, strikeoutPos, strikeoutThick int) {
+	if p.font != nil && p.font.unicode != nil {
+		ttf := p.font.unicode.ttf
+		return ttf.underlinePos, ttf.underlineThick, ttf.strikeoutPos, ttf.strikeoutThick
+	}
+	// the core fonts all use the same underline, and the strikeout sits at about half the x height
+	return -100, 50, 250, 50
+}
+
+// decorateText draws the underline and strikeout lines for text of the given width printed at x on the current line
+func (p *PdfPage) decorateText(x, width float64) {
+	if !p.underline && !p.strikethrough {
+		return
+	}
+	underlinePos, underlineThick, strikeoutPos, strikeoutThick := p.decorationMetrics()
+	size := p.fontSize / 1000
+	var ops strings.Builder
+	line := func(pos, thick int) {
+		y := p.y + p.textRise + float64(pos)*size - float64(thick)*size/2
+		fmt.Fprintf(&ops, "%v %v %v %v re\r\nf\r\n", formatNumber(x), formatNumber(y), formatNumber(width), formatNumber(float64(thick)*size))
+	}
+	if p.underline {
+		line(underlinePos, underlineThick)
+	}
+	if p.strikethrough {
+		line(strikeoutPos, strikeoutThick)
+	}
+	p.content.addGraphics(ops.String())
+}
+
+// SetTextRise moves the baseline of text printed after this call up by rise points, or down when rise is negative
+func (p *PdfPage) SetTextRise(rise float64) {
+	p.textRise = rise
+	p.content.addTextf("%v Ts\r\n", formatNumber(rise))
+}
+
+// PrintSuper prints text as a superscript, smaller and above the baseline, and leaves the cursor at the end of it
+func (p *PdfPage) PrintSuper(text string) {
+	p.printRaised(text, 0.33)
+}
+
+// PrintSub prints text as a subscript, smaller and below the baseline, and leaves the cursor at the end of it
+func (p *PdfPage) PrintSub(text string) {
+	p.printRaised(text, -0.14)
+}
+
+// printRaised prints text at 58% of the font size with its baseline moved by rise times the font size, then
+// restores the size and baseline
+func (p *PdfPage) printRaised(text string, rise float64) {
+	size, textRise := p.fontSize, p.textRise
+	p.SetFontSize(size * 0.58)
+	p.SetTextRise(textRise + rise*size)
+	p.Print(text)
+	p.SetTextRise(textRise)
+	p.SetFontSize(size)
+}
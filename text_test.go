@@ -0,0 +1,39 @@
+package gopdf
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInvisibleTextExtracted(t *testing.T) {
+	d := NewPdfDocument()
+	if _, err := d.AddFont("Helv", Helvetica); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.AddImage("scan", "gopher.jpg"); err != nil {
+		t.Fatal(err)
+	}
+	// a searchable text layer over a scanned page
+	p := d.CurrentPage()
+	if err := p.DrawImageScaled("scan", 0, 0, 595, 842); err != nil {
+		t.Fatal(err)
+	}
+	p.SetFont("Helv")
+	p.SetTextRenderMode(TextInvisible)
+	p.Print("Scanned invoice 1234")
+	p.SetTextRenderMode(TextFill)
+	p.Print(" total €56")
+
+	content := p.content.stream.String()
+	invisible := strings.Index(content, "3 Tr")
+	if invisible < 0 || invisible > strings.Index(content, "(Scanned invoice 1234) Tj") {
+		t.Errorf("the text isn't shown invisible in %q", content)
+	}
+	data, err := d.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := extractText(t, data), "Scanned invoice 1234 total €56"; got != want {
+		t.Errorf("extracted %q, want %q", got, want)
+	}
+}
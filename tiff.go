@@ -0,0 +1,396 @@
+package gopdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"math/bits"
+)
+
+// TIFF tags that the reader uses
+const (
+	tiffImageWidth    = 256
+	tiffImageLength   = 257
+	tiffBitsPerSample = 258
+	tiffCompression   = 259
+	tiffPhotometric   = 262
+	tiffFillOrder     = 266
+	tiffStripOffsets  = 273
+	tiffSamplesPerPix = 277
+	tiffStripBytes    = 279
+	tiffPlanarConfig  = 284
+	tiffT4Options     = 292
+	tiffPredictor     = 317
+	tiffColorMap      = 320
+	tiffTileWidth     = 322
+)
+
+// tiffCompressionNames names the compression schemes in errors
+var tiffCompressionNames = map[int]string{
+	1: "none", 2: "CCITT modified Huffman", 3: "CCITT Group 3", 4: "CCITT Group 4", 5: "LZW", 6: "old-style JPEG",
+	7: "JPEG", 8: "Deflate", 32773: "PackBits", 32946: "Deflate", 34712: "JPEG 2000",
+}
+
+// tiff is the first image in a TIFF file
+type tiff struct {
+	order  binary.ByteOrder
+	fields map[uint16][]uint32
+}
+
+// isTIFF reports whether file starts with a TIFF header
+func isTIFF(file []byte) bool {
+	return bytes.HasPrefix(file, []byte("II*\x00")) || bytes.HasPrefix(file, []byte("MM\x00*"))
+}
+
+// parseTIFF reads the tags of the first image in a TIFF file
+func parseTIFF(file []byte) (*tiff, error) {
+	if len(file) < 8 {
+		return nil, fmt.Errorf("%w: TIFF header is truncated", ErrUnsupportedImage)
+	}
+	t := &tiff{order: binary.LittleEndian, fields: map[uint16][]uint32{}}
+	if file[0] == 'M' {
+		t.order = binary.BigEndian
+	}
+	ifd := int(t.order.Uint32(file[4:]))
+	if ifd < 8 || ifd+2 > len(file) {
+		return nil, fmt.Errorf("%w: TIFF directory is outside the file", ErrUnsupportedImage)
+	}
+	entries := int(t.order.Uint16(file[ifd:]))
+	for i := 0; i < entries; i++ {
+		at := ifd + 2 + 12*i
+		if at+12 > len(file) {
+			return nil, fmt.Errorf("%w: TIFF directory is truncated", ErrUnsupportedImage)
+		}
+		tag, typ, count := t.order.Uint16(file[at:]), t.order.Uint16(file[at+2:]), int(t.order.Uint32(file[at+4:]))
+		size := map[uint16]int{1: 1, 3: 2, 4: 4}[typ]
+		if size == 0 {
+			// types the reader doesn't need, such as text and rationals
+			continue
+		}
+		values := file[at+8 : at+12]
+		if size*count > 4 {
+			offset := int(t.order.Uint32(values))
+			if offset < 0 || count > len(file) || offset+size*count > len(file) {
+				return nil, fmt.Errorf("%w: TIFF tag %v is outside the file", ErrUnsupportedImage, tag)
+			}
+			values = file[offset : offset+size*count]
+		}
+		field := make([]uint32, count)
+		for j := range field {
+			switch size {
+			case 1:
+				field[j] = uint32(values[j])
+			case 2:
+				field[j] = uint32(t.order.Uint16(values[2*j:]))
+			case 4:
+				field[j] = t.order.Uint32(values[4*j:])
+			}
+		}
+		t.fields[tag] = field
+	}
+	return t, nil
+}
+
+// value returns the first value of a tag, or def if it is missing
+func (t *tiff) value(tag uint16, def int) int {
+	if v := t.fields[tag]; len(v) > 0 {
+		return int(v[0])
+	}
+	return def
+}
+
+// strips returns the data of each strip of the image
+func (t *tiff) strips(file []byte) ([][]byte, error) {
+	offsets, counts := t.fields[tiffStripOffsets], t.fields[tiffStripBytes]
+	if len(offsets) == 0 || len(offsets) != len(counts) {
+		return nil, fmt.Errorf("%w: TIFF has no strips", ErrUnsupportedImage)
+	}
+	strips := make([][]byte, len(offsets))
+	for i, offset := range offsets {
+		end := uint64(offset) + uint64(counts[i])
+		if end > uint64(len(file)) {
+			return nil, fmt.Errorf("%w: TIFF strip %v is outside the file", ErrUnsupportedImage, i)
+		}
+		strips[i] = file[offset:end]
+	}
+	return strips, nil
+}
+
+// loadTIFF loads the first image of a TIFF file. CCITT fax compressed images are used as they are, since viewers
+// can decode them, and other images are decoded.
+func (pi *PdfImage) loadTIFF(file []byte) error {
+	t, err := parseTIFF(file)
+	if err != nil {
+		return err
+	}
+	switch compression := t.value(tiffCompression, 1); compression {
+	case 2, 3, 4:
+		return pi.loadCCITT(t, file, compression)
+	}
+	img, err := t.decode(file)
+	if err != nil {
+		return err
+	}
+	return pi.loadPixels(img)
+}
+
+// loadCCITT uses the CCITT fax compressed data of a bilevel TIFF as it is
+func (pi *PdfImage) loadCCITT(t *tiff, file []byte, compression int) error {
+	strips, err := t.strips(file)
+	if err != nil {
+		return err
+	}
+	if compression == 4 && len(strips) > 1 {
+		// each strip is coded from an imaginary white line, so the strips can't be joined
+		return fmt.Errorf("%w: TIFF with %v CCITT Group 4 strips", ErrUnsupportedImage, len(strips))
+	}
+	pi.width, pi.height = t.value(tiffImageWidth, 0), t.value(tiffImageLength, 0)
+	if pi.width <= 0 || pi.height <= 0 {
+		return fmt.Errorf("%w: TIFF has no size", ErrUnsupportedImage)
+	}
+	pi.data = bytes.Join(strips, nil)
+	if t.value(tiffFillOrder, 1) == 2 {
+		for i, b := range pi.data {
+			pi.data[i] = bits.Reverse8(b)
+		}
+	}
+	k := 0
+	switch {
+	case compression == 4:
+		k = -1
+	case compression == 3 && t.value(tiffT4Options, 0)&1 != 0:
+		k = 1
+	}
+	parms := fmt.Sprintf("<< /K %v /Columns %v /Rows %v", k, pi.width, pi.height)
+	if compression == 2 {
+		parms += " /EncodedByteAlign true"
+	}
+	pi.decodeParms = parms + " >>"
+	pi.bits, pi.colorSpace, pi.filter = 1, "/DeviceGray", "/CCITTFaxDecode"
+	if t.value(tiffPhotometric, 0) == 1 {
+		// the runs coded as white are 0, which is black
+		pi.decode = "[ 1 0 ]"
+	}
+	return nil
+}
+
+// decodeTIFF decodes the first image of a TIFF file that isn't CCITT fax compressed
+func decodeTIFF(file []byte) (image.Image, error) {
+	t, err := parseTIFF(file)
+	if err != nil {
+		return nil, err
+	}
+	return t.decode(file)
+}
+
+// decode decodes the image from its strips
+func (t *tiff) decode(file []byte) (image.Image, error) {
+	compression := t.value(tiffCompression, 1)
+	if _, ok := t.fields[tiffTileWidth]; ok {
+		return nil, fmt.Errorf("%w: tiled TIFF", ErrUnsupportedImage)
+	}
+	if t.value(tiffPlanarConfig, 1) != 1 {
+		return nil, fmt.Errorf("%w: TIFF with separate colour planes", ErrUnsupportedImage)
+	}
+	width, height := t.value(tiffImageWidth, 0), t.value(tiffImageLength, 0)
+	samples := t.value(tiffSamplesPerPix, 1)
+	depth := t.value(tiffBitsPerSample, 1)
+	if width <= 0 || height <= 0 || samples < 1 || depth != 1 && depth != 2 && depth != 4 && depth != 8 && depth != 16 {
+		return nil, fmt.Errorf("%w: TIFF %v by %v with %v samples of %v bits", ErrUnsupportedImage, width, height,
+			samples, depth)
+	}
+	strips, err := t.strips(file)
+	if err != nil {
+		return nil, err
+	}
+	var data []byte
+	for _, strip := range strips {
+		var decoded []byte
+		switch compression {
+		case 1:
+			decoded = strip
+		case 32773:
+			decoded = unpackBits(strip)
+		case 5:
+			decoded, err = tiffLZW(strip)
+		case 8, 32946:
+			var r io.ReadCloser
+			if r, err = zlib.NewReader(bytes.NewReader(strip)); err == nil {
+				decoded, err = io.ReadAll(r)
+			}
+		default:
+			name := tiffCompressionNames[compression]
+			if name == "" {
+				name = fmt.Sprint(compression)
+			}
+			return nil, fmt.Errorf("%w: TIFF compression %v", ErrUnsupportedImage, name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: TIFF strip: %v", ErrUnsupportedImage, err)
+		}
+		data = append(data, decoded...)
+	}
+	rowBytes := (width*samples*depth + 7) / 8
+	if len(data) < rowBytes*height {
+		return nil, fmt.Errorf("%w: TIFF has %v bytes of pixels instead of %v", ErrUnsupportedImage, len(data),
+			rowBytes*height)
+	}
+	if t.value(tiffPredictor, 1) == 2 {
+		if depth != 8 {
+			return nil, fmt.Errorf("%w: TIFF predictor with %v bit samples", ErrUnsupportedImage, depth)
+		}
+		for y := 0; y < height; y++ {
+			row := data[y*rowBytes : (y+1)*rowBytes]
+			for i := samples; i < len(row); i++ {
+				row[i] += row[i-samples]
+			}
+		}
+	}
+	// sample returns sample s of pixel x in row y scaled to 8 bits
+	sample := func(x, y, s int) uint8 {
+		row := data[y*rowBytes:]
+		i := x*samples + s
+		switch depth {
+		case 8:
+			return row[i]
+		case 16:
+			if t.order == binary.LittleEndian {
+				return row[2*i+1]
+			}
+			return row[2*i]
+		}
+		v := row[i*depth/8] >> (8 - depth - i*depth%8) & (1<<depth - 1)
+		return uint8(int(v) * 255 / (1<<depth - 1))
+	}
+
+	rect := image.Rect(0, 0, width, height)
+	switch photometric := t.value(tiffPhotometric, 1); photometric {
+	case 0, 1:
+		img := image.NewGray(rect)
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				v := sample(x, y, 0)
+				if photometric == 0 {
+					v = 255 - v
+				}
+				img.Pix[y*width+x] = v
+			}
+		}
+		return img, nil
+	case 2:
+		if samples < 3 {
+			break
+		}
+		img := image.NewNRGBA(rect)
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				at := 4 * (y*width + x)
+				img.Pix[at], img.Pix[at+1], img.Pix[at+2], img.Pix[at+3] = sample(x, y, 0), sample(x, y, 1), sample(x, y, 2), 255
+			}
+		}
+		return img, nil
+	case 3:
+		colorMap := t.fields[tiffColorMap]
+		if depth > 8 || len(colorMap) != 3<<depth {
+			break
+		}
+		palette := make(color.Palette, 1<<depth)
+		for i := range palette {
+			palette[i] = color.RGBA{uint8(colorMap[i] >> 8), uint8(colorMap[len(palette)+i] >> 8),
+				uint8(colorMap[2*len(palette)+i] >> 8), 255}
+		}
+		img := image.NewPaletted(rect, palette)
+		for y := 0; y < height; y++ {
+			row := data[y*rowBytes:]
+			for x := 0; x < width; x++ {
+				i := x * samples
+				img.Pix[y*width+x] = row[i*depth/8] >> (8 - depth - i*depth%8) & (1<<depth - 1)
+			}
+		}
+		return img, nil
+	case 5:
+		if samples < 4 || depth != 8 {
+			break
+		}
+		img := image.NewCMYK(rect)
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				copy(img.Pix[4*(y*width+x):], data[y*rowBytes+x*samples:y*rowBytes+x*samples+4])
+			}
+		}
+		return img, nil
+	}
+	return nil, fmt.Errorf("%w: TIFF photometric interpretation %v with %v samples of %v bits", ErrUnsupportedImage,
+		t.value(tiffPhotometric, 1), samples, depth)
+}
+
+// unpackBits decompresses PackBits run length encoded data
+func unpackBits(data []byte) []byte {
+	var out []byte
+	for i := 0; i < len(data); {
+		n := int(int8(data[i]))
+		i++
+		switch {
+		case n >= 0:
+			end := min(i+n+1, len(data))
+			out = append(out, data[i:end]...)
+			i = end
+		case n > -128 && i < len(data):
+			out = append(out, bytes.Repeat(data[i:i+1], 1-n)...)
+			i++
+		}
+	}
+	return out
+}
+
+// tiffLZW decompresses TIFF LZW data, whose codes grow a code earlier than other LZW variants
+func tiffLZW(data []byte) ([]byte, error) {
+	const clear, end = 256, 257
+	table := make([][]byte, 258, 4096)
+	for i := 0; i < 256; i++ {
+		table[i] = []byte{byte(i)}
+	}
+	var out, prev []byte
+	width := 9
+	for at := 0; at+width <= 8*len(data); {
+		code := 0
+		for i := at; i < at+width; i++ {
+			code = code<<1 | int(data[i/8]>>(7-i%8)&1)
+		}
+		at += width
+		if code == clear {
+			table, width, prev = table[:258], 9, nil
+			continue
+		}
+		if code == end {
+			break
+		}
+		var entry []byte
+		switch {
+		case code < len(table):
+			entry = table[code]
+		case code == len(table) && prev != nil:
+			entry = append(append([]byte(nil), prev...), prev[0])
+		default:
+			return out, fmt.Errorf("invalid LZW code %v", code)
+		}
+		out = append(out, entry...)
+		if prev != nil && len(table) < 4096 {
+			table = append(table, append(append([]byte(nil), prev...), entry[0]))
+		}
+		prev = entry
+		switch len(table) {
+		case 511:
+			width = 10
+		case 1023:
+			width = 11
+		case 2047:
+			width = 12
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,185 @@
+package gopdf
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// headingSizes are the font sizes in points of headings of each level, the last used for deeper levels
+var headingSizes = []float64{18, 14, 12}
+
+// heading is a heading output by Heading, at height y in points on its page
+type heading struct {
+	level int
+	text  string
+	page  *PdfPage
+	y     float64
+}
+
+// TOCOptions controls the table of contents added by InsertTOC. The zero value lists the headings in the first font
+// added to the document at 12 points under the title Contents.
+type TOCOptions struct {
+	Title    string  // title at the top of the first page, or empty for Contents
+	Font     string  // name of a font added to the document, or empty for the first font added
+	FontSize float64 // size in points of the entries, or 0 for 12
+	Indent   float64 // indent of each level below the first in the document's unit, or 0 for 18 points
+}
+
+// tocSettings is where the table of contents goes and how it looks, with the indent in points
+type tocSettings struct {
+	after int
+	opts  TOCOptions
+}
+
+// Heading outputs a heading on the current page at the cursor and records it for the table of contents. Headings
+// of level 1 are 18 points, level 2 are 14 points and deeper levels are 12 points, in the current font. The cursor
+// moves to the start of the line below the heading, with the font size put back.
+func (d *PdfDocument) Heading(level int, text string) {
+	p := d.currentPage
+	if p == nil {
+		p = d.AddPage()
+	}
+	p = p.flow()
+	level = max(level, 1)
+	size := headingSizes[min(level, len(headingSizes))-1]
+	fontSize := p.fontSize
+	if size > fontSize {
+		// make room above the baseline for the larger text
+		p.y -= size - fontSize
+	}
+	p.SetFontSize(size)
+	d.headings = append(d.headings, heading{level: level, text: text, page: p, y: p.y + size})
+	p.Println(text)
+	p.flow().SetFontSize(fontSize)
+}
+
+// InsertTOC adds a table of contents after the first afterPage pages when the document is written, or at the start
+// when afterPage is 0. It lists the headings output by Heading, indented by level, with a leader of dots out to the
+// number of the page each is on. The numbers count the pages of the table of contents itself, which takes as many
+// pages as it needs. Each entry links to its heading, and a bookmark is added for each heading, nested by level.
+func (d *PdfDocument) InsertTOC(afterPage int, opts TOCOptions) error {
+	if afterPage < 0 {
+		return fmt.Errorf("%w: after page %v", ErrInvalidTOC, afterPage)
+	}
+	if opts.Indent > 0 {
+		opts.Indent *= float64(d.unit)
+	}
+	d.toc = &tocSettings{after: afterPage, opts: opts}
+	return nil
+}
+
+// insertTOC adds the pages of the table of contents and the bookmarks of the headings, and returns a function that
+// removes them again, so that the document can still be changed and written again afterwards
+func (d *PdfDocument) insertTOC() (remove func(), err error) {
+	if d.toc == nil {
+		return func() {}, nil
+	}
+	pages := d.catalog.pdfPages.pages
+	if d.toc.after > len(pages) {
+		return func() {}, fmt.Errorf("%w: after page %v of %v", ErrInvalidTOC, d.toc.after, len(pages))
+	}
+	opts := d.toc.opts
+	if opts.Font == "" && len(d.resources.fonts) > 0 {
+		opts.Font = d.resources.fonts[0].name
+	}
+	if _, err := d.resources.font(opts.Font); err != nil {
+		return func() {}, err
+	}
+	if opts.Title == "" {
+		opts.Title = "Contents"
+	}
+	if opts.FontSize <= 0 {
+		opts.FontSize = 12
+	}
+	if opts.Indent <= 0 {
+		opts.Indent = 18
+	}
+
+	objects := len(d.objects)
+	bookmarks := d.catalog.outlines.bookmarks
+	remove = func() {
+		d.catalog.pdfPages.pages = pages
+		d.catalog.outlines.bookmarks = bookmarks
+		if n := len(bookmarks); n > 0 {
+			bookmarks[n-1].next = nil
+		}
+		d.objects = d.objects[:objects]
+	}
+
+	// lay out the entries first, since the page numbers depend on how many pages they take
+	var toc []*PdfPage
+	var p *PdfPage
+	addPage := func() {
+		size := d.pageSize.oriented(d.orientation)
+		p = d.newPage(size.Width, size.Height, d.margins)
+		p.parent = d.catalog.pdfPages
+		p.content.page = p
+		d.addObject(p)
+		d.addObject(p.content)
+		p.SetFont(opts.Font)
+		p.SetFontSize(opts.FontSize)
+		toc = append(toc, p)
+	}
+	addPage()
+	p.SetFontSize(headingSizes[0])
+	p.Println(opts.Title)
+	p.SetFontSize(opts.FontSize)
+	p.newLine()
+	type entry struct {
+		page *PdfPage
+		y    float64
+	}
+	entries := make([]entry, len(d.headings))
+	for i := range d.headings {
+		if p.y < p.bottomMargin {
+			addPage()
+		}
+		entries[i] = entry{p, p.y}
+		p.newLine()
+	}
+
+	all := append(append(append([]*PdfPage(nil), pages[:d.toc.after]...), toc...), pages[d.toc.after:]...)
+	d.catalog.pdfPages.pages = all
+	numbers := make(map[*PdfPage]int, len(all))
+	for i, page := range all {
+		numbers[page] = i + 1
+	}
+	var parents []*Bookmark // the last bookmark at each level
+	for i, h := range d.headings {
+		number, ok := numbers[h.page]
+		if !ok {
+			continue
+		}
+		p = entries[i].page
+		p.y = entries[i].y
+		p.tocEntry(h, strconv.Itoa(number), opts.Indent)
+		parents = parents[:min(h.level-1, len(parents))]
+		var parent *Bookmark
+		if len(parents) > 0 {
+			parent = parents[len(parents)-1]
+		}
+		b := d.AddBookmark(h.text, h.page, 0, parent)
+		b.y = h.y
+		parents = append(parents, b)
+	}
+	return remove, nil
+}
+
+// tocEntry outputs the entry for a heading on the current line, with a leader of dots from the heading to its page
+// number at the right margin, and makes the line a link to the heading
+func (p *PdfPage) tocEntry(h heading, number string, indent float64) {
+	left := p.leftMargin + float64(h.level-1)*indent
+	right := p.width - p.rightMargin
+	p.outputTextAt(h.text, left)
+	numberX := right - p.textWidth(number)
+	p.outputTextAt(number, numberX)
+	// the dots end a space before the number and start at least a space after the heading
+	space, dot := p.textWidth(" "), p.textWidth(".")
+	end := numberX - space
+	if dots := int((end - left - p.textWidth(h.text) - space) / dot); dots > 0 {
+		p.outputTextAt(strings.Repeat(".", dots), end-float64(dots)*dot)
+	}
+	l := p.addLink(left, p.y-p.fontSize/4, right-left, p.fontSize, "")
+	l.target, l.targetY = h.page, h.y
+}
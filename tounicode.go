@@ -0,0 +1,137 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"unicode/utf16"
+)
+
+// toUnicodeCMap builds a ToUnicode CMap mapping character codes of codeBytes bytes to the Unicode text they
+// represent, so that text can be extracted, searched and copied out of the document
+func toUnicodeCMap(codeBytes int, mapping map[int]rune) []byte {
+	codes := make([]int, 0, len(mapping))
+	for code := range mapping {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "/CIDInit /ProcSet findresource begin\n")
+	fmt.Fprintf(&buf, "12 dict begin\n")
+	fmt.Fprintf(&buf, "begincmap\n")
+	fmt.Fprintf(&buf, "/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n")
+	fmt.Fprintf(&buf, "/CMapName /Adobe-Identity-UCS def\n")
+	fmt.Fprintf(&buf, "/CMapType 2 def\n")
+	fmt.Fprintf(&buf, "1 begincodespacerange\n")
+	if codeBytes == 1 {
+		fmt.Fprintf(&buf, "<00> <FF>\n")
+	} else {
+		fmt.Fprintf(&buf, "<0000> <FFFF>\n")
+	}
+	fmt.Fprintf(&buf, "endcodespacerange\n")
+	// Each bfchar section may hold at most 100 mappings
+	for start := 0; start < len(codes); start += 100 {
+		end := start + 100
+		if end > len(codes) {
+			end = len(codes)
+		}
+		fmt.Fprintf(&buf, "%v beginbfchar\n", end-start)
+		for _, code := range codes[start:end] {
+			fmt.Fprintf(&buf, "<%0*X> <", 2*codeBytes, code)
+			for _, u := range utf16.Encode([]rune{mapping[code]}) {
+				fmt.Fprintf(&buf, "%04X", u)
+			}
+			fmt.Fprintf(&buf, ">\n")
+		}
+		fmt.Fprintf(&buf, "endbfchar\n")
+	}
+	fmt.Fprintf(&buf, "endcmap\n")
+	fmt.Fprintf(&buf, "CMapName currentdict /CMap defineresource pop\n")
+	fmt.Fprintf(&buf, "end\n")
+	fmt.Fprintf(&buf, "end\n")
+	return buf.Bytes()
+}
+
+// coreFontUnicode returns the name of the encoding of one of the 14 core fonts and the Unicode characters of its
+// codes. Symbol and ZapfDingbats have encodings of their own, and the other fonts use WinAnsiEncoding.
+func coreFontUnicode(f *PdfFont) (string, map[int]rune) {
+	mapping := make(map[int]rune)
+	var table []rune
+	switch f.baseFont {
+	case "Symbol":
+		table = symbolUnicode[:]
+	case "ZapfDingbats":
+		table = zapfDingbatsUnicode[:]
+	default:
+		for r := rune(' '); r <= 0xFF; r++ {
+			if r != 0x7F && (r < 0x80 || r >= 0xA0) {
+				mapping[int(r)] = r
+			}
+		}
+		for r, b := range winAnsiHigh {
+			mapping[int(b)] = r
+		}
+		return f.encoding, mapping
+	}
+	for i, r := range table {
+		if r != 0 {
+			mapping[0x20+i] = r
+		}
+	}
+	return f.baseFont, mapping
+}
+
+// symbolUnicode is the Unicode character of each code of the Symbol font from 0x20, or 0 where the code has no
+// character or one only in the private use area
+var symbolUnicode = [...]rune{
+	// 0x20
+	' ', '!', '∀', '#', '∃', '%', '&', '∋', '(', ')', '∗', '+', ',', '−', '.', '/',
+	'0', '1', '2', '3', '4', '5', '6', '7', '8', '9', ':', ';', '<', '=', '>', '?',
+	'≅', 'Α', 'Β', 'Χ', 'Δ', 'Ε', 'Φ', 'Γ', 'Η', 'Ι', 'ϑ', 'Κ', 'Λ', 'Μ', 'Ν', 'Ο',
+	'Π', 'Θ', 'Ρ', 'Σ', 'Τ', 'Υ', 'ς', 'Ω', 'Ξ', 'Ψ', 'Ζ', '[', '∴', ']', '⊥', '_',
+	0, 'α', 'β', 'χ', 'δ', 'ε', 'φ', 'γ', 'η', 'ι', 'ϕ', 'κ', 'λ', 'μ', 'ν', 'ο',
+	'π', 'θ', 'ρ', 'σ', 'τ', 'υ', 'ϖ', 'ω', 'ξ', 'ψ', 'ζ', '{', '|', '}', '∼', 0,
+	// 0x80
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	'€', 'ϒ', '′', '≤', '⁄', '∞', 'ƒ', '♣', '♦', '♥', '♠', '↔', '←', '↑', '→', '↓',
+	'°', '±', '″', '≥', '×', '∝', '∂', '•', '÷', '≠', '≡', '≈', '…', 0, 0, '↵',
+	'ℵ', 'ℑ', 'ℜ', '℘', '⊗', '⊕', '∅', '∩', '∪', '⊃', '⊇', '⊄', '⊂', '⊆', '∈', '∉',
+	'∠', '∇', '®', '©', '™', '∏', '√', '⋅', '¬', '∧', '∨', '⇔', '⇐', '⇑', '⇒', '⇓',
+	'◊', '\u2329', '®', '©', '™', '∑', '⎛', '⎜', '⎝', '⎡', '⎢', '⎣', '⎧', '⎨', '⎩', '⎪',
+	0, '\u232A', '∫', '⌠', '⎮', '⌡', '⎞', '⎟', '⎠', '⎤', '⎥', '⎦', '⎫', '⎬', '⎭', 0,
+}
+
+// zapfDingbatsUnicode is the Unicode character of each code of the ZapfDingbats font from 0x20, or 0 where the
+// code has no character
+var zapfDingbatsUnicode = func() [0xE0]rune {
+	table := [0xE0]rune{' '}
+	for code := 0x21; code <= 0x7E; code++ {
+		table[code-0x20] = 0x2700 + rune(code-0x20)
+	}
+	// the dingbats that were already in Unicode before the Dingbats block are where they were
+	for code, r := range map[int]rune{
+		0x25: '☎', 0x2A: '☛', 0x2B: '☞', 0x48: '★', 0x6C: '●', 0x6E: '■', 0x73: '▲', 0x74: '▼', 0x75: '◆', 0x77: '◗',
+	} {
+		table[code-0x20] = r
+	}
+	for code := 0x80; code <= 0x8D; code++ {
+		table[code-0x20] = 0x2768 + rune(code-0x80)
+	}
+	for code := 0xA1; code <= 0xA7; code++ {
+		table[code-0x20] = 0x2761 + rune(code-0xA1)
+	}
+	for code, r := range map[int]rune{0xA8: '♣', 0xA9: '♦', 0xAA: '♥', 0xAB: '♠'} {
+		table[code-0x20] = r
+	}
+	for code := 0xAC; code <= 0xB5; code++ {
+		table[code-0x20] = '①' + rune(code-0xAC)
+	}
+	for code := 0xB6; code <= 0xFE; code++ {
+		if code != 0xF0 {
+			table[code-0x20] = 0x2776 + rune(code-0xB6)
+		}
+	}
+	return table
+}()
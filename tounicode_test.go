@@ -0,0 +1,131 @@
+package gopdf
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// bfchar matches a one byte mapping of a bfchar section of a ToUnicode CMap
+var bfchar = regexp.MustCompile(`<([0-9A-F]{2})> <([0-9A-F]{4})>`)
+
+// extractText returns the text shown on the first page of a PDF file, mapped to Unicode by the ToUnicode CMaps of
+// its fonts as a viewer copying the text would
+func extractText(t *testing.T, data []byte) string {
+	t.Helper()
+	pr, err := parsePDF(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pages, err := pr.pages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := pr.pageContent(pages[0].dict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resources, _ := pr.resolve(pages[0].dict.get("Resources")).(pdfDict)
+	fonts, _ := pr.resolve(resources.get("Font")).(pdfDict)
+	cmaps := map[pdfName]map[byte]rune{}
+	for _, e := range fonts {
+		font, _ := pr.resolve(e.value).(pdfDict)
+		s, ok := pr.resolve(font.get("ToUnicode")).(*pdfStream)
+		if !ok {
+			t.Fatalf("font %v has no ToUnicode CMap", e.key)
+		}
+		cmap, err := pr.decode(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cmaps[e.key] = map[byte]rune{}
+		for _, m := range bfchar.FindAllStringSubmatch(string(cmap), -1) {
+			code, _ := strconv.ParseUint(m[1], 16, 8)
+			r, _ := strconv.ParseUint(m[2], 16, 16)
+			cmaps[e.key][byte(code)] = rune(r)
+		}
+	}
+
+	var text strings.Builder
+	var font pdfName
+	var operands []any
+	p := &pdfParser{data: content}
+	for {
+		p.skipSpace()
+		if p.pos >= len(content) {
+			return text.String()
+		}
+		start := p.pos
+		if !isPDFDelimiter(content[p.pos]) {
+			switch token := p.keyword(); {
+			case isOperand(token):
+				p.pos = start
+			case token == "Tf":
+				font, _ = operands[0].(pdfName)
+				operands = operands[:0]
+				continue
+			case token == "Tj":
+				s, _ := operands[0].(pdfString)
+				for _, b := range []byte(s) {
+					text.WriteRune(cmaps[font][b])
+				}
+				operands = operands[:0]
+				continue
+			default:
+				operands = operands[:0]
+				continue
+			}
+		}
+		v, err := p.value()
+		if err != nil {
+			t.Fatal(err)
+		}
+		operands = append(operands, v)
+	}
+}
+
+func TestCoreFontToUnicode(t *testing.T) {
+	for _, c := range []struct {
+		font       int
+		text, want string
+	}{
+		{Helvetica, "café — €10", "café — €10"},
+		{TimesBoldItalic, "“Quoted” ½ ‰ Žž", "“Quoted” ½ ‰ Žž"},
+		{Symbol, "abg p=3.14", "αβγ π=3.14"},
+		{ZapfDingbats, "4 n", "✔ ■"},
+	} {
+		d := NewPdfDocument()
+		if _, err := d.AddFont("F", c.font); err != nil {
+			t.Fatal(err)
+		}
+		p := d.CurrentPage()
+		p.SetFont("F")
+		p.Print(c.text)
+		data, err := d.Bytes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := extractText(t, data); got != c.want {
+			t.Errorf("font %v: text %q extracted as %q, want %q", c.font, c.text, got, c.want)
+		}
+	}
+}
+
+func TestCoreFontsShareToUnicode(t *testing.T) {
+	d := NewPdfDocument()
+	var fonts []*PdfFont
+	for _, id := range []int{Helvetica, TimesRoman, Symbol, Courier} {
+		f, err := d.AddFont(strconv.Itoa(id), id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fonts = append(fonts, f)
+	}
+	if fonts[0].toUnicode != fonts[1].toUnicode || fonts[0].toUnicode != fonts[3].toUnicode {
+		t.Error("WinAnsiEncoding fonts have different ToUnicode CMaps")
+	}
+	if fonts[2].toUnicode == fonts[0].toUnicode {
+		t.Error("Symbol has the ToUnicode CMap of WinAnsiEncoding")
+	}
+}
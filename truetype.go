@@ -0,0 +1,312 @@
+package gopdf
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+)
+
+// trueTypeFont holds the parts of a TrueType font file needed to embed it and lay out text with it
+type trueTypeFont struct {
+	data   []byte
+	tables map[string][]byte
+
+	postScriptName string
+	unitsPerEm     int
+	bbox           [4]int
+	ascent         int
+	descent        int
+	capHeight      int
+	italicAngle    float64
+	underlinePos   int
+	underlineThick int
+	strikeoutPos   int
+	strikeoutThick int
+	fixedPitch     bool
+	weight         int
+	longLoca       bool
+	numGlyphs      int
+	advances       []int
+	cmap           map[rune]uint16
+}
+
+var errBadTrueType = errors.New("gopdf: invalid TrueType font")
+
+// parseTrueType reads the tables of a TrueType font file
+func parseTrueType(data []byte) (*trueTypeFont, error) {
+	if len(data) < 12 {
+		return nil, errBadTrueType
+	}
+	switch binary.BigEndian.Uint32(data) {
+	case 0x00010000, 0x74727565: // 1.0 and 'true'
+	case 0x4F54544F: // 'OTTO'
+		return nil, fmt.Errorf("%w: CFF based OpenType fonts are not supported", errBadTrueType)
+	default:
+		return nil, errBadTrueType
+	}
+	f := &trueTypeFont{data: data, tables: make(map[string][]byte)}
+	numTables := int(binary.BigEndian.Uint16(data[4:]))
+	for i := 0; i < numTables; i++ {
+		rec := 12 + 16*i
+		if rec+16 > len(data) {
+			return nil, errBadTrueType
+		}
+		tag := string(data[rec : rec+4])
+		offset := int(binary.BigEndian.Uint32(data[rec+8:]))
+		length := int(binary.BigEndian.Uint32(data[rec+12:]))
+		if offset < 0 || length < 0 || offset+length > len(data) {
+			return nil, fmt.Errorf("%w: table %v is out of range", errBadTrueType, tag)
+		}
+		f.tables[tag] = data[offset : offset+length]
+	}
+	for _, tag := range []string{"head", "hhea", "hmtx", "maxp", "cmap", "loca", "glyf"} {
+		if _, ok := f.tables[tag]; !ok {
+			return nil, fmt.Errorf("%w: missing %v table", errBadTrueType, tag)
+		}
+	}
+	if err := f.parseHead(); err != nil {
+		return nil, err
+	}
+	if err := f.parseMetrics(); err != nil {
+		return nil, err
+	}
+	if err := f.parseCmap(); err != nil {
+		return nil, err
+	}
+	f.parseName()
+	f.parsePost()
+	if err := f.parseOS2(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *trueTypeFont) parseHead() error {
+	head := f.tables["head"]
+	if len(head) < 54 {
+		return fmt.Errorf("%w: short head table", errBadTrueType)
+	}
+	f.unitsPerEm = int(binary.BigEndian.Uint16(head[18:]))
+	if f.unitsPerEm == 0 {
+		return fmt.Errorf("%w: unitsPerEm is zero", errBadTrueType)
+	}
+	for i := range f.bbox {
+		f.bbox[i] = f.scale(int(int16(binary.BigEndian.Uint16(head[36+2*i:]))))
+	}
+	f.longLoca = binary.BigEndian.Uint16(head[50:]) != 0
+	return nil
+}
+
+func (f *trueTypeFont) parseMetrics() error {
+	hhea, maxp, hmtx := f.tables["hhea"], f.tables["maxp"], f.tables["hmtx"]
+	if len(hhea) < 36 || len(maxp) < 6 {
+		return fmt.Errorf("%w: short hhea or maxp table", errBadTrueType)
+	}
+	f.ascent = f.scale(int(int16(binary.BigEndian.Uint16(hhea[4:]))))
+	f.descent = f.scale(int(int16(binary.BigEndian.Uint16(hhea[6:]))))
+	numHMetrics := int(binary.BigEndian.Uint16(hhea[34:]))
+	f.numGlyphs = int(binary.BigEndian.Uint16(maxp[4:]))
+	if numHMetrics == 0 || numHMetrics > f.numGlyphs || len(hmtx) < 4*numHMetrics {
+		return fmt.Errorf("%w: bad horizontal metrics", errBadTrueType)
+	}
+	f.advances = make([]int, f.numGlyphs)
+	for i := range f.advances {
+		if i < numHMetrics {
+			f.advances[i] = int(binary.BigEndian.Uint16(hmtx[4*i:]))
+		} else {
+			f.advances[i] = f.advances[numHMetrics-1]
+		}
+	}
+	return nil
+}
+
+// parseCmap reads the Unicode character to glyph mapping, preferring the full repertoire format 12 subtable
+func (f *trueTypeFont) parseCmap() error {
+	cmap := f.tables["cmap"]
+	if len(cmap) < 4 {
+		return fmt.Errorf("%w: short cmap table", errBadTrueType)
+	}
+	best, bestRank := -1, 0
+	numTables := int(binary.BigEndian.Uint16(cmap[2:]))
+	for i := 0; i < numTables && 4+8*i+8 <= len(cmap); i++ {
+		rec := cmap[4+8*i:]
+		platform, encoding := binary.BigEndian.Uint16(rec), binary.BigEndian.Uint16(rec[2:])
+		offset := int(binary.BigEndian.Uint32(rec[4:]))
+		rank := 0
+		switch {
+		case platform == 3 && encoding == 10, platform == 0 && encoding >= 4:
+			rank = 3
+		case platform == 3 && encoding == 1, platform == 0:
+			rank = 2
+		}
+		if rank > bestRank && offset+4 <= len(cmap) {
+			best, bestRank = offset, rank
+		}
+	}
+	if best < 0 {
+		return fmt.Errorf("%w: no Unicode cmap", errBadTrueType)
+	}
+	f.cmap = make(map[rune]uint16)
+	sub := cmap[best:]
+	switch binary.BigEndian.Uint16(sub) {
+	case 4:
+		return f.parseCmap4(sub)
+	case 12:
+		return f.parseCmap12(sub)
+	}
+	return fmt.Errorf("%w: unsupported cmap format %v", errBadTrueType, binary.BigEndian.Uint16(sub))
+}
+
+func (f *trueTypeFont) parseCmap4(sub []byte) error {
+	if len(sub) < 14 {
+		return fmt.Errorf("%w: short cmap", errBadTrueType)
+	}
+	segCount := int(binary.BigEndian.Uint16(sub[6:])) / 2
+	ends := 14
+	starts := ends + 2*segCount + 2
+	deltas := starts + 2*segCount
+	rangeOffsets := deltas + 2*segCount
+	if rangeOffsets+2*segCount > len(sub) {
+		return fmt.Errorf("%w: short cmap", errBadTrueType)
+	}
+	for s := 0; s < segCount; s++ {
+		end := int(binary.BigEndian.Uint16(sub[ends+2*s:]))
+		start := int(binary.BigEndian.Uint16(sub[starts+2*s:]))
+		delta := int(binary.BigEndian.Uint16(sub[deltas+2*s:]))
+		rangeOffset := int(binary.BigEndian.Uint16(sub[rangeOffsets+2*s:]))
+		for c := start; c <= end && c != 0xFFFF; c++ {
+			var gid int
+			if rangeOffset == 0 {
+				gid = (c + delta) & 0xFFFF
+			} else {
+				at := rangeOffsets + 2*s + rangeOffset + 2*(c-start)
+				if at+2 > len(sub) {
+					continue
+				}
+				gid = int(binary.BigEndian.Uint16(sub[at:]))
+				if gid != 0 {
+					gid = (gid + delta) & 0xFFFF
+				}
+			}
+			if gid != 0 && gid < f.numGlyphs {
+				f.cmap[rune(c)] = uint16(gid)
+			}
+		}
+	}
+	return nil
+}
+
+func (f *trueTypeFont) parseCmap12(sub []byte) error {
+	if len(sub) < 16 {
+		return fmt.Errorf("%w: short cmap", errBadTrueType)
+	}
+	groups := int(binary.BigEndian.Uint32(sub[12:]))
+	if 16+12*groups > len(sub) {
+		return fmt.Errorf("%w: short cmap", errBadTrueType)
+	}
+	for g := 0; g < groups; g++ {
+		rec := sub[16+12*g:]
+		start := binary.BigEndian.Uint32(rec)
+		end := binary.BigEndian.Uint32(rec[4:])
+		gid := binary.BigEndian.Uint32(rec[8:])
+		for c := start; c <= end && c <= 0x10FFFF; c++ {
+			if int(gid) < f.numGlyphs {
+				f.cmap[rune(c)] = uint16(gid)
+			}
+			gid++
+		}
+	}
+	return nil
+}
+
+// parseOS2 reads the weight, cap height, strikeout line and embedding permissions
+func (f *trueTypeFont) parseOS2() error {
+	f.weight = 400
+	f.capHeight = f.ascent
+	f.strikeoutPos, f.strikeoutThick = f.ascent/4, f.underlineThick
+	os2 := f.tables["OS/2"]
+	if len(os2) < 10 {
+		return nil
+	}
+	f.weight = int(binary.BigEndian.Uint16(os2[4:]))
+	// Restricted license embedding, with no permission bits that relax it
+	if fsType := binary.BigEndian.Uint16(os2[8:]); fsType&0x000F == 0x0002 {
+		return fmt.Errorf("gopdf: the license of font %v does not allow embedding", f.postScriptName)
+	}
+	if len(os2) >= 30 {
+		f.strikeoutThick = f.scale(int(int16(binary.BigEndian.Uint16(os2[26:]))))
+		f.strikeoutPos = f.scale(int(int16(binary.BigEndian.Uint16(os2[28:]))))
+	}
+	if len(os2) >= 90 && binary.BigEndian.Uint16(os2) >= 2 {
+		f.capHeight = f.scale(int(int16(binary.BigEndian.Uint16(os2[88:]))))
+	}
+	return nil
+}
+
+func (f *trueTypeFont) parsePost() {
+	f.underlinePos, f.underlineThick = -100, 50
+	post := f.tables["post"]
+	if len(post) < 16 {
+		return
+	}
+	f.italicAngle = float64(int32(binary.BigEndian.Uint32(post[4:]))) / 65536
+	f.underlinePos = f.scale(int(int16(binary.BigEndian.Uint16(post[8:]))))
+	f.underlineThick = f.scale(int(int16(binary.BigEndian.Uint16(post[10:]))))
+	f.fixedPitch = binary.BigEndian.Uint32(post[12:]) != 0
+}
+
+// parseName finds the PostScript name of the font, which is used as the BaseFont
+func (f *trueTypeFont) parseName() {
+	f.postScriptName = "Unnamed"
+	name := f.tables["name"]
+	if len(name) < 6 {
+		return
+	}
+	count := int(binary.BigEndian.Uint16(name[2:]))
+	storage := int(binary.BigEndian.Uint16(name[4:]))
+	for i := 0; i < count && 6+12*i+12 <= len(name); i++ {
+		rec := name[6+12*i:]
+		platform := binary.BigEndian.Uint16(rec)
+		nameID := binary.BigEndian.Uint16(rec[6:])
+		length := int(binary.BigEndian.Uint16(rec[8:]))
+		offset := storage + int(binary.BigEndian.Uint16(rec[10:]))
+		if nameID != 6 || offset+length > len(name) {
+			continue
+		}
+		raw := name[offset : offset+length]
+		if platform == 3 || platform == 0 {
+			units := make([]uint16, len(raw)/2)
+			for j := range units {
+				units[j] = binary.BigEndian.Uint16(raw[2*j:])
+			}
+			f.postScriptName = sanitizeFontName(string(utf16.Decode(units)))
+			return
+		}
+		f.postScriptName = sanitizeFontName(string(raw))
+	}
+}
+
+// sanitizeFontName strips the characters that PostScript names may not contain
+func sanitizeFontName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r > '~' || strings.ContainsRune("[](){}<>/%", r) {
+			return -1
+		}
+		return r
+	}, name)
+}
+
+// scale converts a value in font units to thousandths of an em, as used by PDF font metrics
+func (f *trueTypeFont) scale(v int) int {
+	return v * 1000 / f.unitsPerEm
+}
+
+// glyphWidth returns the advance width of a glyph in thousandths of an em
+func (f *trueTypeFont) glyphWidth(gid uint16) int {
+	if int(gid) >= len(f.advances) {
+		return 0
+	}
+	return f.scale(f.advances[gid])
+}
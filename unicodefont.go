@@ -0,0 +1,271 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// unicodeFont holds the extra state of a Type0 font built from an embedded TrueType font.
+// Text is written as two byte glyph ids using the Identity-H encoding, so any character in the font can be used.
+type unicodeFont struct {
+	ttf        *trueTypeFont
+	used       map[uint16]rune // the glyphs used so far and the character each one represents
+	mu         sync.Mutex      // guards used while AddPages builds pages at the same time
+	descendant *PdfCIDFont
+	descriptor *PdfFontDescriptor
+	fontFile   *PdfFontFile
+	toUnicode  *PdfToUnicodeCMap
+	cidSet     *PdfCIDSet
+}
+
+// PdfCIDFont is the descendant CIDFontType2 font of a Type0 font
+type PdfCIDFont struct {
+	PdfObject
+	font *PdfFont
+}
+
+// PdfFontDescriptor describes the metrics of an embedded font
+type PdfFontDescriptor struct {
+	PdfObject
+	font *PdfFont
+}
+
+// PdfFontFile is the embedded TrueType font program
+type PdfFontFile struct {
+	PdfObject
+	font *PdfFont
+}
+
+// PdfToUnicodeCMap maps the character codes of a font back to Unicode
+type PdfToUnicodeCMap struct {
+	PdfObject
+	font    *PdfFont     // the Type0 font whose glyphs are mapped, or nil for the core fonts
+	mapping map[int]rune // the codes of the core fonts sharing the CMap, when font is nil
+}
+
+// PdfCIDSet lists the glyphs present in an embedded font, which PDF/A requires for subsets
+type PdfCIDSet struct {
+	PdfObject
+	font *PdfFont
+}
+
+// AddUnicodeFont embeds the TrueType font in ttfPath under the given name. Text printed in the font can contain any
+// character the font has a glyph for, including CJK and other non Latin scripts.
+func (d *PdfDocument) AddUnicodeFont(name string, ttfPath string) (*PdfFont, error) {
+	if err := d.checkFontName(name); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(ttfPath)
+	if err != nil {
+		return nil, fmt.Errorf("gopdf: loading font %v: %w", name, err)
+	}
+	ttf, err := parseTrueType(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w in %v", err, ttfPath)
+	}
+	font := &PdfFont{name: name, baseFont: ttf.postScriptName, subtype: "Type0", encoding: "Identity-H"}
+	u := &unicodeFont{ttf: ttf, used: make(map[uint16]rune)}
+	u.descendant = &PdfCIDFont{font: font}
+	u.descriptor = &PdfFontDescriptor{font: font}
+	u.fontFile = &PdfFontFile{font: font}
+	u.toUnicode = &PdfToUnicodeCMap{font: font}
+	u.cidSet = &PdfCIDSet{font: font}
+	font.unicode = u
+	d.addObject(font)
+	d.addObject(u.descendant)
+	d.addObject(u.descriptor)
+	d.addObject(u.fontFile)
+	d.addObject(u.toUnicode)
+	d.addObject(u.cidSet)
+	d.resources.fonts = append(d.resources.fonts, font)
+	return font, nil
+}
+
+// encode converts text to two byte glyph ids. Characters missing from the font are drawn with the glyph for
+// replacement, and the first of them is returned as unmapped. When record is set the glyphs are noted as used so
+// that they appear in the widths and ToUnicode tables.
+func (u *unicodeFont) encode(text string, replacement rune, record bool) (encoded string, unmapped rune, ok bool) {
+	ok = true
+	if record {
+		u.mu.Lock()
+		defer u.mu.Unlock()
+	}
+	var sb strings.Builder
+	for _, r := range text {
+		gid, found := u.ttf.cmap[r]
+		if !found {
+			if ok {
+				unmapped, ok = r, false
+			}
+			r = replacement
+			gid = u.ttf.cmap[r]
+		}
+		if record && gid != 0 {
+			if _, seen := u.used[gid]; !seen {
+				u.used[gid] = r
+			}
+		}
+		sb.WriteByte(byte(gid >> 8))
+		sb.WriteByte(byte(gid))
+	}
+	return sb.String(), unmapped, ok
+}
+
+// width returns the width of encoded text in thousandths of a point at a font size of 1
+func (u *unicodeFont) width(encoded string) int {
+	total := 0
+	for i := 0; i+1 < len(encoded); i += 2 {
+		total += u.ttf.glyphWidth(uint16(encoded[i])<<8 | uint16(encoded[i+1]))
+	}
+	return total
+}
+
+// usedGlyphs returns the ids of the glyphs used so far in ascending order
+func (u *unicodeFont) usedGlyphs() []uint16 {
+	gids := make([]uint16, 0, len(u.used))
+	for gid := range u.used {
+		gids = append(gids, gid)
+	}
+	sort.Slice(gids, func(i, j int) bool { return gids[i] < gids[j] })
+	return gids
+}
+
+// SetFontSubsetting controls whether embedded TrueType fonts are reduced to the glyphs the document uses when it is
+// written. Subsetting is on by default; turn it off to embed the whole font, for instance so that the text can be
+// edited later.
+func (d *PdfDocument) SetFontSubsetting(subset bool) {
+	d.noSubsetting = !subset
+}
+
+// fontName returns the name of the embedded font, prefixed with a subset tag when only part of the font is embedded
+func (u *unicodeFont) fontName(d *PdfDocument) string {
+	if d.noSubsetting {
+		return u.ttf.postScriptName
+	}
+	return subsetTag(u.usedGlyphs()) + "+" + u.ttf.postScriptName
+}
+
+func (f *PdfFont) type0Bytes() []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%v 0 obj\r\n", f.id)
+	fmt.Fprintf(&buf, "<<\r\n")
+	fmt.Fprintf(&buf, "/Type /Font\r\n")
+	fmt.Fprintf(&buf, "/Subtype /Type0\r\n")
+	fmt.Fprintf(&buf, "/BaseFont %v\r\n", formatName(f.unicode.fontName(f.document)))
+	fmt.Fprintf(&buf, "/Encoding /Identity-H\r\n")
+	fmt.Fprintf(&buf, "/DescendantFonts [ %v ]\r\n", f.unicode.descendant.objectRef())
+	fmt.Fprintf(&buf, "/ToUnicode %v\r\n", f.unicode.toUnicode.objectRef())
+	fmt.Fprintf(&buf, ">>\r\n")
+	fmt.Fprintf(&buf, "endobj\r\n")
+	return buf.Bytes()
+}
+
+func (c *PdfCIDFont) bytes() []byte {
+	u := c.font.unicode
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%v 0 obj\r\n", c.id)
+	fmt.Fprintf(&buf, "<<\r\n")
+	fmt.Fprintf(&buf, "/Type /Font\r\n")
+	fmt.Fprintf(&buf, "/Subtype /CIDFontType2\r\n")
+	fmt.Fprintf(&buf, "/BaseFont %v\r\n", formatName(u.fontName(c.document)))
+	fmt.Fprintf(&buf, "/CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >>\r\n")
+	fmt.Fprintf(&buf, "/FontDescriptor %v\r\n", u.descriptor.objectRef())
+	fmt.Fprintf(&buf, "/DW %v\r\n", u.ttf.glyphWidth(0))
+	fmt.Fprintf(&buf, "/W [ ")
+	// Runs of consecutive glyphs share an entry: first [ w1 w2 ... ]
+	gids := u.usedGlyphs()
+	for i := 0; i < len(gids); {
+		fmt.Fprintf(&buf, "%v [ ", gids[i])
+		j := i
+		for ; j < len(gids) && gids[j] == gids[i]+uint16(j-i); j++ {
+			fmt.Fprintf(&buf, "%v ", u.ttf.glyphWidth(gids[j]))
+		}
+		fmt.Fprintf(&buf, "] ")
+		i = j
+	}
+	fmt.Fprintf(&buf, "]\r\n")
+	fmt.Fprintf(&buf, "/CIDToGIDMap /Identity\r\n")
+	fmt.Fprintf(&buf, ">>\r\n")
+	fmt.Fprintf(&buf, "endobj\r\n")
+	return buf.Bytes()
+}
+
+func (fd *PdfFontDescriptor) bytes() []byte {
+	ttf := fd.font.unicode.ttf
+	// Nonsymbolic, plus FixedPitch and Italic where they apply
+	flags := 32
+	if ttf.fixedPitch {
+		flags |= 1
+	}
+	if ttf.italicAngle != 0 {
+		flags |= 64
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%v 0 obj\r\n", fd.id)
+	fmt.Fprintf(&buf, "<<\r\n")
+	fmt.Fprintf(&buf, "/Type /FontDescriptor\r\n")
+	fmt.Fprintf(&buf, "/FontName %v\r\n", formatName(fd.font.unicode.fontName(fd.document)))
+	fmt.Fprintf(&buf, "/Flags %v\r\n", flags)
+	fmt.Fprintf(&buf, "/FontBBox [ %v %v %v %v ]\r\n", ttf.bbox[0], ttf.bbox[1], ttf.bbox[2], ttf.bbox[3])
+	fmt.Fprintf(&buf, "/ItalicAngle %v\r\n", formatNumber(ttf.italicAngle))
+	fmt.Fprintf(&buf, "/Ascent %v\r\n", ttf.ascent)
+	fmt.Fprintf(&buf, "/Descent %v\r\n", ttf.descent)
+	fmt.Fprintf(&buf, "/CapHeight %v\r\n", ttf.capHeight)
+	// TrueType fonts don't record the stem width so estimate it from the weight
+	fmt.Fprintf(&buf, "/StemV %v\r\n", 50+ttf.weight*ttf.weight/4000)
+	fmt.Fprintf(&buf, "/FontFile2 %v\r\n", fd.font.unicode.fontFile.objectRef())
+	fmt.Fprintf(&buf, "/CIDSet %v\r\n", fd.font.unicode.cidSet.objectRef())
+	fmt.Fprintf(&buf, ">>\r\n")
+	fmt.Fprintf(&buf, "endobj\r\n")
+	return buf.Bytes()
+}
+
+func (ff *PdfFontFile) bytes() []byte {
+	u := ff.font.unicode
+	data := u.ttf.data
+	if !ff.document.noSubsetting {
+		data = u.ttf.subset(u.used)
+	}
+	entries := fmt.Sprintf("/Filter /FlateDecode\r\n/Length1 %v\r\n", len(data))
+	return streamObject(ff.id, entries, deflate(data))
+}
+
+func (t *PdfToUnicodeCMap) bytes() []byte {
+	if t.font == nil {
+		data := toUnicodeCMap(1, t.mapping)
+		if t.document.noCompression {
+			return streamObject(t.id, "", data)
+		}
+		return streamObject(t.id, "/Filter /FlateDecode\r\n", deflate(data))
+	}
+	mapping := make(map[int]rune)
+	for gid, r := range t.font.unicode.used {
+		if utf8.ValidRune(r) {
+			mapping[int(gid)] = r
+		}
+	}
+	return streamObject(t.id, "", toUnicodeCMap(2, mapping))
+}
+
+func (c *PdfCIDSet) bytes() []byte {
+	ttf := c.font.unicode.ttf
+	glyphs := make(map[uint16]bool)
+	if c.document.noSubsetting {
+		for gid := 0; gid < ttf.numGlyphs; gid++ {
+			glyphs[uint16(gid)] = true
+		}
+	} else {
+		glyphs = ttf.subsetGlyphs(c.font.unicode.usedGlyphs())
+	}
+	// one bit per CID, high order bit first, and CIDs are glyph ids
+	set := make([]byte, (ttf.numGlyphs+7)/8)
+	for gid := range glyphs {
+		set[gid/8] |= 0x80 >> (gid % 8)
+	}
+	return streamObject(c.id, "/Filter /FlateDecode\r\n", deflate(set))
+}
@@ -0,0 +1,58 @@
+package gopdf
+
+// Unit is a unit of length, given as the number of points in one of it
+type Unit float64
+
+// Units of length
+const (
+	UnitPoint Unit = 1
+	UnitMM    Unit = 72 / 25.4
+	UnitCM    Unit = 72 / 2.54
+	UnitInch  Unit = 72
+)
+
+// SetUnit sets the unit of the positions and sizes given to the page methods and of page sizes given after this call,
+// and of those returned by GetX, GetY and TextWidth. The default is UnitPoint. Font sizes, line widths and text
+// spacing are always in points, as are the standard page sizes such as A4.
+func (d *PdfDocument) SetUnit(u Unit) {
+	d.unit = u
+}
+
+// WithUnit sets the unit of positions and sizes, as for SetUnit. It applies to the margins of WithMargins and the page
+// size of WithPageSize when it comes first, and to the page size given to NewPdfDocumentWithPageSize.
+func WithUnit(u Unit) Option {
+	return func(d *PdfDocument) {
+		d.SetUnit(u)
+	}
+}
+
+// ToUnit converts a length in points to the document's unit, so that a length in another unit can be given to a
+// page method, e.g. ToUnit(In(0.5)) in a document measured in millimetres
+func (d *PdfDocument) ToUnit(points float64) float64 {
+	return points / float64(d.unit)
+}
+
+// MM converts millimetres to points
+func MM(v float64) float64 {
+	return v * float64(UnitMM)
+}
+
+// CM converts centimetres to points
+func CM(v float64) float64 {
+	return v * float64(UnitCM)
+}
+
+// In converts inches to points
+func In(v float64) float64 {
+	return v * float64(UnitInch)
+}
+
+// pt converts a length in the document's unit to points
+func (p *PdfPage) pt(v float64) float64 {
+	return v * float64(p.document.unit)
+}
+
+// inUnit converts a length in points to the document's unit
+func (p *PdfPage) inUnit(points float64) float64 {
+	return p.document.ToUnit(points)
+}
@@ -0,0 +1,211 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// pdfHeader matches the header that starts a PDF file
+var pdfHeader = regexp.MustCompile(`^%PDF-(1\.[0-7]|2\.0)\r?\n`)
+
+// Validate writes the document as Bytes does and reads it back to check the structure of the file, returning
+// ErrInvalidDocument listing every problem found with the numbers of the objects involved. It checks the header and
+// the end of file marker, that each cross-reference entry points at the object it is for, that the Length of each
+// stream ends its data at endstream, that the trailer's Size is one more than the highest object number and its Root
+// is the catalog, that every reference is to an object in the file and that the Contents and Resources of every page
+// can be found. The objects packed in the object streams of an encrypted document can't be read, so they aren't
+// checked.
+func (d *PdfDocument) Validate() error {
+	data, err := d.Bytes()
+	if err != nil {
+		return err
+	}
+	return checkPDF(data)
+}
+
+// checkPDF returns ErrInvalidDocument listing the problems with the structure of the PDF file held in data, if any
+func checkPDF(data []byte) error {
+	if problems := validatePDF(data); len(problems) > 0 {
+		return fmt.Errorf("%w: %v", ErrInvalidDocument, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// validatePDF returns the problems with the structure of the PDF file held in data
+func validatePDF(data []byte) []string {
+	var problems []string
+	problem := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+	if !pdfHeader.Match(data) {
+		problem("the file doesn't start with a PDF header")
+	}
+	if !bytes.HasSuffix(bytes.TrimRight(data, "\r\n"), []byte("%%EOF")) {
+		problem("the file doesn't end with %%%%EOF")
+	}
+	pr, err := parsePDF(data)
+	if err != nil {
+		return append(problems, err.Error())
+	}
+	encrypted := pr.trailer.get("Encrypt") != nil
+	readable := func(num int) bool {
+		return !encrypted || pr.xref[num].stream == 0
+	}
+
+	nums := make([]int, 0, len(pr.xref))
+	for num := range pr.xref {
+		nums = append(nums, num)
+	}
+	sort.Ints(nums)
+	highest := 0
+	if len(nums) > 0 {
+		highest = nums[len(nums)-1]
+	}
+	if size, ok := pr.trailer.get("Size").(int); !ok {
+		problem("the trailer has no Size")
+	} else if size != highest+1 {
+		problem("the trailer Size is %v but the highest object number is %v", size, highest)
+	}
+	root, ok := pr.trailer.get("Root").(pdfRef)
+	if !ok {
+		problem("the trailer has no Root")
+	} else if catalog, _ := pr.resolve(root).(pdfDict); readable(root.num) && catalog.get("Type") != pdfName("Catalog") {
+		problem("the trailer Root, object %v, is not a catalog", root.num)
+	}
+	checkRefs := func(from string, v any) {
+		walkRefs(v, func(ref pdfRef) {
+			if e, ok := pr.xref[ref.num]; !ok || e.free {
+				problem("%v refers to object %v, which isn't in the file", from, ref.num)
+			}
+		})
+	}
+	checkRefs("the trailer", pr.trailer)
+
+	for _, num := range nums {
+		e := pr.xref[num]
+		if e.free || num == 0 {
+			continue
+		}
+		if !readable(num) {
+			continue
+		}
+		if e.stream == 0 {
+			if found, ok := objectNumberAt(data, e.offset); !ok || found != num {
+				problem("the cross-reference entry for object %v points at offset %v, where object %v isn't", num,
+					e.offset, num)
+				continue
+			}
+			if length, ok := pr.checkStreamLength(e.offset); !ok {
+				problem("the Length %v of stream object %v doesn't end at endstream", length, num)
+			}
+		}
+		v, err := pr.object(num)
+		if err != nil {
+			problem("object %v can't be read: %v", num, err)
+			continue
+		}
+		if e.stream != 0 {
+			if stm := pr.streams[e.stream]; stm == nil {
+				problem("object %v is in object %v, which isn't an object stream", num, e.stream)
+				continue
+			} else if _, ok := stm.offsets[num]; !ok {
+				problem("object %v isn't in object stream %v", num, e.stream)
+				continue
+			}
+		}
+		checkRefs(fmt.Sprintf("object %v", num), v)
+	}
+
+	if !ok || !readable(root.num) {
+		return problems
+	}
+	pages, err := pr.pages()
+	if err != nil {
+		return append(problems, err.Error())
+	}
+	for i, page := range pages {
+		contents := page.dict.get("Contents")
+		list, ok := pr.resolve(contents).([]any)
+		if !ok && contents != nil {
+			list = []any{contents}
+		}
+		for _, c := range list {
+			if _, ok := pr.resolve(c).(*pdfStream); !ok {
+				problem("the Contents of page %v, object %v, aren't a stream", i+1, page.num)
+			}
+		}
+		if _, ok := pr.resolve(page.dict.get("Resources")).(pdfDict); !ok {
+			problem("page %v, object %v, has no Resources", i+1, page.num)
+		}
+	}
+	return problems
+}
+
+// walkRefs calls f with every reference in a value read from a PDF file
+func walkRefs(v any, f func(ref pdfRef)) {
+	switch v := v.(type) {
+	case pdfRef:
+		f(v)
+	case []any:
+		for _, item := range v {
+			walkRefs(item, f)
+		}
+	case pdfDict:
+		for _, e := range v {
+			walkRefs(e.value, f)
+		}
+	case *pdfStream:
+		walkRefs(v.dict, f)
+	}
+}
+
+// objectNumberAt returns the number of the object that starts at offset in a PDF file, reporting false if no object
+// starts there
+func objectNumberAt(data []byte, offset int) (int, bool) {
+	if offset < 0 || offset >= len(data) || data[offset] < '0' || data[offset] > '9' {
+		return 0, false
+	}
+	p := &pdfParser{data: data, pos: offset}
+	num, ok1 := p.integer()
+	_, ok2 := p.integer()
+	return num, ok1 && ok2 && p.keyword() == "obj"
+}
+
+// checkStreamLength returns the Length of the stream object at offset and reports whether its data, after the end of
+// line that follows the stream keyword, is followed by an end of line and endstream. Objects that aren't streams are
+// reported as correct.
+func (pr *pdfReader) checkStreamLength(offset int) (any, bool) {
+	p := &pdfParser{data: pr.data, pos: offset}
+	p.integer()
+	p.integer()
+	p.keyword()
+	v, err := p.value()
+	dict, ok := v.(pdfDict)
+	if err != nil || !ok || p.keyword() != "stream" {
+		return nil, true
+	}
+	if bytes.HasPrefix(pr.data[p.pos:], []byte("\r\n")) {
+		p.pos += 2
+	} else if bytes.HasPrefix(pr.data[p.pos:], []byte("\n")) {
+		p.pos++
+	} else {
+		return dict.get("Length"), false
+	}
+	length := pr.resolve(dict.get("Length"))
+	n, ok := length.(int)
+	end := p.pos + n
+	if !ok || n < 0 || end > len(pr.data) {
+		return length, false
+	}
+	rest := pr.data[end:]
+	for _, eol := range []string{"\r\n", "\n", "\r"} {
+		if bytes.HasPrefix(rest, []byte(eol)) {
+			rest = rest[len(eol):]
+			break
+		}
+	}
+	return length, bytes.HasPrefix(rest, []byte("endstream"))
+}
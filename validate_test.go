@@ -0,0 +1,53 @@
+package gopdf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	if err := testDocument(t).Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValidateCorrupted(t *testing.T) {
+	d := testDocument(t)
+	d.SetCompression(false)
+	data, err := d.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// change the last digit of the Length of the first stream, so its data no longer ends at endstream
+	m := regexp.MustCompile(`(?s)(\d+) 0 obj\r\n<<[^>]*?/Length \d+`).FindSubmatchIndex(data)
+	if m == nil {
+		t.Fatal("no stream")
+	}
+	stream, _ := strconv.Atoi(string(data[m[2]:m[3]]))
+	data[m[1]-1] = '0' + (data[m[1]-1]-'0'+5)%10
+
+	// point the cross-reference entry of another object at the catalog
+	moved := 2
+	if stream == moved {
+		moved = 3
+	}
+	table := bytes.LastIndex(data, []byte("65535 f\r\n")) + len("65535 f\r\n")
+	catalog := bytes.Index(data, []byte("\r\n1 0 obj\r\n")) + 2
+	copy(data[table+20*(moved-1):], fmt.Sprintf("%010d", catalog))
+
+	err = checkPDF(data)
+	if !errors.Is(err, ErrInvalidDocument) {
+		t.Fatalf("corrupted file reported as %v", err)
+	}
+	for _, want := range []string{fmt.Sprintf("stream object %v ", stream), fmt.Sprintf("object %v points", moved)} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("%q not reported in %v", want, err)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// PageMode selects which panel a viewer shows when the document is opened
+type PageMode int
+
+// Page modes. PageModeAuto shows the bookmarks when the document has any.
+const (
+	PageModeAuto PageMode = iota
+	UseNone
+	UseOutlines
+	UseThumbs
+	FullScreen
+	UseOC
+	UseAttachments
+)
+
+var pageModeNames = [...]string{"", "UseNone", "UseOutlines", "UseThumbs", "FullScreen", "UseOC", "UseAttachments"}
+
+func (m PageMode) String() string {
+	if m <= PageModeAuto || m > UseAttachments {
+		return fmt.Sprintf("PageMode(%d)", int(m))
+	}
+	return pageModeNames[m]
+}
+
+// ZoomMode selects how a page is zoomed when the document is opened
+type ZoomMode int
+
+// Zoom modes. ZoomInherit keeps the viewer's current zoom.
+const (
+	ZoomInherit ZoomMode = iota
+	ZoomFitPage
+	ZoomFitWidth
+	ZoomFitHeight
+	ZoomActualSize
+)
+
+// ViewerPreferences control the window of a viewer showing the document
+type ViewerPreferences struct {
+	HideToolbar     bool
+	HideMenubar     bool
+	HideWindowUI    bool // hide scroll bars and navigation controls
+	FitWindow       bool // size the window to the first page
+	CenterWindow    bool
+	DisplayDocTitle bool // show the title set with SetTitle in the title bar instead of the file name
+	// NonFullScreenPageMode is the page mode to use on leaving full screen mode, one of UseNone, UseOutlines,
+	// UseThumbs or UseOC, or PageModeAuto for the viewer's default
+	NonFullScreenPageMode PageMode
+}
+
+// openAction is the page and zoom the document opens at
+type openAction struct {
+	page *PdfPage
+	zoom ZoomMode
+}
+
+// SetOpenAction makes the document open at the top of page, zoomed according to zoom
+func (d *PdfDocument) SetOpenAction(page *PdfPage, zoom ZoomMode) {
+	d.catalog.openAction = &openAction{page: page, zoom: zoom}
+}
+
+// SetPageMode sets which panel a viewer shows when the document is opened, or whether it opens in full screen mode
+func (d *PdfDocument) SetPageMode(mode PageMode) error {
+	if mode < PageModeAuto || mode > UseAttachments {
+		return fmt.Errorf("%w: page mode %v", ErrInvalidViewerPreferences, mode)
+	}
+	d.catalog.pageMode = mode
+	return nil
+}
+
+// SetViewerPreferences sets the preferences for the window of a viewer showing the document
+func (d *PdfDocument) SetViewerPreferences(prefs ViewerPreferences) error {
+	switch prefs.NonFullScreenPageMode {
+	case PageModeAuto, UseNone, UseOutlines, UseThumbs, UseOC:
+	default:
+		return fmt.Errorf("%w: page mode on leaving full screen mode can't be %v", ErrInvalidViewerPreferences,
+			prefs.NonFullScreenPageMode)
+	}
+	d.catalog.viewerPreferences = &prefs
+	return nil
+}
+
+// zoomDestination returns an explicit destination showing the top of page zoomed according to zoom
+func zoomDestination(page *PdfPage, zoom ZoomMode) string {
+	switch zoom {
+	case ZoomFitPage:
+		return fmt.Sprintf("[ %v /Fit ]", page.objectRef())
+	case ZoomFitWidth:
+		return fmt.Sprintf("[ %v /FitH %v ]", page.objectRef(), formatNumber(page.height))
+	case ZoomFitHeight:
+		return fmt.Sprintf("[ %v /FitV 0 ]", page.objectRef())
+	case ZoomActualSize:
+		return fmt.Sprintf("[ %v /XYZ 0 %v 1 ]", page.objectRef(), formatNumber(page.height))
+	}
+	return fmt.Sprintf("[ %v /XYZ null %v null ]", page.objectRef(), formatNumber(page.height))
+}
+
+// bytes returns the viewer preferences dictionary, with only the entries that differ from the defaults
+func (v ViewerPreferences) bytes() []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "<< ")
+	for _, pref := range []struct {
+		name string
+		set  bool
+	}{
+		{"HideToolbar", v.HideToolbar},
+		{"HideMenubar", v.HideMenubar},
+		{"HideWindowUI", v.HideWindowUI},
+		{"FitWindow", v.FitWindow},
+		{"CenterWindow", v.CenterWindow},
+		{"DisplayDocTitle", v.DisplayDocTitle},
+	} {
+		if pref.set {
+			fmt.Fprintf(&buf, "/%v true ", pref.name)
+		}
+	}
+	if v.NonFullScreenPageMode != PageModeAuto {
+		fmt.Fprintf(&buf, "/NonFullScreenPageMode /%v ", v.NonFullScreenPageMode)
+	}
+	fmt.Fprintf(&buf, ">>")
+	return buf.Bytes()
+}
@@ -0,0 +1,111 @@
+package gopdf
+
+import (
+	"fmt"
+	"math"
+)
+
+// watermarkName is the name of the watermark in the page resources, which can't be used for an image or template
+const watermarkName = "Watermark"
+
+// WatermarkOptions controls how a watermark is drawn. The zero value draws it horizontally in black at 72 points,
+// under the page content and 30% opaque.
+type WatermarkOptions struct {
+	Font     string  // name of a font added to the document, or empty for a core font of the document or Helvetica
+	FontSize float64 // size of text in points, or 0 for 72
+	Colour   Color   // colour of text, or nil for black
+	Opacity  float64 // up to 1 for opaque, or 0 for the default of 0.3
+	Angle    float64 // anticlockwise rotation in degrees
+	Width    float64 // width of an image in the document's unit, or 0 for its size in pixels
+	Over     bool    // draw over the page content instead of under it
+}
+
+// PdfWatermark is a form XObject centred on the origin that is drawn in the middle of every page
+type PdfWatermark struct {
+	PdfObject
+	over      bool
+	opacity   float64
+	radius    float64 // half the diagonal of the unrotated watermark, which bounds it at any angle
+	resources string
+	ops       string
+}
+
+// SetWatermarkText draws text in the middle of every page when the document is written, including pages added
+// afterwards. It replaces any watermark already set.
+func (d *PdfDocument) SetWatermarkText(text string, opts WatermarkOptions) error {
+	p := &PdfPage{fontSize: opts.FontSize, horizontalScaling: 100}
+	p.document = d
+	if p.fontSize == 0 {
+		p.fontSize = 72
+	}
+	if opts.Font == "" {
+		p.font = p.fieldFont()
+	} else if err := p.SetFont(opts.Font); err != nil {
+		return err
+	}
+	width := p.textWidth(text)
+	colour := opts.Colour
+	if colour == nil {
+		colour = GrayColor(0)
+	}
+	ops := fmt.Sprintf("BT\r\n%v %v Tf\r\n%v\r\n%v %v Td\r\n%v Tj\r\nET\r\n", formatName(p.font.name), formatNumber(p.fontSize),
+		colour.operator(false), formatNumber(-width/2), formatNumber(-0.35*p.fontSize), p.textString(text))
+	resources := fmt.Sprintf("/Font << %v %v >>", formatName(p.font.name), p.font.objectRef())
+	d.setWatermark(width, p.fontSize, resources, ops, opts)
+	return nil
+}
+
+// SetWatermarkImage draws a named image in the middle of every page when the document is written, including pages
+// added afterwards. It replaces any watermark already set.
+func (d *PdfDocument) SetWatermarkImage(name string, opts WatermarkOptions) error {
+	i, err := d.resources.image(name)
+	if err != nil {
+		return err
+	}
+	w, h := i.scaledSize(opts.Width*float64(d.unit), 0)
+	i.drawnAt(w, h)
+	ops := fmt.Sprintf("%v\r\n%v Do\r\n", i.placement(-w/2, -h/2, w, h), formatName(name))
+	resources := fmt.Sprintf("/XObject << %v %v >>", formatName(name), i.objectRef())
+	d.setWatermark(w, h, resources, ops, opts)
+	return nil
+}
+
+// setWatermark sets the watermark drawn by ops, which is w by h points centred on the origin before it is rotated
+func (d *PdfDocument) setWatermark(w, h float64, resources, ops string, opts WatermarkOptions) {
+	wm := d.resources.watermark
+	if wm == nil {
+		wm = new(PdfWatermark)
+		d.addObject(wm)
+		d.resources.watermark = wm
+	}
+	wm.over = opts.Over
+	wm.opacity = opts.Opacity
+	if wm.opacity == 0 {
+		wm.opacity = 0.3
+	}
+	wm.radius = math.Ceil(math.Hypot(w, h) / 2)
+	wm.resources = resources
+	wm.ops = "/GS0 gs\r\n"
+	if opts.Angle != 0 {
+		sin, cos := sinCos(opts.Angle)
+		wm.ops += fmt.Sprintf("%v %v %v %v 0 0 cm\r\n", formatNumber(cos), formatNumber(sin), formatNumber(-sin), formatNumber(cos))
+	}
+	wm.ops += ops
+}
+
+// placement returns the operators that draw the watermark in the middle of a page
+func (wm *PdfWatermark) placement(p *PdfPage) string {
+	return fmt.Sprintf("q\r\n1 0 0 1 %v %v cm\r\n%v Do\r\nQ\r\n", formatNumber(p.width/2),
+		formatNumber(p.height/2), formatName(watermarkName))
+}
+
+func (wm PdfWatermark) bytes() []byte {
+	r := wm.radius
+	entries := fmt.Sprintf("/Type /XObject\r\n/Subtype /Form\r\n/BBox [ %v %v %v %v ]\r\n", -r, -r, r, r)
+	entries += fmt.Sprintf("/Resources << %v /ExtGState << /GS0 << /Type /ExtGState /CA %v /ca %v >> >> >>\r\n",
+		wm.resources, formatNumber(wm.opacity), formatNumber(wm.opacity))
+	if wm.document.noCompression {
+		return streamObject(wm.id, entries, []byte(wm.ops))
+	}
+	return streamObject(wm.id, entries+"/Filter /FlateDecode\r\n", deflate([]byte(wm.ops)))
+}
@@ -0,0 +1,84 @@
+package gopdf
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// winAnsiHigh maps the characters in the 0x80 to 0x9F block of WinAnsiEncoding to their byte values.
+// The rest of the encoding matches ASCII and Latin-1.
+var winAnsiHigh = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87, 'ˆ': 0x88,
+	'‰': 0x89, 'Š': 0x8A, '‹': 0x8B, 'Œ': 0x8C, 'Ž': 0x8E, '‘': 0x91, '’': 0x92, '“': 0x93,
+	'”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '˜': 0x98, '™': 0x99, 'š': 0x9A, '›': 0x9B,
+	'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+// toWinAnsi returns the WinAnsiEncoding byte for r
+func toWinAnsi(r rune) (byte, bool) {
+	if r < 0x80 || (r >= 0xA0 && r <= 0xFF) {
+		return byte(r), true
+	}
+	b, ok := winAnsiHigh[r]
+	return b, ok
+}
+
+// encodeWinAnsi converts UTF-8 text to WinAnsiEncoding. Bytes that are not part of a valid UTF-8 sequence are
+// assumed to be encoded already and are copied unchanged. Runes with no WinAnsi equivalent are replaced by
+// replacement and the first of them is returned as unmapped.
+func encodeWinAnsi(text string, replacement byte) (encoded string, unmapped rune, ok bool) {
+	ok = true
+	var sb strings.Builder
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if r == utf8.RuneError && size <= 1 {
+			sb.WriteByte(text[i])
+			i++
+			continue
+		}
+		i += size
+		if b, found := toWinAnsi(r); found {
+			sb.WriteByte(b)
+			continue
+		}
+		if ok {
+			unmapped, ok = r, false
+		}
+		sb.WriteByte(replacement)
+	}
+	return sb.String(), unmapped, ok
+}
+
+// SetReplacementChar sets the character printed in place of characters that cannot be represented in the
+// WinAnsiEncoding used by the text fonts, or that are missing from a font added with AddUnicodeFont.
+// The default is '?'.
+func (d *PdfDocument) SetReplacementChar(r rune) error {
+	b, ok := toWinAnsi(r)
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnmappableRune, r)
+	}
+	d.replacement = b
+	d.replacementRune = r
+	return nil
+}
+
+// SetStrictEncoding controls what happens when text contains characters that cannot be represented in
+// WinAnsiEncoding. When strict is true the replacement character is still printed, but ErrUnmappableRune is
+// returned when the document is written.
+func (d *PdfDocument) SetStrictEncoding(strict bool) {
+	d.strictEncoding = strict
+}
+
+// encodeText converts text from UTF-8 to the encoding of the current font. Symbol and ZapfDingbats use their own
+// built in encodings so text for them is passed through unchanged, and Type0 fonts use two byte glyph ids.
+// record is set when the text is being output rather than measured.
+func (p *PdfPage) encodeText(text string, record bool) (string, rune, bool) {
+	switch {
+	case p.font == nil || p.font.encoding == "WinAnsiEncoding":
+		return encodeWinAnsi(text, p.document.replacement)
+	case p.font.unicode != nil:
+		return p.font.unicode.encode(text, p.document.replacementRune, record)
+	}
+	return text, 0, true
+}
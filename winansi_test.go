@@ -0,0 +1,60 @@
+package gopdf
+
+import (
+	"fmt"
+	"testing"
+)
+
+// cp1252High is the 0x80 to 0x9F block of Windows code page 1252, which WinAnsiEncoding follows, with 0 for the five
+// undefined codes
+var cp1252High = [32]rune{
+	'€', 0, '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', 0, 'Ž', 0,
+	0, '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', 0, 'ž', 'Ÿ',
+}
+
+func TestToWinAnsi(t *testing.T) {
+	type mapping struct {
+		r  rune
+		b  byte
+		ok bool
+	}
+	var cases []mapping
+	for r := rune(0); r < 0x80; r++ {
+		cases = append(cases, mapping{r, byte(r), true})
+	}
+	for r := rune(0xA0); r <= 0xFF; r++ {
+		cases = append(cases, mapping{r, byte(r), true})
+	}
+	for i, r := range cp1252High {
+		if r != 0 {
+			cases = append(cases, mapping{r, byte(0x80 + i), true})
+		}
+	}
+	// the C1 controls, which WinAnsiEncoding replaces with the characters above, and characters it doesn't have
+	for r := rune(0x80); r < 0xA0; r++ {
+		cases = append(cases, mapping{r, 0, false})
+	}
+	for _, r := range []rune{0x100, 'Ł', 'Ω', '←', '😀', 0xFFFD} {
+		cases = append(cases, mapping{r, 0, false})
+	}
+	for _, c := range cases {
+		if b, ok := toWinAnsi(c.r); ok != c.ok || ok && b != c.b {
+			t.Errorf("toWinAnsi(%U) = %#x, %v, want %#x, %v", c.r, b, ok, c.b, c.ok)
+		}
+	}
+}
+
+func TestEncodeWinAnsiUndefined(t *testing.T) {
+	for _, b := range []byte{0x81, 0x8D, 0x8F, 0x90, 0x9D} {
+		// the character a reader decoding the byte as Latin-1 would give
+		text := fmt.Sprintf("a%cb", rune(b))
+		encoded, unmapped, ok := encodeWinAnsi(text, '?')
+		if encoded != "a?b" || unmapped != rune(b) || ok {
+			t.Errorf("encodeWinAnsi(%q) = %q, %U, %v, want the replacement", text, encoded, unmapped, ok)
+		}
+	}
+	encoded, _, ok := encodeWinAnsi("€ café ™", '?')
+	if want := "\x80 caf\xE9 \x99"; encoded != want || !ok {
+		t.Errorf("encodeWinAnsi = %q, %v, want %q", encoded, ok, want)
+	}
+}
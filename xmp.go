@@ -0,0 +1,88 @@
+package gopdf
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"time"
+)
+
+// PdfMetadata is an XMP metadata stream holding the same metadata as the Info dictionary, for archiving systems
+// that read XMP
+type PdfMetadata struct {
+	PdfObject
+	info *PdfInfo
+}
+
+// SetXMPMetadata turns writing an XMP metadata stream on or off. The stream is built from the metadata set with
+// SetTitle, SetAuthor and the other Info setters.
+func (d *PdfDocument) SetXMPMetadata(enabled bool) {
+	switch {
+	case enabled && d.catalog.metadata == nil:
+		d.catalog.metadata = &PdfMetadata{info: d.info()}
+		d.addObject(d.catalog.metadata)
+	case !enabled && d.catalog.metadata != nil:
+		// the object stays in the document but nothing refers to it
+		d.catalog.metadata = nil
+	}
+}
+
+// xmlEscape escapes s for use as XML character data
+func xmlEscape(s string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
+
+// xmpDate formats t as an XMP date
+func xmpDate(t time.Time) string {
+	return t.Format("2006-01-02T15:04:05Z07:00")
+}
+
+// xmpPacket returns the XMP packet describing the document
+func (m PdfMetadata) xmpPacket() []byte {
+	i := m.info
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "<?xpacket begin=\"\uFEFF\" id=\"W5M0MpCehiHzreSzNTczkc9d\"?>\n")
+	fmt.Fprintf(&buf, "<x:xmpmeta xmlns:x=\"adobe:ns:meta/\">\n")
+	fmt.Fprintf(&buf, "<rdf:RDF xmlns:rdf=\"http://www.w3.org/1999/02/22-rdf-syntax-ns#\">\n")
+	fmt.Fprintf(&buf, "<rdf:Description rdf:about=\"\"\n")
+	fmt.Fprintf(&buf, "  xmlns:dc=\"http://purl.org/dc/elements/1.1/\"\n")
+	fmt.Fprintf(&buf, "  xmlns:xmp=\"http://ns.adobe.com/xap/1.0/\"\n")
+	fmt.Fprintf(&buf, "  xmlns:pdf=\"http://ns.adobe.com/pdf/1.3/\"\n")
+	fmt.Fprintf(&buf, "  xmlns:pdfaid=\"http://www.aiim.org/pdfa/ns/id/\">\n")
+	fmt.Fprintf(&buf, "<dc:format>application/pdf</dc:format>\n")
+	if i.title != "" {
+		fmt.Fprintf(&buf, "<dc:title><rdf:Alt><rdf:li xml:lang=\"x-default\">%v</rdf:li></rdf:Alt></dc:title>\n", xmlEscape(i.title))
+	}
+	if i.author != "" {
+		fmt.Fprintf(&buf, "<dc:creator><rdf:Seq><rdf:li>%v</rdf:li></rdf:Seq></dc:creator>\n", xmlEscape(i.author))
+	}
+	if i.subject != "" {
+		fmt.Fprintf(&buf, "<dc:description><rdf:Alt><rdf:li xml:lang=\"x-default\">%v</rdf:li></rdf:Alt></dc:description>\n", xmlEscape(i.subject))
+	}
+	if i.keywords != "" {
+		fmt.Fprintf(&buf, "<pdf:Keywords>%v</pdf:Keywords>\n", xmlEscape(i.keywords))
+	}
+	if i.creator != "" {
+		fmt.Fprintf(&buf, "<xmp:CreatorTool>%v</xmp:CreatorTool>\n", xmlEscape(i.creator))
+	}
+	fmt.Fprintf(&buf, "<xmp:CreateDate>%v</xmp:CreateDate>\n", xmpDate(i.created))
+	fmt.Fprintf(&buf, "<xmp:ModifyDate>%v</xmp:ModifyDate>\n", xmpDate(i.modified))
+	fmt.Fprintf(&buf, "<xmp:MetadataDate>%v</xmp:MetadataDate>\n", xmpDate(i.modified))
+	fmt.Fprintf(&buf, "<pdf:Producer>%v</pdf:Producer>\n", xmlEscape(i.producer))
+	if m.document.pdfA {
+		fmt.Fprintf(&buf, "<pdfaid:part>1</pdfaid:part>\n")
+		fmt.Fprintf(&buf, "<pdfaid:conformance>B</pdfaid:conformance>\n")
+	}
+	fmt.Fprintf(&buf, "</rdf:Description>\n")
+	fmt.Fprintf(&buf, "</rdf:RDF>\n")
+	fmt.Fprintf(&buf, "</x:xmpmeta>\n")
+	fmt.Fprintf(&buf, "<?xpacket end=\"w\"?>")
+	return buf.Bytes()
+}
+
+func (m PdfMetadata) bytes() []byte {
+	// left uncompressed so that tools which don't understand PDF can still find the packet
+	return streamObject(m.id, "/Type /Metadata\r\n/Subtype /XML\r\n", m.xmpPacket())
+}
@@ -0,0 +1,86 @@
+package gopdf
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// xmpProperties parses an XMP packet and returns the text of each property of its description, by namespace and
+// name, as an XMP reader would see it
+func xmpProperties(t *testing.T, packet []byte) map[string]string {
+	t.Helper()
+	const description = "http://www.w3.org/1999/02/22-rdf-syntax-ns# Description"
+	properties := map[string]string{}
+	var path []string
+	decoder := xml.NewDecoder(bytes.NewReader(packet))
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("the XMP packet isn't well formed: %v\n%s", err, packet)
+		}
+		switch token := token.(type) {
+		case xml.StartElement:
+			path = append(path, token.Name.Space+" "+token.Name.Local)
+		case xml.EndElement:
+			path = path[:len(path)-1]
+		case xml.CharData:
+			for i := 0; i+1 < len(path); i++ {
+				if path[i] == description {
+					properties[path[i+1]] += string(token)
+					break
+				}
+			}
+		}
+	}
+	for key, value := range properties {
+		properties[key] = strings.TrimSpace(value)
+	}
+	return properties
+}
+
+func TestXMPPacket(t *testing.T) {
+	const (
+		dc  = "http://purl.org/dc/elements/1.1/ "
+		xmp = "http://ns.adobe.com/xap/1.0/ "
+		pdf = "http://ns.adobe.com/pdf/1.3/ "
+	)
+	created := time.Date(2024, 5, 6, 7, 8, 9, 0, time.FixedZone("", 2*60*60))
+	d := NewPdfDocument()
+	d.SetDeterministic(created)
+	d.SetXMPMetadata(true)
+	fields := map[string]string{
+		dc + "title":        `Tom & Jerry <draft> "quoted" 'single'`,
+		dc + "creator":      "Ann & Bob Smith-Müller",
+		dc + "description":  "A ]]> section end, an &amp; and a <![CDATA[ start",
+		pdf + "Keywords":    "<script>alert(1)</script>, café, 日本語",
+		xmp + "CreatorTool": "tool\tv1.0 > v0.9",
+	}
+	d.SetTitle(fields[dc+"title"])
+	d.SetAuthor(fields[dc+"creator"])
+	d.SetSubject(fields[dc+"description"])
+	d.SetKeywords(fields[pdf+"Keywords"])
+	d.SetCreator(fields[xmp+"CreatorTool"])
+	fields[dc+"format"] = "application/pdf"
+	fields[xmp+"CreateDate"] = "2024-05-06T07:08:09+02:00"
+	fields[xmp+"ModifyDate"] = "2024-05-06T07:08:09+02:00"
+
+	got := xmpProperties(t, d.catalog.metadata.xmpPacket())
+	for key, want := range fields {
+		if got[key] != want {
+			t.Errorf("%v read back as %q, want %q", key, got[key], want)
+		}
+	}
+	if got[pdf+"Producer"] == "" {
+		t.Error("no producer")
+	}
+	if date, err := time.Parse(time.RFC3339, got[xmp+"CreateDate"]); err != nil || !date.Equal(created) {
+		t.Errorf("CreateDate %v read back as %v, %v", created, date, err)
+	}
+}